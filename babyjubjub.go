@@ -0,0 +1,35 @@
+package jubjub
+
+import "math/big"
+
+// Baby Jubjub, the twisted Edwards curve embedded in BN254
+// https://eips.ethereum.org/EIPS/eip-2494
+// Curve generator is the base point B8 of the prime order subgroup
+// and compressed points follow iden3 encoding.
+func NewBabyJubjub() *Curve {
+	field := NewField(bigFromStr16("0x30644e72e131a029b85045b68181585d2833e84879b9709143e1f593f0000001"))
+	a := fe(field, "0x0292fc")
+	d := fe(field, "0x0292f8")
+	generator := new(AffinePoint).NewPoint(field).SetCoordinates(
+		fe(field, "0x0bb77a6ad63e739b4eacb2e09d6277c12ab8d8010534e0b62893f3f6bb957051"),
+		fe(field, "0x25797203f7a0b24925572e1cd16bf9edfce0051fb9e133774b3c257a872d7d8b"))
+	order := bigFromStr16("0x060c89ce5c263405370a08b6d0302b0bab3eedb83920ee0a677297dc392126f1")
+	curve := mustNewTwistedEdwardsCurve(field, a, d, generator, order, big.NewInt(8))
+	curve.sign = signLexicographic
+	return curve
+}
+
+// Generator of the full group of order 8.l as in circomlib, B8 = 8.G
+func BabyJubjubGenerator(curve *Curve) *AffinePoint {
+	return new(AffinePoint).NewPoint(curve.field).SetCoordinates(
+		fe(curve.field, "0x023343e3445b673d38bcba38f25645adb494b1255b1162bb40f41a59f4d4b45e"),
+		fe(curve.field, "0x0c19139cb84c680a6e14116da06056174a0cfa121e6e5c2450f87d64fc000001"))
+}
+
+func NewBabyJubjubScalarField() *ScalarField {
+	q := bigFromStr16("0x060c89ce5c263405370a08b6d0302b0bab3eedb83920ee0a677297dc392126f1")
+	scalarField := &ScalarField{
+		q: q,
+	}
+	return scalarField
+}
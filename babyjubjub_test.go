@@ -0,0 +1,161 @@
+package jubjub
+
+import (
+	"encoding/hex"
+	"testing"
+)
+
+// test vectors are from circomlib test/babyjub.js
+
+func bjjPoint(curve *Curve, x, y string) *AffinePoint {
+	return new(AffinePoint).NewPoint(curve.field).SetCoordinates(
+		curve.field.NewElement(bigFromStr10(x).Bytes()),
+		curve.field.NewElement(bigFromStr10(y).Bytes()))
+}
+
+func TestBabyJubjubGenerator(t *testing.T) {
+	curve := NewBabyJubjub()
+	scalarField := NewBabyJubjubScalarField()
+	identity := curve.NewExtendedPoint()
+	g := BabyJubjubGenerator(curve)
+	if !curve.AffinePointIsOnCurve(g) {
+		t.Errorf("generator is not on curve")
+	}
+	r := curve.NewExtendedPoint()
+	curve.Mul(r, g.ToExtended(), scalarField.NewElementFromUint64(8))
+	if !r.Eq(curve.Generator()) {
+		t.Errorf("base point is expected to be 8.G")
+	}
+	if curve.IsInSubgroup(g.ToExtended()) {
+		t.Errorf("generator is not expected to be in prime order subgroup")
+	}
+	curve.MulBase(r, scalarField.NewElementFromBig(curve.Order()))
+	if !r.Eq(identity) {
+		t.Errorf("identity element expected")
+	}
+}
+
+func TestBabyJubjubParameters(t *testing.T) {
+	if err := NewBabyJubjub().checkOrder(); err != nil {
+		t.Errorf("bad baby jubjub parameters, %v", err)
+	}
+}
+
+func TestBabyJubjubAddition(t *testing.T) {
+	curve := NewBabyJubjub()
+	p1 := bjjPoint(curve,
+		"17777552123799933955779906779655732241715742912184938656739573121738514868268",
+		"2626589144620713026669568689430873010625803728049924121243784502389097019475")
+	p2 := bjjPoint(curve,
+		"16540640123574156134436876038791482806971768689494387082833631921987005038935",
+		"20819045374670962167435360035096875258406992893633759881276124905556507972311")
+	e1 := bjjPoint(curve,
+		"6890855772600357754907169075114257697580319025794532037257385534741338397365",
+		"4338620300185947561074059802482547481416142213883829469920100239455078257889")
+	e2 := bjjPoint(curve,
+		"7916061937171219682591368294088513039687205273691143098332585753343424131937",
+		"14035240266687799601661095864649209771790948434046947201833777492504781204499")
+	r := curve.NewExtendedPoint()
+	curve.Add(r, p1.ToExtended(), p1.ToExtended())
+	if !r.ToAffine().Eq(e1) {
+		t.Errorf("bad addition, have: %s, want: %s", r.ToAffine(), e1)
+	}
+	curve.Add(r, p1.ToExtended(), p2.ToExtended())
+	if !r.ToAffine().Eq(e2) {
+		t.Errorf("bad addition, have: %s, want: %s", r.ToAffine(), e2)
+	}
+	curve.Add(r, curve.NewExtendedPoint(), curve.NewExtendedPoint())
+	if !r.Eq(curve.NewExtendedPoint()) {
+		t.Errorf("identity element expected")
+	}
+}
+
+func TestBabyJubjubMultiplication(t *testing.T) {
+	curve := NewBabyJubjub()
+	scalarField := NewBabyJubjubScalarField()
+	p1 := bjjPoint(curve,
+		"17777552123799933955779906779655732241715742912184938656739573121738514868268",
+		"2626589144620713026669568689430873010625803728049924121243784502389097019475")
+	p2 := bjjPoint(curve,
+		"6890855772600357754907169075114257697580319025794532037257385534741338397365",
+		"4338620300185947561074059802482547481416142213883829469920100239455078257889")
+	vectors := []struct {
+		p    *AffinePoint
+		k    string
+		x, y string
+	}{
+		{p1, "3",
+			"19372461775513343691590086534037741906533799473648040012278229434133483800898",
+			"9458658722007214007257525444427903161243386465067105737478306991484593958249"},
+		{p1, "14035240266687799601661095864649209771790948434046947201833777492504781204499",
+			"17070357974431721403481313912716834497662307308519659060910483826664480189605",
+			"4014745322800118607127020275658861516666525056516280575712425373174125159339"},
+		{p2, "20819045374670962167435360035096875258406992893633759881276124905556507972311",
+			"13563888653650925984868671744672725781658357821216877865297235725727006259983",
+			"8442587202676550862664528699803615547505326611544120184665036919364004251662"},
+	}
+	for i, v := range vectors {
+		r := curve.NewExtendedPoint()
+		curve.Mul(r, v.p.ToExtended(), scalarField.NewElementFromBig(bigFromStr10(v.k)))
+		e := bjjPoint(curve, v.x, v.y)
+		if !r.ToAffine().Eq(e) {
+			t.Errorf("bad multiplication %d, have: %s, want: %s", i, r.ToAffine(), e)
+		}
+	}
+}
+
+func TestBabyJubjubInCurve(t *testing.T) {
+	curve := NewBabyJubjub()
+	p1 := bjjPoint(curve,
+		"17777552123799933955779906779655732241715742912184938656739573121738514868268",
+		"2626589144620713026669568689430873010625803728049924121243784502389097019475")
+	p2 := bjjPoint(curve,
+		"6890855772600357754907169075114257697580319025794532037257385534741338397365",
+		"4338620300185947561074059802482547481416142213883829469920100239455078257889")
+	if !curve.AffinePointIsOnCurve(p1) || !curve.AffinePointIsOnCurve(p2) {
+		t.Errorf("point is expected to be on curve")
+	}
+	if !curve.IsInSubgroup(p1.ToExtended()) {
+		t.Errorf("point is expected to be in subgroup")
+	}
+	curve.field.Add(p1.x, p1.x, curve.field.r1)
+	if curve.AffinePointIsOnCurve(p1) {
+		t.Errorf("point is not expected to be on curve")
+	}
+}
+
+func TestBabyJubjubCompression(t *testing.T) {
+	curve := NewBabyJubjub()
+	p := bjjPoint(curve,
+		"17777552123799933955779906779655732241715742912184938656739573121738514868268",
+		"2626589144620713026669568689430873010625803728049924121243784502389097019475")
+	e := toBytes("53b81ed5bffe9545b54016234682e7b2f699bd42a5e9eae27ff4051bc698ce85")
+	out := curve.Compress(p)
+	if hex.EncodeToString(out) != hex.EncodeToString(e) {
+		t.Errorf("bad compression, have: %x, want: %x", out, e)
+	}
+	q, err := curve.NewAffinePointFromCompressed(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !q.Eq(p) {
+		t.Errorf("bad decompression, have: %s, want: %s", q, p)
+	}
+}
+
+func TestBabyJubjubCompressionRandom(t *testing.T) {
+	curve := NewBabyJubjub()
+	scalarField := NewBabyJubjubScalarField()
+	for i := 0; i < 20; i++ {
+		r := curve.NewExtendedPoint()
+		curve.MulBase(r, randScalar(scalarField))
+		p := r.ToAffine()
+		q, err := curve.NewAffinePointFromCompressed(curve.Compress(p))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !q.Eq(p) {
+			t.Errorf("bad decompression, have: %s, want: %s", q, p)
+		}
+	}
+}
@@ -0,0 +1,56 @@
+package jubjub
+
+import "math/big"
+
+// Bandersnatch, a twisted Edwards curve over BLS12-381 scalar field
+// with an efficient degree 2 endomorphism
+// Bandersnatch: a fast elliptic curve built over the BLS12-381 scalar field
+// Masson, Sanso, Zhang https://eprint.iacr.org/2021/1152
+func NewBandersnatch() *Curve {
+	field := NewField(bigFromStr16("0x73eda753299d7d483339d80809a1d80553bda402fffe5bfeffffffff00000001"))
+	a := fe(field, "0x73eda753299d7d483339d80809a1d80553bda402fffe5bfefffffffefffffffc")
+	d := fe(field, "0x6389c12633c267cbc66e3bf86be3b6d8cb66677177e54f92b369f2f5188d58e7")
+	generator := new(AffinePoint).NewPoint(field).SetCoordinates(
+		fe(field, "0x29c132cc2c0b34c5743711777bbe42f32b79c022ad998465e1e71866a252ae18"),
+		fe(field, "0x2a6c669eda123e0f157d8b50badcd586358cad81eee464605e3167b6cc974166"))
+	order := bigFromStr16("0x1cfb69d4ca675f520cce760202687600ff8f87007419047174fd06b52876e7e1")
+	curve := mustNewTwistedEdwardsCurve(field, a, d, generator, order, big.NewInt(4))
+	lambda := bigFromStr16("0x13b4f3dc4a39a493edf849562b38c72bcfc49db970a5056ed13d21408783df05")
+	b := fe(field, "0x52c9f28b828426a561f00d3a63511a882ea712770d9af4d6ee0f014d172510b4")
+	c := fe(field, "0x6cc624cf865457c3a97c6efd6c17d1078456abcfff36f4e9515c806cdf650b3d")
+	psi := func(r, p *ExtendedPoint) {
+		bandersnatchEndomorphism(field, b, c, r, p)
+	}
+	curve.glv = newGLV(order, lambda, psi)
+	return curve
+}
+
+func NewBandersnatchScalarField() *ScalarField {
+	q := bigFromStr16("0x1cfb69d4ca675f520cce760202687600ff8f87007419047174fd06b52876e7e1")
+	scalarField := &ScalarField{
+		q: q,
+	}
+	return scalarField
+}
+
+// psi(x, y) = (f(y) / x.y, g(y) / h(y))
+// f(y) = c.(1 - y^2)
+// g(y) = b.(y^2 + b)
+// h(y) = y^2 - b
+// psi^2 = -2 and psi(P) = lambda.P in the prime order subgroup
+func bandersnatchEndomorphism(o *Field, b, c *FieldElement, r, p *ExtendedPoint) {
+	var f, g, h, xy, t FieldElement
+	o.Square(&t, p.z)
+	o.Square(&h, p.y)
+	o.Sub(&f, &t, &h)
+	o.Mul(&f, &f, c)
+	o.Mul(&t, &t, b)
+	o.Add(&g, &h, &t)
+	o.Mul(&g, &g, b)
+	o.Sub(&h, &h, &t)
+	o.Mul(&xy, p.x, p.y)
+	o.Mul(r.x, &f, &h)
+	o.Mul(r.y, &g, &xy)
+	o.Mul(r.z, &xy, &h)
+	o.Mul(r.t, &f, &g)
+}
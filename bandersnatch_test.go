@@ -0,0 +1,123 @@
+package jubjub
+
+import (
+	"math/big"
+	"testing"
+)
+
+func TestBandersnatchGenerator(t *testing.T) {
+	curve := NewBandersnatch()
+	scalarField := NewBandersnatchScalarField()
+	if !curve.ExtendedPointIsOnCurve(curve.Generator()) {
+		t.Errorf("generator is not on curve")
+	}
+	r := curve.NewExtendedPoint()
+	curve.Mul(r, curve.Generator(), scalarField.NewElementFromBig(curve.Order()))
+	if !r.Eq(curve.NewExtendedPoint()) {
+		t.Errorf("identity element expected")
+	}
+}
+
+func TestBandersnatchParameters(t *testing.T) {
+	if err := NewBandersnatch().checkOrder(); err != nil {
+		t.Errorf("bad bandersnatch parameters, %v", err)
+	}
+}
+
+func TestBandersnatchEndomorphism(t *testing.T) {
+	curve := NewBandersnatch()
+	scalarField := NewBandersnatchScalarField()
+	lambda := scalarField.NewElementFromBig(curve.glv.lambda)
+	for i := 0; i < 20; i++ {
+		p := curve.NewExtendedPoint()
+		curve.Mul(p, curve.Generator(), randScalar(scalarField))
+		r0, r1 := curve.NewExtendedPoint(), curve.NewExtendedPoint()
+		curve.glv.psi(r0, p)
+		if !curve.ExtendedPointIsOnCurve(r0) {
+			t.Fatalf("endomorphism image is not on curve")
+		}
+		curve.Mul(r1, p, lambda)
+		if !r0.Eq(r1) {
+			t.Fatalf("psi(P) is expected to be lambda.P")
+		}
+	}
+}
+
+func TestBandersnatchDecomposition(t *testing.T) {
+	curve := NewBandersnatch()
+	scalarField := NewBandersnatchScalarField()
+	g := curve.glv
+	for i := 0; i < 1000; i++ {
+		k := randScalar(scalarField).n
+		k1, k2 := g.decompose(k)
+		if k1.BitLen() > 129 || k2.BitLen() > 129 {
+			t.Fatalf("decomposition is not balanced, %d %d", k1.BitLen(), k2.BitLen())
+		}
+		r := new(big.Int).Mul(k2, g.lambda)
+		r.Add(r, k1).Sub(r, k).Mod(r, curve.Order())
+		if r.Sign() != 0 {
+			t.Fatalf("bad decomposition")
+		}
+	}
+}
+
+func TestBandersnatchMulSubgroup(t *testing.T) {
+	curve := NewBandersnatch()
+	scalarField := NewBandersnatchScalarField()
+	scalars := []*ScalarFieldElement{
+		scalarField.NewElementFromUint64(0),
+		scalarField.NewElementFromUint64(1),
+		scalarField.NewElementFromBig(new(big.Int).Sub(curve.Order(), big.NewInt(1))),
+	}
+	for i := 0; i < 50; i++ {
+		scalars = append(scalars, randScalar(scalarField))
+	}
+	for i, s := range scalars {
+		// random point of the subgroup
+		p := curve.NewExtendedPoint()
+		curve.Mul(p, curve.Generator(), randScalar(scalarField))
+		r0, r1 := curve.NewExtendedPoint(), curve.NewExtendedPoint()
+		curve.Mul(r0, p, s)
+		curve.MulSubgroup(r1, p, s)
+		if !r0.Eq(r1) {
+			t.Fatalf("bad multiplication %d, have: %s, want: %s", i, r1.ToAffine(), r0.ToAffine())
+		}
+		curve.Mul(r0, curve.Generator(), s)
+		curve.MulBase(r1, s)
+		if !r0.Eq(r1) {
+			t.Fatalf("bad base multiplication %d", i)
+		}
+	}
+	r := curve.NewExtendedPoint()
+	curve.MulSubgroup(r, curve.NewExtendedPoint(), scalars[3])
+	if !r.Eq(curve.NewExtendedPoint()) {
+		t.Errorf("identity element expected")
+	}
+}
+
+// Multiplication of a subgroup point other than the generator
+func benchmarkBandersnatchPoint() (*Curve, *ExtendedPoint, *ScalarFieldElement) {
+	curve := NewBandersnatch()
+	scalarField := NewBandersnatchScalarField()
+	p := curve.NewExtendedPoint()
+	curve.Mul(p, curve.generator, randScalar(scalarField))
+	return curve, p, randScalar(scalarField)
+}
+
+func BenchmarkBandersnatchMultiplication(t *testing.B) {
+	curve, p, s := benchmarkBandersnatchPoint()
+	r := curve.NewExtendedPoint()
+	t.ResetTimer()
+	for i := 0; i < t.N; i++ {
+		curve.Mul(r, p, s)
+	}
+}
+
+func BenchmarkBandersnatchMultiplicationSubgroup(t *testing.B) {
+	curve, p, s := benchmarkBandersnatchPoint()
+	r := curve.NewExtendedPoint()
+	t.ResetTimer()
+	for i := 0; i < t.N; i++ {
+		curve.MulSubgroup(r, p, s)
+	}
+}
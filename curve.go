@@ -1,12 +1,137 @@
 package jubjub
 
-import "fmt"
+import (
+	"fmt"
+	"math/big"
+)
 
+// Twisted Edwards curve a.x^2 + y^2 = 1 + d.x^2.y^2
 type Curve struct {
+	a         *FieldElement
 	d         *FieldElement
 	twoD      *FieldElement
 	field     *Field
 	generator *ExtendedPoint
+	order     *big.Int
+	cofactor  *big.Int
+	// a = -1 allows faster addition and doubling formulas
+	aIsMinusOne bool
+	sign        signConvention
+	// efficient endomorphism if the curve has one
+	glv *glv
+}
+
+// Compressed point is the little endian encoding of y
+// where the most significant bit carries the sign of x
+type signConvention int
+
+const (
+	// x is odd, as in Zcash
+	signOdd signConvention = iota
+	// x > (p-1)/2, as in iden3
+	signLexicographic
+)
+
+// Given curve parameters a and d in Montgomery domain,
+// generator of the prime order subgroup
+// and order of that subgroup with the curve cofactor.
+// The order is required to be prime with [order]G = O and
+// cofactor.order to be the number of points of the curve.
+func NewTwistedEdwardsCurve(field *Field, a, d *FieldElement, generator *AffinePoint, order, cofactor *big.Int) (*Curve, error) {
+	e, err := newTwistedEdwardsCurve(field, a, d, generator, order, cofactor)
+	if err != nil {
+		return nil, err
+	}
+	if err := e.checkOrder(); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+// Curves with known parameters skip the order check, it is run in tests
+func mustNewTwistedEdwardsCurve(field *Field, a, d *FieldElement, generator *AffinePoint, order, cofactor *big.Int) *Curve {
+	e, err := newTwistedEdwardsCurve(field, a, d, generator, order, cofactor)
+	if err != nil {
+		panic(err)
+	}
+	return e
+}
+
+func newTwistedEdwardsCurve(field *Field, a, d *FieldElement, generator *AffinePoint, order, cofactor *big.Int) (*Curve, error) {
+	if a.IsZero() || d.IsZero() || a.Eq(d) {
+		return nil, fmt.Errorf("bad curve parameters")
+	}
+	minusOne := new(FieldElement)
+	field.Neg(minusOne, field.r1)
+	twoD := new(FieldElement)
+	field.Double(twoD, d)
+	e := &Curve{
+		a:           new(FieldElement).Set(a),
+		d:           new(FieldElement).Set(d),
+		twoD:        twoD,
+		field:       field,
+		order:       new(big.Int).Set(order),
+		cofactor:    new(big.Int).Set(cofactor),
+		aIsMinusOne: a.Eq(minusOne),
+	}
+	g := new(AffinePoint).Set(generator)
+	g.field = field
+	if !e.AffinePointIsOnCurve(g) {
+		return nil, fmt.Errorf("generator is not on curve")
+	}
+	e.generator = g.ToExtended()
+	return e, nil
+}
+
+func (e *Curve) checkOrder() error {
+	if e.order.Sign() <= 0 || e.cofactor.Sign() <= 0 || !e.order.ProbablyPrime(20) {
+		return fmt.Errorf("bad subgroup order")
+	}
+	identity := e.NewExtendedPoint()
+	if e.generator.Eq(identity) || !e.IsInSubgroup(e.generator) {
+		return fmt.Errorf("generator is not of the subgroup order")
+	}
+	// Hasse bound |p + 1 - N| <= 2 sqrt(p) for N = cofactor.order
+	n := new(big.Int).Mul(e.order, e.cofactor)
+	t := new(big.Int).Add(e.field.Modulus(), big.NewInt(1))
+	t.Sub(t, n)
+	t.Mul(t, t)
+	if t.Cmp(new(big.Int).Lsh(e.field.Modulus(), 2)) > 0 {
+		return fmt.Errorf("bad number of points")
+	}
+	// the order of a point not in the subgroup divides N
+	in := make([]byte, 32)
+	for c := 2; c < 256; c++ {
+		in[0] = byte(c)
+		p, err := e.NewExtendedPointFromCompressed(in)
+		if err != nil {
+			continue
+		}
+		r := e.NewExtendedPoint()
+		e.Mul(r, p, &ScalarFieldElement{n: n})
+		if !r.Eq(identity) {
+			return fmt.Errorf("bad number of points")
+		}
+		return nil
+	}
+	return fmt.Errorf("no point to check the number of points")
+}
+
+func (e *Curve) Field() *Field {
+	return e.field
+}
+
+// Returns the order of the prime order subgroup
+func (e *Curve) Order() *big.Int {
+	return new(big.Int).Set(e.order)
+}
+
+func (e *Curve) Cofactor() *big.Int {
+	return new(big.Int).Set(e.cofactor)
+}
+
+func (e *Curve) Generator() *ExtendedPoint {
+	return new(ExtendedPoint).Set(e.generator)
 }
 
 func (e *Curve) NewAffinePoint() *AffinePoint {
@@ -34,10 +159,70 @@ func (e *Curve) NewAffinePointFromUncompressed(in []byte) (*AffinePoint, error)
 	return point, nil
 }
 
-// TODO
-// require square root implementation
-// func (e *Curve) NewAffinePointFromCompressed(in []byte) *AffinePoint, error {
-// }
+func (e *Curve) NewAffinePointFromCompressed(in []byte) (*AffinePoint, error) {
+	if len(in) != 32 {
+		return nil, fmt.Errorf("bad compressed point input size")
+	}
+	be := make([]byte, 32)
+	for i := 0; i < 32; i++ {
+		be[i] = in[31-i]
+	}
+	sign := be[0]>>7 == 1
+	be[0] &= 0x7f
+	y := new(FieldElement).Unmarshal(be)
+	if y.Cmp(e.field.p) >= 0 {
+		return nil, fmt.Errorf("non canonical y coordinate")
+	}
+	o := e.field
+	o.Mul(y, y, o.r2)
+	// x^2 = (1 - y^2) / (a - d.y^2)
+	var u, v FieldElement
+	o.Square(&v, y)
+	o.Sub(&u, o.r1, &v)
+	o.Mul(&v, &v, e.d)
+	o.Sub(&v, e.a, &v)
+	if v.IsZero() {
+		return nil, fmt.Errorf("point is not on curve")
+	}
+	o.InvMontUp(&v, &v)
+	o.Mul(&u, &u, &v)
+	x := new(FieldElement)
+	if !o.Sqrt(x, &u) {
+		return nil, fmt.Errorf("point is not on curve")
+	}
+	if x.IsZero() && sign {
+		return nil, fmt.Errorf("non canonical x sign")
+	}
+	if e.xSign(x) != sign {
+		o.Neg(x, x)
+	}
+	return new(AffinePoint).NewPoint(o).SetCoordinates(x, y), nil
+}
+
+// Returns the 32 bytes compressed encoding of p
+func (e *Curve) Compress(p *AffinePoint) []byte {
+	be := e.field.ToBytes(p.y)
+	out := make([]byte, 32)
+	for i := 0; i < 32; i++ {
+		out[i] = be[31-i]
+	}
+	if e.xSign(p.x) {
+		out[31] |= 0x80
+	}
+	return out
+}
+
+func (e *Curve) xSign(x *FieldElement) bool {
+	c := new(FieldElement)
+	e.field.Mul(c, x, &FieldElement{1, 0, 0, 0})
+	if e.sign == signLexicographic {
+		// x > (p-1)/2
+		h := new(FieldElement).Set(e.field.p)
+		h.rightShift(0)
+		return c.Cmp(h) > 0
+	}
+	return !c.IsEven()
+}
 
 func (e *Curve) NewExtendedPointFromUncompressed(in []byte) (*ExtendedPoint, error) {
 	if len(in) != 64 {
@@ -52,15 +237,26 @@ func (e *Curve) NewExtendedPointFromUncompressed(in []byte) (*ExtendedPoint, err
 	return point.ToExtended(), nil
 }
 
-// TODO
-// require square root implementation
-// func (e *Curve) NewExtendedPointFromCompressed(in []byte) *ExtendedPoint, error {
-// }
+func (e *Curve) NewExtendedPointFromCompressed(in []byte) (*ExtendedPoint, error) {
+	point, err := e.NewAffinePointFromCompressed(in)
+	if err != nil {
+		return nil, err
+	}
+	return point.ToExtended(), nil
+}
+
+func (e *Curve) Add(r *ExtendedPoint, p *ExtendedPoint, q *ExtendedPoint) {
+	if e.aIsMinusOne {
+		e.addMinusOne(r, p, q)
+		return
+	}
+	e.add(r, p, q)
+}
 
 // add-2008-hwcd-3
 // http://www.hyperelliptic.org/EFD/g1p/auto-twisted-extended-1
 // 2008 Hisil–Wong–Carter–Dawson, http://eprint.iacr.org/2008/522, Section 3.1.
-func (e *Curve) Add(r *ExtendedPoint, p *ExtendedPoint, q *ExtendedPoint) {
+func (e *Curve) addMinusOne(r *ExtendedPoint, p *ExtendedPoint, q *ExtendedPoint) {
 	o := e.field
 	var t1, t2, t3, t4, t5, t6 FieldElement
 	o.Sub(&t1, p.y, p.x)
@@ -83,8 +279,43 @@ func (e *Curve) Add(r *ExtendedPoint, p *ExtendedPoint, q *ExtendedPoint) {
 	o.Mul(r.z, &t2, &t4)
 }
 
+// add-2008-hwcd
+// http://www.hyperelliptic.org/EFD/g1p/auto-twisted-extended.html
+// 2008 Hisil–Wong–Carter–Dawson, http://eprint.iacr.org/2008/522, Section 3.1.
+func (e *Curve) add(r *ExtendedPoint, p *ExtendedPoint, q *ExtendedPoint) {
+	o := e.field
+	var t1, t2, t3, t4, t5, t6 FieldElement
+	o.Mul(&t1, p.x, q.x)
+	o.Mul(&t2, p.y, q.y)
+	o.Mul(&t3, p.t, e.d)
+	o.Mul(&t3, &t3, q.t)
+	o.Mul(&t4, p.z, q.z)
+	o.Add(&t5, p.x, p.y)
+	o.Add(&t6, q.x, q.y)
+	o.Mul(&t5, &t5, &t6)
+	o.Sub(&t5, &t5, &t1)
+	o.Sub(&t5, &t5, &t2)
+	o.Sub(&t6, &t4, &t3)
+	o.Add(&t4, &t4, &t3)
+	o.Mul(&t1, &t1, e.a)
+	o.Sub(&t3, &t2, &t1)
+	o.Mul(r.x, &t5, &t6)
+	o.Mul(r.y, &t4, &t3)
+	o.Mul(r.t, &t5, &t3)
+	o.Mul(r.z, &t6, &t4)
+}
+
+// q is left unchanged
 func (e *Curve) Sub(r *ExtendedPoint, p *ExtendedPoint, q *ExtendedPoint) {
-	e.Add(r, p, q.Neg())
+	e.Add(r, p, new(ExtendedPoint).Set(q).Neg())
+}
+
+func (e *Curve) double(r *ExtendedPoint, p *projectivePoint) {
+	if e.aIsMinusOne {
+		e.doubleMinusOne(r, p)
+		return
+	}
+	e.doubleGeneric(r, p)
 }
 
 // dbl-2008-bbjlp
@@ -92,7 +323,7 @@ func (e *Curve) Sub(r *ExtendedPoint, p *ExtendedPoint, q *ExtendedPoint) {
 // 2008 Bernstein–Birkner–Joye–Lange–Peters http://eprint.iacr.org/2008/013 Section 6
 // reduced version
 // https://github.com/zkcrypto/jubjub/blob/master/src/lib.rs
-func (e *Curve) double(r *ExtendedPoint, p *projectivePoint) {
+func (e *Curve) doubleMinusOne(r *ExtendedPoint, p *projectivePoint) {
 	o := e.field
 	var t1, t2, t3, t4 FieldElement
 	o.Add(&t1, p.x, p.y)
@@ -111,6 +342,30 @@ func (e *Curve) double(r *ExtendedPoint, p *projectivePoint) {
 	o.Mul(r.t, &t1, &t4)
 }
 
+// dbl-2008-bbjlp for arbitrary a
+// x3 = 2xy / (a.x^2 + y^2)
+// y3 = (y^2 - a.x^2) / (2 - a.x^2 - y^2)
+func (e *Curve) doubleGeneric(r *ExtendedPoint, p *projectivePoint) {
+	o := e.field
+	var t1, t2, t3, t4 FieldElement
+	o.Add(&t1, p.x, p.y)
+	o.Square(&t1, &t1)
+	o.Square(&t2, p.x)
+	o.Square(&t3, p.y)
+	o.Sub(&t1, &t1, &t2)
+	o.Sub(&t1, &t1, &t3)
+	o.Mul(&t2, &t2, e.a)
+	o.Sub(&t4, &t3, &t2)
+	o.Add(&t2, &t3, &t2)
+	o.Square(&t3, p.z)
+	o.Double(&t3, &t3)
+	o.Sub(&t3, &t3, &t2)
+	o.Mul(r.x, &t1, &t3)
+	o.Mul(r.y, &t2, &t4)
+	o.Mul(r.z, &t2, &t3)
+	o.Mul(r.t, &t1, &t4)
+}
+
 func (c *Curve) AddBase(r *ExtendedPoint, p *ExtendedPoint) {
 	c.Add(r, c.generator, p)
 }
@@ -130,18 +385,56 @@ func (e *Curve) Mul(r *ExtendedPoint, p *ExtendedPoint, a *ScalarFieldElement) {
 }
 
 func (e *Curve) MulBase(r *ExtendedPoint, s *ScalarFieldElement) {
-	e.Mul(r, e.generator, s)
+	e.MulSubgroup(r, e.generator, s)
+}
+
+// Checks whether p is in the prime order subgroup
+func (e *Curve) IsInSubgroup(p *ExtendedPoint) bool {
+	r := e.NewExtendedPoint()
+	e.Mul(r, p, &ScalarFieldElement{n: e.order})
+	return r.Eq(e.NewExtendedPoint())
 }
 
 func (e *Curve) AffinePointIsOnCurve(p *AffinePoint) bool {
-	return p.IsOnCurve(e.d)
+	if e.aIsMinusOne {
+		return p.IsOnCurve(e.d)
+	}
+	return p.isOnCurve(e.a, e.d)
 }
 
 func (e *Curve) ExtendedPointIsOnCurve(p *ExtendedPoint) bool {
-	return p.IsOnCurve(e.d)
+	if e.aIsMinusOne {
+		return p.IsOnCurve(e.d)
+	}
+	return p.isOnCurve(e.a, e.d)
 }
 
-// TODO
-// requires field square root
-// func (c *Curve) RandomPoint() *AffinePoint {
-// }
+// Montgomery's simultaneous inversion, converts all points with a single field inversion
+// Guide to Elliptic Curve Cryptography, Hankerson, Menezes, Vanstone
+// 2.26 Algorithm Simultaneous inversion
+func (e *Curve) BatchToAffine(points []*ExtendedPoint) []*AffinePoint {
+	n := len(points)
+	out := make([]*AffinePoint, n)
+	if n == 0 {
+		return out
+	}
+	acc := make([]FieldElement, n)
+	acc[0].Set(points[0].z)
+	for i := 1; i < n; i++ {
+		e.field.Mul(&acc[i], &acc[i-1], points[i].z)
+	}
+	var inv, zinv FieldElement
+	e.field.InvMontUp(&inv, &acc[n-1])
+	for i := n - 1; i >= 0; i-- {
+		if i > 0 {
+			e.field.Mul(&zinv, &inv, &acc[i-1])
+			e.field.Mul(&inv, &inv, points[i].z)
+		} else {
+			zinv.Set(&inv)
+		}
+		out[i] = new(AffinePoint).NewPoint(e.field)
+		e.field.Mul(out[i].x, &zinv, points[i].x)
+		e.field.Mul(out[i].y, &zinv, points[i].y)
+	}
+	return out
+}
@@ -219,8 +219,8 @@ func TestCurveMultiplication(t *testing.T) {
 	for i := 0; i < 1; i++ {
 		ge2 := curve.NewExtendedPoint()
 		ge3 := curve.NewExtendedPoint()
-		s1 := scalarField.NewRandElement()
-		s2 := scalarField.NewRandElement()
+		s1 := randScalar(scalarField)
+		s2 := randScalar(scalarField)
 		s3 := scalarField.NewElement()
 		scalarField.Mul(s3, s1, s2)
 		curve.Mul(ge2, ge1, s1)
@@ -246,8 +246,8 @@ func TestCurveAddition(t *testing.T) {
 		ge1 := curve.NewExtendedPoint()
 		ge2 := curve.NewExtendedPoint()
 		ge3 := curve.NewExtendedPoint()
-		s1 := scalarField.NewRandElement()
-		s2 := scalarField.NewRandElement()
+		s1 := randScalar(scalarField)
+		s2 := randScalar(scalarField)
 		s3 := scalarField.NewElement()
 		scalarField.Add(s3, s1, s2)
 		curve.Mul(ge1, ge, s1)
@@ -260,6 +260,84 @@ func TestCurveAddition(t *testing.T) {
 	}
 }
 
+func TestCurveSubtraction(t *testing.T) {
+	curve := NewJubjub()
+	scalarField := NewJubjubScalarField()
+	ge := curve.Generator()
+	for i := 0; i < 10; i++ {
+		ge1 := curve.NewExtendedPoint()
+		ge2 := curve.NewExtendedPoint()
+		ge3 := curve.NewExtendedPoint()
+		curve.Mul(ge1, ge, randScalar(scalarField))
+		curve.Mul(ge2, ge, randScalar(scalarField))
+		curve.Add(ge3, ge1, ge2)
+		ge4 := new(ExtendedPoint).Set(ge2)
+		curve.Sub(ge3, ge3, ge2)
+		if !ge3.Eq(ge1) {
+			t.Errorf("bad subtraction")
+		}
+		if !ge2.Eq(ge4) {
+			t.Errorf("subtrahend is modified")
+		}
+		curve.Sub(ge4, ge4, ge4)
+		if !ge4.Eq(curve.NewExtendedPoint()) {
+			t.Errorf("bad subtraction of a point from itself")
+		}
+	}
+}
+
+func TestCurveBatchToAffine(t *testing.T) {
+	curve := NewJubjub()
+	scalarField := NewJubjubScalarField()
+	points := make([]*ExtendedPoint, 10)
+	for i := range points {
+		points[i] = curve.NewExtendedPoint()
+		if i > 0 {
+			curve.Mul(points[i], curve.Generator(), randScalar(scalarField))
+		}
+	}
+	affine := curve.BatchToAffine(points)
+	for i, p := range points {
+		if !affine[i].Eq(p.ToAffine()) {
+			t.Errorf("bad batch affine conversion %d", i)
+		}
+	}
+	if len(curve.BatchToAffine(nil)) != 0 {
+		t.Errorf("expected no points")
+	}
+}
+
+func TestCurveMultiExp(t *testing.T) {
+	curve := NewJubjub()
+	scalarField := NewJubjubScalarField()
+	for _, n := range []int{0, 1, 2, 7, 64} {
+		points := make([]*ExtendedPoint, n)
+		scalars := make([]*ScalarFieldElement, n)
+		expected, p := curve.NewExtendedPoint(), curve.NewExtendedPoint()
+		for i := range points {
+			points[i] = curve.NewExtendedPoint()
+			curve.Mul(points[i], curve.Generator(), randScalar(scalarField))
+			scalars[i] = randScalar(scalarField)
+			if i == 1 {
+				// unreduced scalar
+				scalars[i].n.Lsh(scalars[i].n, 8)
+			}
+			curve.Mul(p, points[i], scalars[i])
+			curve.Add(expected, expected, p)
+		}
+		r := curve.NewExtendedPoint()
+		if err := curve.MultiExp(r, points, scalars); err != nil {
+			t.Fatal(err)
+		}
+		if !r.Eq(expected) {
+			t.Errorf("bad multi exponentiation of %d points", n)
+		}
+	}
+	if err := curve.MultiExp(curve.NewExtendedPoint(), []*ExtendedPoint{curve.Generator()}, nil); err == nil {
+		t.Errorf("expected error for length mismatch")
+	}
+}
+
 func TestCurveOrder(t *testing.T) {
 	curve := NewJubjub()
 	field := curve.field
@@ -459,10 +537,200 @@ func BenchmarkCurveMultiplication(t *testing.B) {
 		x:     fe(field, "0x11dafe5d23e1218086a365b99fbf3d3be72f6afd7d1f72623e6b071492d1122b"),
 		y:     fe(field, "0x1d523cf1ddab1a1793132e78c866c0c33e26ba5cc220fed7cc3f870e59d292aa"),
 	}
-	s := scalarField.NewRandElement()
+	s := randScalar(scalarField)
 	ge := ga.ToExtended()
 	t.ResetTimer()
 	for i := 0; i < t.N; i++ {
 		curve.Mul(ge, ge, s)
 	}
 }
+
+func TestCurveJubjubGenerator(t *testing.T) {
+	curve := NewJubjub()
+	scalarField := NewJubjubScalarField()
+	identity := curve.NewExtendedPoint()
+	g := curve.NewExtendedPoint()
+	curve.MulBase(g, scalarField.NewElementFromBig(curve.Order()))
+	if !g.Eq(identity) {
+		t.Errorf("identity element expected")
+	}
+	curve.MulBase(g, scalarField.NewElementFromUint64(1))
+	if g.Eq(identity) || !curve.ExtendedPointIsOnCurve(g) {
+		t.Errorf("bad generator")
+	}
+}
+
+func TestCurveGenericArithmetic(t *testing.T) {
+	curve := NewJubjub()
+	generic := *curve
+	generic.aIsMinusOne = false
+	scalarField := NewJubjubScalarField()
+	g := curve.Generator()
+	for i := 0; i < 10; i++ {
+		s1 := randScalar(scalarField)
+		s2 := randScalar(scalarField)
+		p1, p2 := curve.NewExtendedPoint(), curve.NewExtendedPoint()
+		q1, q2 := curve.NewExtendedPoint(), curve.NewExtendedPoint()
+		curve.Mul(p1, g, s1)
+		generic.Mul(p2, g, s1)
+		if !p1.Eq(p2) {
+			t.Errorf("generic multiplication fails")
+		}
+		curve.Mul(q1, g, s2)
+		generic.Mul(q2, g, s2)
+		curve.Add(p1, p1, q1)
+		generic.Add(p2, p2, q2)
+		if !p1.Eq(p2) {
+			t.Errorf("generic addition fails")
+		}
+		if !generic.ExtendedPointIsOnCurve(p2) || !generic.AffinePointIsOnCurve(p2.ToAffine()) {
+			t.Errorf("point is not on curve")
+		}
+	}
+}
+
+func TestCurveTwistedEdwardsIsomorphism(t *testing.T) {
+	// (x, y) -> (x/s, y) maps -x^2 + y^2 = 1 + d.x^2.y^2
+	// onto -s^2.x^2 + y^2 = 1 + d.s^2.x^2.y^2
+	jubjub := NewJubjub()
+	field := jubjub.field
+	scalarField := NewJubjubScalarField()
+	s := fe(field, "0x03")
+	sInv, s2 := new(FieldElement), new(FieldElement)
+	field.InvMontUp(sInv, s)
+	field.Square(s2, s)
+	a, d := new(FieldElement), new(FieldElement)
+	field.Neg(a, s2)
+	field.Mul(d, jubjub.d, s2)
+	g := jubjub.Generator().ToAffine()
+	field.Mul(g.x, g.x, sInv)
+	curve, err := NewTwistedEdwardsCurve(field, a, d, g, jubjub.Order(), jubjub.Cofactor())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if curve.aIsMinusOne {
+		t.Errorf("generic formulas are expected")
+	}
+	identity := curve.NewExtendedPoint()
+	r := curve.NewExtendedPoint()
+	curve.MulBase(r, scalarField.NewElementFromBig(curve.Order()))
+	if !r.Eq(identity) {
+		t.Errorf("identity element expected")
+	}
+	for i := 0; i < 10; i++ {
+		k := randScalar(scalarField)
+		r1, r2 := curve.NewExtendedPoint(), jubjub.NewExtendedPoint()
+		curve.MulBase(r1, k)
+		jubjub.MulBase(r2, k)
+		if !curve.ExtendedPointIsOnCurve(r1) {
+			t.Errorf("point is not on curve")
+		}
+		p1, p2 := r1.ToAffine(), r2.ToAffine()
+		field.Mul(p1.x, p1.x, s)
+		if !p1.Eq(p2) {
+			t.Errorf("bad multiplication on isomorphic curve")
+		}
+	}
+}
+
+func TestCurveBadGenerator(t *testing.T) {
+	jubjub := NewJubjub()
+	field := jubjub.field
+	g := jubjub.Generator().ToAffine()
+	field.Add(g.x, g.x, field.r1)
+	_, err := NewTwistedEdwardsCurve(field, jubjub.a, jubjub.d, g, jubjub.Order(), jubjub.Cofactor())
+	if err == nil {
+		t.Errorf("generator is expected to be rejected")
+	}
+}
+
+func TestCurveBadOrder(t *testing.T) {
+	jubjub := NewJubjub()
+	g := jubjub.Generator().ToAffine()
+	// a point of order 8.r
+	full := jubjub.NewExtendedPoint()
+	for c := byte(2); ; c++ {
+		p, err := jubjub.NewExtendedPointFromCompressed(append([]byte{c}, make([]byte, 31)...))
+		if err == nil && !jubjub.IsInSubgroup(p) {
+			full = p
+			break
+		}
+	}
+	r := new(big.Int).Add(jubjub.Order(), big.NewInt(2))
+	for !r.ProbablyPrime(20) {
+		r.Add(r, big.NewInt(2))
+	}
+	for i, test := range []struct {
+		g               *AffinePoint
+		order, cofactor *big.Int
+	}{
+		// not prime
+		{g, new(big.Int).Mul(jubjub.Order(), big.NewInt(8)), big.NewInt(1)},
+		// generator of another order
+		{g, r, jubjub.Cofactor()},
+		{full.ToAffine(), jubjub.Order(), jubjub.Cofactor()},
+		{jubjub.NewAffinePoint(), jubjub.Order(), jubjub.Cofactor()},
+		// wrong number of points
+		{g, jubjub.Order(), big.NewInt(4)},
+		{g, jubjub.Order(), big.NewInt(16)},
+	} {
+		if _, err := NewTwistedEdwardsCurve(jubjub.field, jubjub.a, jubjub.d, test.g, test.order, test.cofactor); err == nil {
+			t.Errorf("bad order %d is expected to be rejected", i)
+		}
+	}
+}
+
+func TestCurveJubjubParameters(t *testing.T) {
+	if err := NewJubjub().checkOrder(); err != nil {
+		t.Errorf("bad jubjub parameters, %v", err)
+	}
+}
+
+func TestCurveCompression(t *testing.T) {
+	curve := NewJubjub()
+	scalarField := NewJubjubScalarField()
+	for i := 0; i < 20; i++ {
+		r := curve.NewExtendedPoint()
+		curve.MulBase(r, randScalar(scalarField))
+		p := r.ToAffine()
+		in := curve.Compress(p)
+		q, err := curve.NewAffinePointFromCompressed(in)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !q.Eq(p) {
+			t.Errorf("bad decompression, have: %s, want: %s", q, p)
+		}
+		// sign bit carries parity of x
+		x := curve.field.ToBytes(p.x)
+		if in[31]>>7 != x[31]&1 {
+			t.Errorf("bad sign bit")
+		}
+	}
+}
+
+func TestCurveCompressionIdentity(t *testing.T) {
+	curve := NewJubjub()
+	in := make([]byte, 32)
+	in[0] = 1
+	p, err := curve.NewAffinePointFromCompressed(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !p.Eq(curve.NewAffinePoint()) {
+		t.Errorf("identity element expected")
+	}
+	in[31] |= 0x80
+	if _, err := curve.NewAffinePointFromCompressed(in); err == nil {
+		t.Errorf("non canonical encoding is expected to be rejected")
+	}
+}
+
+func TestCurveCompressionNonCanonical(t *testing.T) {
+	curve := NewJubjub()
+	// y = p
+	in := toBytes("01000000fffffffffe5bfeff02a4bd5305d8a10908d83933487d9d2953a7ed73")
+	if _, err := curve.NewAffinePointFromCompressed(in); err == nil {
+		t.Errorf("non canonical encoding is expected to be rejected")
+	}
+}
@@ -0,0 +1,302 @@
+// Package dkg implements a synchronous Pedersen distributed key generation over a twisted Edwards curve.
+//
+// A Threshold Cryptosystem without a Trusted Party, Pedersen
+// Secure Distributed Key Generation for Discrete-Log Based Cryptosystems, Gennaro, Jarecki, Krawczyk, Rabin
+//
+// Deal, every participant i deals a Feldman sharing of a random secret a_i0,
+// broadcasts commitments C_ik = [a_ik]G and sends f_i(j) to participant j privately.
+// Complaint, participant j broadcasts a complaint against dealer i if f_i(j) is missing or invalid.
+// Justification, dealer i broadcasts f_i(j) for every complaint against it.
+// Finalize, dealers without a deal, with an unanswered or invalid justification
+// or with at least threshold complaints are disqualified.
+// The share of j is sum f_i(j) and the public key is sum C_i0 over qualified dealers i.
+//
+// Messages missing in a phase are taken as not sent, so all participants agree on the
+// qualified set as long as broadcasts are consistent. Private shares are expected to be
+// sent over authenticated and encrypted channels. As noted by Gennaro et al. an adversary
+// can bias the distribution of the public key, which is acceptable for Schnorr type signatures.
+package dkg
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/kilic/go-jubjub"
+	"github.com/kilic/go-jubjub/vss"
+)
+
+type Phase int
+
+const (
+	PhaseInit Phase = iota
+	PhaseDeal
+	PhaseComplaint
+	PhaseJustification
+	PhaseFinalized
+)
+
+// State of participant Index in a threshold of n key generation, indices are in [1, n]
+type Participant struct {
+	curve       *jubjub.Curve
+	scalarField *jubjub.ScalarField
+	vss         *vss.VSS
+	index       uint32
+	threshold   int
+	n           int
+	phase       Phase
+	polynomial  vss.Polynomial
+	// keyed by dealer
+	deals  map[uint32]*Deal
+	shares map[uint32]*jubjub.ScalarFieldElement
+	// keyed by dealer then complainer
+	complaints     map[uint32]map[uint32]bool
+	justifications map[uint32]map[uint32]*jubjub.ScalarFieldElement
+}
+
+// Output of the key generation
+type Result struct {
+	// Secret share of the participant
+	Share *vss.Share
+	// Feldman commitments of the shared polynomial, the first one is the public key
+	Commitments []*jubjub.ExtendedPoint
+	PublicKey   *jubjub.ExtendedPoint
+	// Sorted indices of the qualified dealers
+	Qualified []uint32
+}
+
+func NewParticipant(curve *jubjub.Curve, index uint32, threshold, n int) (*Participant, error) {
+	if threshold < 1 || threshold > n || int64(n) > 1<<32-1 {
+		return nil, fmt.Errorf("bad threshold")
+	}
+	if index == 0 || uint64(index) > uint64(n) {
+		return nil, fmt.Errorf("bad index")
+	}
+	return &Participant{
+		curve:          curve,
+		scalarField:    jubjub.NewScalarField(curve.Order()),
+		vss:            vss.New(curve),
+		index:          index,
+		threshold:      threshold,
+		n:              n,
+		deals:          make(map[uint32]*Deal),
+		shares:         make(map[uint32]*jubjub.ScalarFieldElement),
+		complaints:     make(map[uint32]map[uint32]bool),
+		justifications: make(map[uint32]map[uint32]*jubjub.ScalarFieldElement),
+	}, nil
+}
+
+func (p *Participant) Index() uint32 {
+	return p.index
+}
+
+func (p *Participant) Phase() Phase {
+	return p.phase
+}
+
+func (p *Participant) Curve() *jubjub.Curve {
+	return p.curve
+}
+
+func (p *Participant) validIndex(i uint32) bool {
+	return i != 0 && uint64(i) <= uint64(p.n)
+}
+
+// Starts the deal phase, returns the deal to broadcast
+// and the shares to send to the other participants
+func (p *Participant) Deal(rand io.Reader) (*Deal, []*ShareMessage, error) {
+	if p.phase != PhaseInit {
+		return nil, nil, fmt.Errorf("bad phase")
+	}
+	secret, err := p.vss.RandScalar(rand)
+	if err != nil {
+		return nil, nil, err
+	}
+	if p.polynomial, err = p.vss.RandPolynomial(rand, secret, p.threshold-1); err != nil {
+		return nil, nil, err
+	}
+	deal := &Deal{Dealer: p.index, Commitments: p.vss.Commit(p.polynomial)}
+	var shares []*ShareMessage
+	for j := 1; j <= p.n; j++ {
+		if uint32(j) == p.index {
+			continue
+		}
+		shares = append(shares, &ShareMessage{Dealer: p.index, Recipient: uint32(j), Share: p.vss.Eval(p.polynomial, uint32(j))})
+	}
+	p.deals[p.index] = deal
+	p.shares[p.index] = p.vss.Eval(p.polynomial, p.index)
+	p.phase = PhaseDeal
+	return deal, shares, nil
+}
+
+// Processes a message of the current phase received from participant from.
+// Rejected messages are ignored by the protocol and an error is returned.
+func (p *Participant) Process(from uint32, m Message) error {
+	if m.Sender() != from || from == p.index || !p.validIndex(from) {
+		return fmt.Errorf("bad sender %d", from)
+	}
+	switch m := m.(type) {
+	case *Deal:
+		if p.phase != PhaseDeal {
+			return fmt.Errorf("bad phase")
+		}
+		if _, ok := p.deals[from]; ok {
+			return fmt.Errorf("duplicate deal of participant %d", from)
+		}
+		if len(m.Commitments) != p.threshold {
+			return fmt.Errorf("bad commitment size of participant %d", from)
+		}
+		p.deals[from] = m
+	case *ShareMessage:
+		if p.phase != PhaseDeal {
+			return fmt.Errorf("bad phase")
+		}
+		if m.Recipient != p.index {
+			return fmt.Errorf("bad recipient")
+		}
+		if _, ok := p.shares[from]; ok {
+			return fmt.Errorf("duplicate share of participant %d", from)
+		}
+		p.shares[from] = m.Share
+	case *Complaint:
+		if p.phase != PhaseComplaint {
+			return fmt.Errorf("bad phase")
+		}
+		if !p.validIndex(m.Dealer) || m.Dealer == from {
+			return fmt.Errorf("bad dealer %d", m.Dealer)
+		}
+		if _, ok := p.deals[m.Dealer]; !ok {
+			return fmt.Errorf("complaint against disqualified dealer %d", m.Dealer)
+		}
+		p.addComplaint(m.Dealer, from)
+	case *Justification:
+		if p.phase != PhaseJustification {
+			return fmt.Errorf("bad phase")
+		}
+		if !p.complaints[from][m.Complainer] {
+			return fmt.Errorf("unexpected justification of participant %d", from)
+		}
+		if _, ok := p.justifications[from][m.Complainer]; ok {
+			return fmt.Errorf("duplicate justification of participant %d", from)
+		}
+		share := &vss.Share{Index: m.Complainer, Value: m.Share}
+		if !p.vss.FeldmanVerify(share, p.deals[from].Commitments) {
+			return fmt.Errorf("bad justification of participant %d", from)
+		}
+		if p.justifications[from] == nil {
+			p.justifications[from] = make(map[uint32]*jubjub.ScalarFieldElement)
+		}
+		p.justifications[from][m.Complainer] = m.Share
+	default:
+		return fmt.Errorf("unknown message")
+	}
+	return nil
+}
+
+func (p *Participant) addComplaint(dealer, complainer uint32) {
+	if p.complaints[dealer] == nil {
+		p.complaints[dealer] = make(map[uint32]bool)
+	}
+	p.complaints[dealer][complainer] = true
+}
+
+// Ends the deal phase, returns complaints to broadcast
+// against dealers whose share is missing or invalid
+func (p *Participant) Complain() ([]*Complaint, error) {
+	if p.phase != PhaseDeal {
+		return nil, fmt.Errorf("bad phase")
+	}
+	var complaints []*Complaint
+	for i := 1; i <= p.n; i++ {
+		dealer := uint32(i)
+		if _, ok := p.deals[dealer]; !ok || dealer == p.index {
+			continue
+		}
+		share, ok := p.shares[dealer]
+		if ok && p.vss.FeldmanVerify(&vss.Share{Index: p.index, Value: share}, p.deals[dealer].Commitments) {
+			continue
+		}
+		complaints = append(complaints, &Complaint{Complainer: p.index, Dealer: dealer})
+		p.addComplaint(dealer, p.index)
+	}
+	p.phase = PhaseComplaint
+	return complaints, nil
+}
+
+// Ends the complaint phase, returns justifications to broadcast
+// for complaints against the participant
+func (p *Participant) Justify() ([]*Justification, error) {
+	if p.phase != PhaseComplaint {
+		return nil, fmt.Errorf("bad phase")
+	}
+	var justifications []*Justification
+	for i := 1; i <= p.n; i++ {
+		complainer := uint32(i)
+		if !p.complaints[p.index][complainer] {
+			continue
+		}
+		share := p.vss.Eval(p.polynomial, complainer)
+		justifications = append(justifications, &Justification{Dealer: p.index, Complainer: complainer, Share: share})
+		if p.justifications[p.index] == nil {
+			p.justifications[p.index] = make(map[uint32]*jubjub.ScalarFieldElement)
+		}
+		p.justifications[p.index][complainer] = share
+	}
+	p.phase = PhaseJustification
+	return justifications, nil
+}
+
+// Ends the justification phase, returns the share of the participant and the public key
+func (p *Participant) Finalize() (*Result, error) {
+	if p.phase != PhaseJustification {
+		return nil, fmt.Errorf("bad phase")
+	}
+	var qualified []uint32
+	for i := 1; i <= p.n; i++ {
+		dealer := uint32(i)
+		if _, ok := p.deals[dealer]; !ok {
+			continue
+		}
+		complaints := p.complaints[dealer]
+		if len(complaints) >= p.threshold {
+			continue
+		}
+		justified := true
+		for complainer := range complaints {
+			if _, ok := p.justifications[dealer][complainer]; !ok {
+				justified = false
+			}
+		}
+		if justified {
+			qualified = append(qualified, dealer)
+		}
+	}
+	if len(qualified) < p.threshold {
+		return nil, fmt.Errorf("not enough qualified dealers")
+	}
+	share := &vss.Share{Index: p.index, Value: p.scalarField.NewElement()}
+	commitments := make([]*jubjub.ExtendedPoint, p.threshold)
+	for k := range commitments {
+		commitments[k] = p.curve.NewExtendedPoint()
+	}
+	for _, dealer := range qualified {
+		s, ok := p.justifications[dealer][p.index]
+		if !ok {
+			s = p.shares[dealer]
+		}
+		p.scalarField.Add(share.Value, share.Value, s)
+		for k, c := range p.deals[dealer].Commitments {
+			p.curve.Add(commitments[k], commitments[k], c)
+		}
+	}
+	if !p.vss.FeldmanVerify(share, commitments) {
+		return nil, fmt.Errorf("bad share")
+	}
+	p.phase = PhaseFinalized
+	p.polynomial = nil
+	return &Result{
+		Share:       share,
+		Commitments: commitments,
+		PublicKey:   new(jubjub.ExtendedPoint).Set(commitments[0]),
+		Qualified:   qualified,
+	}, nil
+}
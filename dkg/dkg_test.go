@@ -0,0 +1,350 @@
+package dkg
+
+import (
+	"bytes"
+	"crypto/rand"
+	"testing"
+
+	"github.com/kilic/go-jubjub"
+	"github.com/kilic/go-jubjub/vss"
+)
+
+func newParticipants(t *testing.T, curve *jubjub.Curve, threshold, n int) []*Participant {
+	participants := make([]*Participant, n)
+	for i := range participants {
+		var err error
+		if participants[i], err = NewParticipant(curve, uint32(i+1), threshold, n); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return participants
+}
+
+// Checks that results of participants other than exclude agree
+// and any threshold of their shares recover the secret key of the public key
+func checkResults(t *testing.T, curve *jubjub.Curve, results map[uint32]*Result, threshold int, qualified []uint32, exclude uint32) {
+	v := vss.New(curve)
+	var shares []*vss.Share
+	var first *Result
+	for i := uint32(1); int(i) <= len(results); i++ {
+		if i == exclude {
+			continue
+		}
+		r, ok := results[i]
+		if !ok {
+			t.Fatalf("missing result of participant %d", i)
+		}
+		if first == nil {
+			first = r
+		}
+		if !r.PublicKey.Eq(first.PublicKey) || !r.Commitments[0].Eq(r.PublicKey) {
+			t.Errorf("bad public key of participant %d", i)
+		}
+		if len(r.Qualified) != len(qualified) {
+			t.Fatalf("bad qualified set of participant %d, have: %v, want: %v", i, r.Qualified, qualified)
+		}
+		for k := range qualified {
+			if r.Qualified[k] != qualified[k] {
+				t.Errorf("bad qualified set of participant %d, have: %v, want: %v", i, r.Qualified, qualified)
+			}
+		}
+		if !v.FeldmanVerify(r.Share, first.Commitments) {
+			t.Errorf("bad share of participant %d", i)
+		}
+		shares = append(shares, r.Share)
+	}
+	for _, subset := range [][]*vss.Share{shares[:threshold], shares[len(shares)-threshold:]} {
+		secret, err := v.Combine(subset)
+		if err != nil {
+			t.Fatal(err)
+		}
+		pub := curve.NewExtendedPoint()
+		curve.MulBase(pub, secret)
+		if !pub.Eq(first.PublicKey) {
+			t.Errorf("bad combined secret key")
+		}
+	}
+}
+
+func TestDKG(t *testing.T) {
+	for _, curve := range []*jubjub.Curve{jubjub.NewJubjub(), jubjub.NewBandersnatch()} {
+		participants := newParticipants(t, curve, 3, 5)
+		results, err := Run(rand.Reader, participants, NewMemoryTransport(5))
+		if err != nil {
+			t.Fatal(err)
+		}
+		checkResults(t, curve, results, 3, []uint32{1, 2, 3, 4, 5}, 0)
+		if _, err := participants[0].Finalize(); err == nil {
+			t.Errorf("expected error for bad phase")
+		}
+	}
+	if _, err := NewParticipant(jubjub.NewJubjub(), 4, 2, 3); err == nil {
+		t.Errorf("expected error for bad index")
+	}
+	if _, err := NewParticipant(jubjub.NewJubjub(), 1, 4, 3); err == nil {
+		t.Errorf("expected error for bad threshold")
+	}
+}
+
+func TestDKGMisbehaviour(t *testing.T) {
+	curve := jubjub.NewJubjub()
+	badShare := func(msg []byte) []byte {
+		msg[len(msg)-32] ^= 1
+		return msg
+	}
+	for _, test := range []struct {
+		name      string
+		tamper    func(from, to uint32, msg []byte) []byte
+		qualified []uint32
+		// a dealer whose broadcast doesn't reach the others doesn't agree with them
+		exclude uint32
+	}{
+		{
+			// justified by revealing the share
+			"bad share",
+			func(from, to uint32, msg []byte) []byte {
+				if from == 2 && to == 3 && msg[0] == typeShare {
+					return badShare(msg)
+				}
+				return msg
+			},
+			[]uint32{1, 2, 3, 4, 5},
+			0,
+		},
+		{
+			"missing share",
+			func(from, to uint32, msg []byte) []byte {
+				if from == 2 && to == 3 && msg[0] == typeShare {
+					return nil
+				}
+				return msg
+			},
+			[]uint32{1, 2, 3, 4, 5},
+			0,
+		},
+		{
+			"missing justification",
+			func(from, to uint32, msg []byte) []byte {
+				if from == 2 && to == 3 && msg[0] == typeShare {
+					return badShare(msg)
+				}
+				if from == 2 && msg[0] == typeJustification {
+					return nil
+				}
+				return msg
+			},
+			[]uint32{1, 3, 4, 5},
+			2,
+		},
+		{
+			"bad justification",
+			func(from, to uint32, msg []byte) []byte {
+				if from == 2 && to == 3 && msg[0] == typeShare {
+					return badShare(msg)
+				}
+				if from == 2 && msg[0] == typeJustification {
+					return badShare(msg)
+				}
+				return msg
+			},
+			[]uint32{1, 3, 4, 5},
+			2,
+		},
+		{
+			"threshold complaints",
+			func(from, to uint32, msg []byte) []byte {
+				if from == 4 && to != 5 && msg[0] == typeShare {
+					return badShare(msg)
+				}
+				return msg
+			},
+			[]uint32{1, 2, 3, 5},
+			0,
+		},
+		{
+			"missing deal",
+			func(from, to uint32, msg []byte) []byte {
+				if from == 5 && msg[0] == typeDeal {
+					return nil
+				}
+				return msg
+			},
+			[]uint32{1, 2, 3, 4},
+			5,
+		},
+		{
+			"bad deal",
+			func(from, to uint32, msg []byte) []byte {
+				if from == 1 && msg[0] == typeDeal {
+					return msg[:len(msg)-1]
+				}
+				return msg
+			},
+			[]uint32{2, 3, 4, 5},
+			1,
+		},
+	} {
+		transport := NewMemoryTransport(5)
+		transport.Tamper = test.tamper
+		results, err := Run(rand.Reader, newParticipants(t, curve, 3, 5), transport)
+		if err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+		checkResults(t, curve, results, 3, test.qualified, test.exclude)
+	}
+
+	// too many disqualified dealers
+	transport := NewMemoryTransport(3)
+	transport.Tamper = func(from, to uint32, msg []byte) []byte {
+		if from != 1 && msg[0] == typeDeal {
+			return nil
+		}
+		return msg
+	}
+	if _, err := Run(rand.Reader, newParticipants(t, curve, 2, 3), transport); err == nil {
+		t.Errorf("expected error for not enough qualified dealers")
+	}
+}
+
+func TestDKGFalseComplaint(t *testing.T) {
+	curve := jubjub.NewJubjub()
+	participants := newParticipants(t, curve, 2, 3)
+	deals := make([]*Deal, 3)
+	shares := make([][]*ShareMessage, 3)
+	for i, p := range participants {
+		var err error
+		if deals[i], shares[i], err = p.Deal(rand.Reader); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for i, p := range participants {
+		for j := range participants {
+			if i == j {
+				continue
+			}
+			if err := p.Process(uint32(j+1), deals[j]); err != nil {
+				t.Fatal(err)
+			}
+			for _, s := range shares[j] {
+				if s.Recipient == p.Index() {
+					if err := p.Process(uint32(j+1), s); err != nil {
+						t.Fatal(err)
+					}
+				}
+			}
+		}
+	}
+	if err := participants[0].Process(2, deals[1]); err == nil {
+		t.Errorf("expected error for duplicate deal")
+	}
+	if err := participants[0].Process(3, deals[1]); err == nil {
+		t.Errorf("expected error for bad sender")
+	}
+	for _, p := range participants {
+		complaints, err := p.Complain()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(complaints) != 0 {
+			t.Fatalf("unexpected complaints")
+		}
+	}
+	// participant 3 complains against participant 1 although its share is valid
+	complaint := &Complaint{Complainer: 3, Dealer: 1}
+	for _, p := range participants[:2] {
+		if err := p.Process(3, complaint); err != nil {
+			t.Fatal(err)
+		}
+	}
+	participants[2].addComplaint(1, 3)
+	justifications, err := participants[0].Justify()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(justifications) != 1 || justifications[0].Complainer != 3 {
+		t.Fatalf("bad justifications")
+	}
+	for _, p := range participants[1:] {
+		if _, err := p.Justify(); err != nil {
+			t.Fatal(err)
+		}
+		if err := p.Process(1, justifications[0]); err != nil {
+			t.Fatal(err)
+		}
+	}
+	results := make(map[uint32]*Result)
+	for _, p := range participants {
+		if results[p.Index()], err = p.Finalize(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	checkResults(t, curve, results, 2, []uint32{1, 2, 3}, 0)
+}
+
+func TestMessageEncoding(t *testing.T) {
+	curve := jubjub.NewJubjub()
+	p, _ := NewParticipant(curve, 1, 3, 4)
+	deal, shares, err := p.Deal(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	msgs := []Message{deal, shares[0], &Complaint{Complainer: 2, Dealer: 1}, &Justification{Dealer: 1, Complainer: 2, Share: shares[0].Share}}
+	for _, m := range msgs {
+		data := Marshal(curve, m)
+		decoded, err := Unmarshal(curve, data)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(Marshal(curve, decoded), data) {
+			t.Errorf("bad encoding of message type %d", m.messageType())
+		}
+	}
+	data := Marshal(curve, deal)
+	if len(data) != 9+3*32 {
+		t.Errorf("bad deal size %d", len(data))
+	}
+	decoded, _ := Unmarshal(curve, data)
+	for i, c := range decoded.(*Deal).Commitments {
+		if !c.Eq(deal.Commitments[i]) {
+			t.Errorf("bad commitment %d", i)
+		}
+	}
+	for _, in := range [][]byte{
+		{},
+		{9, 1, 0, 0, 0, 1, 0, 0, 0},
+		data[:len(data)-1],
+		Marshal(curve, msgs[2])[:8],
+	} {
+		if _, err := Unmarshal(curve, in); err == nil {
+			t.Errorf("expected error for bad encoding %x", in)
+		}
+	}
+	// non canonical scalar
+	in := Marshal(curve, shares[0])
+	copy(in[9:], bytes.Repeat([]byte{0xff}, 32))
+	if _, err := Unmarshal(curve, in); err == nil {
+		t.Errorf("expected error for non canonical scalar")
+	}
+	// point of small order
+	in = append([]byte{}, data[:9+32]...)
+	in[5] = 1
+	negOne := curve.NewAffinePoint().Y()
+	curve.Field().Neg(negOne, negOne)
+	copy(in[9:], curve.Compress(curve.NewAffinePoint().SetCoordinates(new(jubjub.FieldElement), negOne)))
+	if _, err := Unmarshal(curve, in); err == nil {
+		t.Errorf("expected error for point not in subgroup")
+	}
+}
+
+func BenchmarkDKG(t *testing.B) {
+	curve := jubjub.NewJubjub()
+	for i := 0; i < t.N; i++ {
+		participants := make([]*Participant, 5)
+		for j := range participants {
+			participants[j], _ = NewParticipant(curve, uint32(j+1), 3, 5)
+		}
+		if _, err := Run(rand.Reader, participants, NewMemoryTransport(5)); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,144 @@
+package dkg
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	"github.com/kilic/go-jubjub"
+)
+
+// Messages are encoded as type byte || fields where indices are 4 bytes little endian,
+// points are 32 bytes compressed and scalars are 32 bytes little endian.
+const (
+	typeDeal byte = iota
+	typeShare
+	typeComplaint
+	typeJustification
+)
+
+const (
+	pointSize  = 32
+	scalarSize = 32
+)
+
+type Message interface {
+	// Index of the participant that created the message
+	Sender() uint32
+	messageType() byte
+}
+
+// Broadcast by a dealer in the deal phase,
+// Feldman commitments to the coefficients of its sharing polynomial
+type Deal struct {
+	Dealer      uint32
+	Commitments []*jubjub.ExtendedPoint
+}
+
+// Sent privately by a dealer to a participant in the deal phase
+type ShareMessage struct {
+	Dealer    uint32
+	Recipient uint32
+	Share     *jubjub.ScalarFieldElement
+}
+
+// Broadcast by a participant in the complaint phase
+// against a dealer whose share is missing or invalid
+type Complaint struct {
+	Complainer uint32
+	Dealer     uint32
+}
+
+// Broadcast by a dealer in the justification phase,
+// reveals the share of a complainer
+type Justification struct {
+	Dealer     uint32
+	Complainer uint32
+	Share      *jubjub.ScalarFieldElement
+}
+
+func (m *Deal) Sender() uint32          { return m.Dealer }
+func (m *ShareMessage) Sender() uint32  { return m.Dealer }
+func (m *Complaint) Sender() uint32     { return m.Complainer }
+func (m *Justification) Sender() uint32 { return m.Dealer }
+
+func (m *Deal) messageType() byte          { return typeDeal }
+func (m *ShareMessage) messageType() byte  { return typeShare }
+func (m *Complaint) messageType() byte     { return typeComplaint }
+func (m *Justification) messageType() byte { return typeJustification }
+
+// Returns the encoding of m
+func Marshal(curve *jubjub.Curve, m Message) []byte {
+	out := []byte{m.messageType()}
+	switch m := m.(type) {
+	case *Deal:
+		out = appendUint32(out, m.Dealer)
+		out = appendUint32(out, uint32(len(m.Commitments)))
+		for _, c := range m.Commitments {
+			out = append(out, curve.Compress(c.ToAffine())...)
+		}
+	case *ShareMessage:
+		out = appendUint32(out, m.Dealer)
+		out = appendUint32(out, m.Recipient)
+		out = append(out, jubjub.NewScalarField(curve.Order()).ToBytes(m.Share)...)
+	case *Complaint:
+		out = appendUint32(out, m.Complainer)
+		out = appendUint32(out, m.Dealer)
+	case *Justification:
+		out = appendUint32(out, m.Dealer)
+		out = appendUint32(out, m.Complainer)
+		out = append(out, jubjub.NewScalarField(curve.Order()).ToBytes(m.Share)...)
+	}
+	return out
+}
+
+// Decodes a message, points are required to be in the prime order subgroup
+// and scalars to be canonical
+func Unmarshal(curve *jubjub.Curve, in []byte) (Message, error) {
+	if len(in) < 9 {
+		return nil, fmt.Errorf("bad message size")
+	}
+	a, b := binary.LittleEndian.Uint32(in[1:5]), binary.LittleEndian.Uint32(in[5:9])
+	body := in[9:]
+	switch in[0] {
+	case typeDeal:
+		if uint64(len(body)) != uint64(b)*pointSize {
+			return nil, fmt.Errorf("bad message size")
+		}
+		m := &Deal{Dealer: a, Commitments: make([]*jubjub.ExtendedPoint, b)}
+		for i := range m.Commitments {
+			p, err := curve.NewExtendedPointFromCompressed(body[i*pointSize : (i+1)*pointSize])
+			if err != nil {
+				return nil, err
+			}
+			if !curve.IsInSubgroup(p) {
+				return nil, fmt.Errorf("commitment is not in subgroup")
+			}
+			m.Commitments[i] = p
+		}
+		return m, nil
+	case typeShare, typeJustification:
+		if len(body) != scalarSize {
+			return nil, fmt.Errorf("bad message size")
+		}
+		s, err := jubjub.NewScalarField(curve.Order()).FromBytes(body)
+		if err != nil {
+			return nil, err
+		}
+		if in[0] == typeShare {
+			return &ShareMessage{Dealer: a, Recipient: b, Share: s}, nil
+		}
+		return &Justification{Dealer: a, Complainer: b, Share: s}, nil
+	case typeComplaint:
+		if len(body) != 0 {
+			return nil, fmt.Errorf("bad message size")
+		}
+		return &Complaint{Complainer: a, Dealer: b}, nil
+	}
+	return nil, fmt.Errorf("unknown message type %d", in[0])
+}
+
+func appendUint32(out []byte, a uint32) []byte {
+	var b [4]byte
+	binary.LittleEndian.PutUint32(b[:], a)
+	return append(out, b[:]...)
+}
@@ -0,0 +1,183 @@
+package dkg
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+)
+
+// Encoded message and the index of the participant it is received from
+type Envelope struct {
+	From uint32
+	Data []byte
+}
+
+// Authenticated channels between participants with a consistent broadcast
+type Transport interface {
+	// Sends msg to every participant except from
+	Broadcast(from uint32, msg []byte) error
+	Send(from, to uint32, msg []byte) error
+	// Returns and removes messages delivered to participant to
+	Receive(to uint32) ([]*Envelope, error)
+}
+
+// In memory transport between participants 1 to n
+type MemoryTransport struct {
+	mu      sync.Mutex
+	n       int
+	inboxes map[uint32][]*Envelope
+	// Called for every delivery if not nil, returns the message to deliver or nil to drop it.
+	// Allows simulating misbehaving participants and faulty channels.
+	Tamper func(from, to uint32, msg []byte) []byte
+}
+
+func NewMemoryTransport(n int) *MemoryTransport {
+	return &MemoryTransport{n: n, inboxes: make(map[uint32][]*Envelope)}
+}
+
+func (t *MemoryTransport) validIndex(i uint32) bool {
+	return i != 0 && uint64(i) <= uint64(t.n)
+}
+
+func (t *MemoryTransport) deliver(from, to uint32, msg []byte) {
+	if t.Tamper != nil {
+		if msg = t.Tamper(from, to, append([]byte{}, msg...)); msg == nil {
+			return
+		}
+	}
+	t.inboxes[to] = append(t.inboxes[to], &Envelope{From: from, Data: append([]byte{}, msg...)})
+}
+
+func (t *MemoryTransport) Broadcast(from uint32, msg []byte) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if !t.validIndex(from) {
+		return fmt.Errorf("bad sender %d", from)
+	}
+	for i := 1; i <= t.n; i++ {
+		if uint32(i) != from {
+			t.deliver(from, uint32(i), msg)
+		}
+	}
+	return nil
+}
+
+func (t *MemoryTransport) Send(from, to uint32, msg []byte) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if !t.validIndex(from) || !t.validIndex(to) || from == to {
+		return fmt.Errorf("bad sender or recipient")
+	}
+	t.deliver(from, to, msg)
+	return nil
+}
+
+func (t *MemoryTransport) Receive(to uint32) ([]*Envelope, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if !t.validIndex(to) {
+		return nil, fmt.Errorf("bad recipient %d", to)
+	}
+	msgs := t.inboxes[to]
+	delete(t.inboxes, to)
+	return msgs, nil
+}
+
+// Runs the phases of the key generation in lock step for participants sharing the transport.
+// Messages that fail to decode or are rejected by a participant are dropped.
+// Returns results keyed by participant index, participants that fail to finalize are absent
+// and the first such error is returned.
+func Run(rand io.Reader, participants []*Participant, transport Transport) (map[uint32]*Result, error) {
+	send := func(p *Participant, msgs []Message) error {
+		for _, m := range msgs {
+			data := Marshal(p.curve, m)
+			var err error
+			if s, ok := m.(*ShareMessage); ok {
+				err = transport.Send(p.index, s.Recipient, data)
+			} else {
+				err = transport.Broadcast(p.index, data)
+			}
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	receive := func() error {
+		for _, p := range participants {
+			envelopes, err := transport.Receive(p.index)
+			if err != nil {
+				return err
+			}
+			sort.SliceStable(envelopes, func(i, j int) bool { return envelopes[i].From < envelopes[j].From })
+			for _, e := range envelopes {
+				if m, err := Unmarshal(p.curve, e.Data); err == nil {
+					_ = p.Process(e.From, m)
+				}
+			}
+		}
+		return nil
+	}
+	for _, p := range participants {
+		deal, shares, err := p.Deal(rand)
+		if err != nil {
+			return nil, err
+		}
+		msgs := []Message{deal}
+		for _, s := range shares {
+			msgs = append(msgs, s)
+		}
+		if err := send(p, msgs); err != nil {
+			return nil, err
+		}
+	}
+	if err := receive(); err != nil {
+		return nil, err
+	}
+	for _, p := range participants {
+		complaints, err := p.Complain()
+		if err != nil {
+			return nil, err
+		}
+		var msgs []Message
+		for _, c := range complaints {
+			msgs = append(msgs, c)
+		}
+		if err := send(p, msgs); err != nil {
+			return nil, err
+		}
+	}
+	if err := receive(); err != nil {
+		return nil, err
+	}
+	for _, p := range participants {
+		justifications, err := p.Justify()
+		if err != nil {
+			return nil, err
+		}
+		var msgs []Message
+		for _, j := range justifications {
+			msgs = append(msgs, j)
+		}
+		if err := send(p, msgs); err != nil {
+			return nil, err
+		}
+	}
+	if err := receive(); err != nil {
+		return nil, err
+	}
+	results := make(map[uint32]*Result)
+	var firstErr error
+	for _, p := range participants {
+		r, err := p.Finalize()
+		if err != nil {
+			if firstErr == nil {
+				firstErr = fmt.Errorf("participant %d: %v", p.index, err)
+			}
+			continue
+		}
+		results[p.index] = r
+	}
+	return results, firstErr
+}
@@ -0,0 +1,112 @@
+package eddsa
+
+import (
+	"encoding/binary"
+	"math/bits"
+)
+
+// BLAKE-512 as submitted to the SHA-3 competition, not BLAKE2b.
+// circomlib derives signing keys and nonces with it.
+// SHA-3 proposal BLAKE
+// Aumasson, Henzen, Meier, Phan
+
+var blake512IV = [8]uint64{
+	0x6a09e667f3bcc908, 0xbb67ae8584caa73b, 0x3c6ef372fe94f82b, 0xa54ff53a5f1d36f1,
+	0x510e527fade682d1, 0x9b05688c2b3e6c1f, 0x1f83d9abfb41bd6b, 0x5be0cd19137e2179,
+}
+
+var blake512C = [16]uint64{
+	0x243f6a8885a308d3, 0x13198a2e03707344, 0xa4093822299f31d0, 0x082efa98ec4e6c89,
+	0x452821e638d01377, 0xbe5466cf34e90c6c, 0xc0ac29b7c97c50dd, 0x3f84d5b5b5470917,
+	0x9216d5d98979fb1b, 0xd1310ba698dfb5ac, 0x2ffd72dbd01adfb7, 0xb8e1afed6a267e96,
+	0xba7c9045f12c7f99, 0x24a19947b3916cf7, 0x0801f2e2858efc16, 0x636920d871574e69,
+}
+
+var blake512Sigma = [10][16]uint8{
+	{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15},
+	{14, 10, 4, 8, 9, 15, 13, 6, 1, 12, 0, 2, 11, 7, 5, 3},
+	{11, 8, 12, 0, 5, 2, 15, 13, 10, 14, 3, 6, 7, 1, 9, 4},
+	{7, 9, 3, 1, 13, 12, 11, 14, 2, 6, 5, 10, 4, 0, 15, 8},
+	{9, 0, 5, 7, 2, 4, 10, 15, 14, 1, 11, 12, 6, 8, 3, 13},
+	{2, 12, 6, 10, 0, 11, 8, 3, 4, 13, 7, 5, 15, 14, 1, 9},
+	{12, 5, 1, 15, 14, 13, 4, 10, 0, 7, 6, 3, 9, 2, 8, 11},
+	{13, 11, 7, 14, 12, 1, 3, 9, 5, 0, 15, 4, 8, 6, 2, 10},
+	{6, 15, 14, 9, 11, 3, 0, 8, 12, 2, 13, 7, 1, 4, 10, 5},
+	{10, 2, 8, 4, 7, 6, 1, 5, 15, 11, 9, 14, 3, 12, 13, 0},
+}
+
+const blake512Rounds = 16
+
+// Returns BLAKE-512 digest of the message with zero salt
+func blake512(in []byte) [64]byte {
+	h := blake512IV
+	l := uint64(len(in)) << 3
+	var counter uint64
+	for len(in) >= 128 {
+		counter += 1024
+		blake512Compress(&h, in[:128], counter)
+		in = in[128:]
+	}
+	// padding is 1 bit, zeros and 1 bit followed by 128 bit message length
+	// a block that carries no message bits is compressed with zero counter
+	var block [256]byte
+	n := copy(block[:], in)
+	block[n] = 0x80
+	size := 128
+	if n > 111 {
+		size = 256
+	}
+	block[size-17] |= 0x01
+	binary.BigEndian.PutUint64(block[size-8:], l)
+	if n > 0 {
+		counter += uint64(n) << 3
+	}
+	if size == 256 {
+		blake512Compress(&h, block[:128], counter)
+		counter = 0
+	}
+	blake512Compress(&h, block[size-128:size], counter)
+	var out [64]byte
+	for i := 0; i < 8; i++ {
+		binary.BigEndian.PutUint64(out[i*8:], h[i])
+	}
+	return out
+}
+
+func blake512Compress(h *[8]uint64, block []byte, counter uint64) {
+	var m [16]uint64
+	for i := 0; i < 16; i++ {
+		m[i] = binary.BigEndian.Uint64(block[i*8:])
+	}
+	var v [16]uint64
+	copy(v[:8], h[:])
+	copy(v[8:], blake512C[:8])
+	v[12] ^= counter
+	v[13] ^= counter
+	for r := 0; r < blake512Rounds; r++ {
+		s := &blake512Sigma[r%10]
+		blake512G(&v, &m, s, 0, 0, 4, 8, 12)
+		blake512G(&v, &m, s, 1, 1, 5, 9, 13)
+		blake512G(&v, &m, s, 2, 2, 6, 10, 14)
+		blake512G(&v, &m, s, 3, 3, 7, 11, 15)
+		blake512G(&v, &m, s, 4, 0, 5, 10, 15)
+		blake512G(&v, &m, s, 5, 1, 6, 11, 12)
+		blake512G(&v, &m, s, 6, 2, 7, 8, 13)
+		blake512G(&v, &m, s, 7, 3, 4, 9, 14)
+	}
+	for i := 0; i < 8; i++ {
+		h[i] ^= v[i] ^ v[i+8]
+	}
+}
+
+func blake512G(v *[16]uint64, m *[16]uint64, s *[16]uint8, i, a, b, c, d int) {
+	x, y := s[2*i], s[2*i+1]
+	v[a] += v[b] + (m[x] ^ blake512C[y])
+	v[d] = bits.RotateLeft64(v[d]^v[a], -32)
+	v[c] += v[d]
+	v[b] = bits.RotateLeft64(v[b]^v[c], -25)
+	v[a] += v[b] + (m[y] ^ blake512C[x])
+	v[d] = bits.RotateLeft64(v[d]^v[a], -16)
+	v[c] += v[d]
+	v[b] = bits.RotateLeft64(v[b]^v[c], -11)
+}
@@ -0,0 +1,170 @@
+package eddsa
+
+import (
+	"fmt"
+	"io"
+	"math/big"
+
+	"github.com/kilic/go-jubjub"
+	"github.com/kilic/go-jubjub/mimc"
+	"github.com/kilic/go-jubjub/poseidon"
+)
+
+// EdDSA over Baby Jubjub compatible with circomlib
+// EdDSAPoseidonVerifier and EdDSAMiMCVerifier circuits
+//
+// s = prune(H(k)[0:32]) / 8
+// A = s.B8
+// r = H(H(k)[32:64] || msg) mod l
+// R8 = r.B8
+// S = r + h(R8, A, msg).8.s mod l
+// where H is BLAKE-512 and h is either Poseidon or MiMC-7
+//
+// Verification equation is S.B8 = R8 + 8.h(R8, A, msg).A
+type EdDSA struct {
+	curve       *jubjub.Curve
+	field       *jubjub.Field
+	scalarField *jubjub.ScalarField
+	hash        func(inputs []*jubjub.FieldElement) *jubjub.FieldElement
+}
+
+type PrivateKey [32]byte
+
+type Signature struct {
+	R8 *jubjub.AffinePoint
+	S  *big.Int
+}
+
+// Expects Baby Jubjub curve whose generator is B8
+func NewEdDSAPoseidon(curve *jubjub.Curve) *EdDSA {
+	// circomlib instance of width 6
+	h, _ := poseidon.New(curve.Field(), 6, 8, 60)
+	return newEdDSA(curve, func(inputs []*jubjub.FieldElement) *jubjub.FieldElement {
+		out, _ := h.Hash(inputs)
+		return out
+	})
+}
+
+// Expects Baby Jubjub curve whose generator is B8
+func NewEdDSAMiMC(curve *jubjub.Curve) *EdDSA {
+	h := mimc.NewMiMC7Circom(curve.Field())
+	return newEdDSA(curve, func(inputs []*jubjub.FieldElement) *jubjub.FieldElement {
+		return h.Hash(inputs, nil)
+	})
+}
+
+func newEdDSA(curve *jubjub.Curve, hash func([]*jubjub.FieldElement) *jubjub.FieldElement) *EdDSA {
+	return &EdDSA{
+		curve:       curve,
+		field:       curve.Field(),
+		scalarField: jubjub.NewScalarField(curve.Order()),
+		hash:        hash,
+	}
+}
+
+func NewRandPrivateKey(r io.Reader) (*PrivateKey, error) {
+	k := new(PrivateKey)
+	if _, err := io.ReadFull(r, k[:]); err != nil {
+		return nil, err
+	}
+	return k, nil
+}
+
+// Returns the secret scalar s
+func (e *EdDSA) Scalar(k *PrivateKey) *big.Int {
+	h := blake512(k[:])
+	var buf [32]byte
+	copy(buf[:], h[:32])
+	buf[0] &= 0xf8
+	buf[31] &= 0x7f
+	buf[31] |= 0x40
+	s := leToBig(buf[:])
+	return s.Rsh(s, 3)
+}
+
+func (e *EdDSA) PublicKey(k *PrivateKey) *jubjub.AffinePoint {
+	A := e.curve.NewExtendedPoint()
+	e.curve.MulBase(A, e.scalarField.NewElementFromBig(e.Scalar(k)))
+	return A.ToAffine()
+}
+
+func (e *EdDSA) Sign(k *PrivateKey, msg *jubjub.FieldElement) *Signature {
+	l := e.curve.Order()
+	h := blake512(k[:])
+	in := make([]byte, 64)
+	copy(in, h[32:])
+	copy(in[32:], reverse(e.field.ToBytes(msg)))
+	rBuf := blake512(in)
+	r := e.scalarField.FromBytesWide(rBuf[:])
+	R8 := e.curve.NewExtendedPoint()
+	e.curve.MulBase(R8, r)
+	sig := &Signature{R8: R8.ToAffine()}
+	A := e.PublicKey(k)
+	hm := e.challenge(sig.R8, A, msg)
+	S := new(big.Int).Lsh(e.Scalar(k), 3)
+	S.Mul(S, hm)
+	S.Add(S, r.Big())
+	sig.S = S.Mod(S, l)
+	return sig
+}
+
+func (e *EdDSA) Verify(A *jubjub.AffinePoint, msg *jubjub.FieldElement, sig *Signature) bool {
+	if sig == nil || sig.R8 == nil || sig.S == nil {
+		return false
+	}
+	if sig.S.Sign() < 0 || sig.S.Cmp(e.curve.Order()) >= 0 {
+		return false
+	}
+	if !e.curve.AffinePointIsOnCurve(sig.R8) || !e.curve.AffinePointIsOnCurve(A) {
+		return false
+	}
+	hm := e.challenge(sig.R8, A, msg)
+	hm.Lsh(hm, 3)
+	left, right := e.curve.NewExtendedPoint(), e.curve.NewExtendedPoint()
+	e.curve.MulBase(left, e.scalarField.NewElementFromBig(sig.S))
+	e.curve.Mul(right, A.ToExtended(), e.scalarField.NewElementFromBig(hm))
+	e.curve.Add(right, right, sig.R8.ToExtended())
+	return left.Eq(right)
+}
+
+// h(R8.x, R8.y, A.x, A.y, msg) as an integer
+func (e *EdDSA) challenge(R8, A *jubjub.AffinePoint, msg *jubjub.FieldElement) *big.Int {
+	hm := e.hash([]*jubjub.FieldElement{R8.X(), R8.Y(), A.X(), A.Y(), msg})
+	return new(big.Int).SetBytes(e.field.ToBytes(hm))
+}
+
+// Returns 64 bytes compressed R8 followed by little endian S
+func (e *EdDSA) Compress(sig *Signature) []byte {
+	out := make([]byte, 64)
+	copy(out, e.curve.Compress(sig.R8))
+	copy(out[32:], e.scalarField.ToBytes(e.scalarField.NewElementFromBig(sig.S)))
+	return out
+}
+
+func (e *EdDSA) NewSignatureFromCompressed(in []byte) (*Signature, error) {
+	if len(in) != 64 {
+		return nil, fmt.Errorf("bad compressed signature input size")
+	}
+	R8, err := e.curve.NewAffinePointFromCompressed(in[:32])
+	if err != nil {
+		return nil, err
+	}
+	S, err := e.scalarField.FromBytes(in[32:])
+	if err != nil {
+		return nil, err
+	}
+	return &Signature{R8: R8, S: S.Big()}, nil
+}
+
+func leToBig(in []byte) *big.Int {
+	return new(big.Int).SetBytes(reverse(in))
+}
+
+// Returns a reversed copy of in
+func reverse(in []byte) []byte {
+	out := make([]byte, len(in))
+	for i := range in {
+		out[len(in)-1-i] = in[i]
+	}
+	return out
+}
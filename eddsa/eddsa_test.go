@@ -0,0 +1,158 @@
+package eddsa
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"math/big"
+	"testing"
+
+	"github.com/kilic/go-jubjub"
+)
+
+// signature test vectors are from circomlib
+
+func fromStr10(field *jubjub.Field, s string) *jubjub.FieldElement {
+	n, _ := new(big.Int).SetString(s, 10)
+	return field.NewElement(n.Bytes())
+}
+
+func toStr10(field *jubjub.Field, a *jubjub.FieldElement) string {
+	return new(big.Int).SetBytes(field.ToBytes(a)).String()
+}
+
+func TestBlake512(t *testing.T) {
+	vectors := []struct {
+		in  []byte
+		out string
+	}{
+		{[]byte{},
+			"a8cfbbd73726062df0c6864dda65defe58ef0cc52a5625090fa17601e1eecd1b" +
+				"628e94f396ae402a00acc9eab77b4d4c2e852aaaa25a636d80af3fc7913ef5b8"},
+		{[]byte{0},
+			"97961587f6d970faba6d2478045de6d1fabd09b61ae50932054d52bc29d31be4" +
+				"ff9102b9f69e2bbdb83be13d4b9c06091e5fa0b48bd081b634058be0ec49beb3"},
+		{make([]byte, 144),
+			"313717d608e9cf758dcb1eb0f0c3cf9fc150b2d500fb33f51c52afc99d358a2f" +
+				"1374b8a38bba7974e7f6ef79cab16f22ce1e649d6e01ad9589c213045d545dde"},
+	}
+	for i, v := range vectors {
+		out := blake512(v.in)
+		if hex.EncodeToString(out[:]) != v.out {
+			t.Errorf("bad digest %d, have: %x, want: %s", i, out, v.out)
+		}
+	}
+}
+
+func testPrivateKey() *PrivateKey {
+	k := new(PrivateKey)
+	b, _ := hex.DecodeString("0001020304050607080900010203040506070809000102030405060708090001")
+	copy(k[:], b)
+	return k
+}
+
+func testMessage(field *jubjub.Field) *jubjub.FieldElement {
+	// little endian 00010203040506070809
+	b, _ := hex.DecodeString("09080706050403020100")
+	return field.NewElement(b)
+}
+
+func testVector(t *testing.T, e *EdDSA, s, compressed string) {
+	field := e.field
+	k := testPrivateKey()
+	msg := testMessage(field)
+	A := e.PublicKey(k)
+	if toStr10(field, A.X()) != "13277427435165878497778222415993513565335242147425444199013288855685581939618" ||
+		toStr10(field, A.Y()) != "13622229784656158136036771217484571176836296686641868549125388198837476602820" {
+		t.Fatalf("bad public key %s", A)
+	}
+	sig := e.Sign(k, msg)
+	if toStr10(field, sig.R8.X()) != "11384336176656855268977457483345535180380036354188103142384839473266348197733" ||
+		toStr10(field, sig.R8.Y()) != "15383486972088797283337779941324724402501462225528836549661220478783371668959" {
+		t.Fatalf("bad R8 %s", sig.R8)
+	}
+	if sig.S.String() != s {
+		t.Fatalf("bad S, have: %s, want: %s", sig.S, s)
+	}
+	if !e.Verify(A, msg, sig) {
+		t.Fatalf("signature is expected to be valid")
+	}
+	out := e.Compress(sig)
+	if hex.EncodeToString(out) != compressed {
+		t.Fatalf("bad compression, have: %x, want: %s", out, compressed)
+	}
+	sig2, err := e.NewSignatureFromCompressed(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !e.Verify(A, msg, sig2) {
+		t.Fatalf("decompressed signature is expected to be valid")
+	}
+}
+
+func TestEdDSAPoseidonVector(t *testing.T) {
+	e := NewEdDSAPoseidon(jubjub.NewBabyJubjub())
+	testVector(t, e,
+		"1672775540645840396591609181675628451599263765380031905495115170613215233181",
+		"dfedb4315d3f2eb4de2d3c510d7a987dcab67089c8ace06308827bf5bcbe02a2"+
+			"9d043ece562a8f82bfc0adb640c0107a7d3a27c1c7c1a6179a0da73de5c1b203")
+}
+
+func TestEdDSAMiMCVector(t *testing.T) {
+	e := NewEdDSAMiMC(jubjub.NewBabyJubjub())
+	testVector(t, e,
+		"2523202440825208709475937830811065542425109372212752003460238913256192595070",
+		"dfedb4315d3f2eb4de2d3c510d7a987dcab67089c8ace06308827bf5bcbe02a2"+
+			"7ed40dab29bf993c928e789d007387998901a24913d44fddb64b1f21fc149405")
+}
+
+func TestEdDSAInvalid(t *testing.T) {
+	e := NewEdDSAPoseidon(jubjub.NewBabyJubjub())
+	field := e.field
+	k, err := NewRandPrivateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	A := e.PublicKey(k)
+	msg := testMessage(field)
+	sig := e.Sign(k, msg)
+	if !e.Verify(A, msg, sig) {
+		t.Fatalf("signature is expected to be valid")
+	}
+	msg2 := new(jubjub.FieldElement)
+	field.Add(msg2, msg, fromStr10(field, "1"))
+	if e.Verify(A, msg2, sig) {
+		t.Errorf("signature is not expected to be valid for another message")
+	}
+	if e.Verify(e.PublicKey(testPrivateKey()), msg, sig) {
+		t.Errorf("signature is not expected to be valid for another key")
+	}
+	bad := &Signature{R8: sig.R8, S: new(big.Int).Add(sig.S, e.curve.Order())}
+	if e.Verify(A, msg, bad) {
+		t.Errorf("non canonical s is not expected to be accepted")
+	}
+	if NewEdDSAMiMC(e.curve).Verify(A, msg, sig) {
+		t.Errorf("signature is not expected to be valid under another hash")
+	}
+}
+
+func BenchmarkEdDSAPoseidonSign(t *testing.B) {
+	e := NewEdDSAPoseidon(jubjub.NewBabyJubjub())
+	k := testPrivateKey()
+	msg := testMessage(e.field)
+	t.ResetTimer()
+	for i := 0; i < t.N; i++ {
+		e.Sign(k, msg)
+	}
+}
+
+func BenchmarkEdDSAPoseidonVerify(t *testing.B) {
+	e := NewEdDSAPoseidon(jubjub.NewBabyJubjub())
+	k := testPrivateKey()
+	msg := testMessage(e.field)
+	A := e.PublicKey(k)
+	sig := e.Sign(k, msg)
+	t.ResetTimer()
+	for i := 0; i < t.N; i++ {
+		e.Verify(A, msg, sig)
+	}
+}
@@ -20,6 +20,14 @@ type Field struct {
 	r1  *FieldElement
 	r2  *FieldElement
 	r3  *FieldElement
+	// Tonelli-Shanks constants
+	// p-1 = 2^s.t where t is odd
+	// t1  = (t+1)/2
+	// z   = c^t modp in Montgomery domain, c is a quadratic non-residue
+	s  int
+	t  *big.Int
+	t1 *big.Int
+	z  *FieldElement
 }
 
 // Given prime number as big.Int,
@@ -33,6 +41,18 @@ func NewField(pBig *big.Int) *Field {
 	r3 := new(FieldElement).Unmarshal(bn().Exp(r1Big, big3, pBig).Bytes())
 	rN1 := new(FieldElement).Unmarshal(bn().ModInverse(r1Big, pBig).Bytes())
 	p2 := new(FieldElement).Unmarshal(bn().Sub(pBig, big2).Bytes())
+	t := bn().Sub(pBig, big1)
+	s := 0
+	for t.Bit(0) == 0 {
+		t.Rsh(t, 1)
+		s++
+	}
+	c := bn().Set(big2)
+	for big.Jacobi(c, pBig) != -1 {
+		c.Add(c, big1)
+	}
+	z := bn().Exp(c, t, pBig)
+	z.Mul(z, r1Big).Mod(z, pBig)
 	return &Field{
 		p:   p,
 		inp: inp.Uint64(),
@@ -41,9 +61,20 @@ func NewField(pBig *big.Int) *Field {
 		rN1: rN1,
 		r2:  r2,
 		r3:  r3,
+		s:   s,
+		t:   t,
+		t1:  bn().Rsh(bn().Add(t, big1), 1),
+		z:   new(FieldElement).Unmarshal(z.Bytes()),
 	}
 }
 
+// Returns the modulus p
+func (f *Field) Modulus() *big.Int {
+	out := make([]byte, 32)
+	f.p.Marshal(out)
+	return bn().SetBytes(out)
+}
+
 // Returns new element in Montgomery domain
 func (f *Field) NewElement(in []byte) *FieldElement {
 	fe := new(FieldElement).Unmarshal(in)
@@ -51,6 +82,15 @@ func (f *Field) NewElement(in []byte) *FieldElement {
 	return fe
 }
 
+// Returns big endian bytes of a out of Montgomery domain
+func (f *Field) ToBytes(a *FieldElement) []byte {
+	out := make([]byte, 32)
+	c := new(FieldElement)
+	f.Mul(c, a, &FieldElement{1, 0, 0, 0})
+	c.Marshal(out)
+	return out
+}
+
 // Adapted from https://github.com/golang/go/blob/master/src/crypto/rand/util.go
 func (f *Field) RandElement(fe *FieldElement, r io.Reader) error {
 	// assuming p > 2^192
@@ -82,41 +122,24 @@ func (f *Field) RandElement(fe *FieldElement, r io.Reader) error {
 
 // c = (a + b) modp
 func (f *Field) Add(c, a, b *FieldElement) {
-	a0 := a[0]
-	a1 := a[1]
-	a2 := a[2]
-	a3 := a[3]
-	b0 := b[0]
-	b1 := b[1]
-	b2 := b[2]
-	b3 := b[3]
-	p0 := f.p[0]
-	p1 := f.p[1]
-	p2 := f.p[2]
-	p3 := f.p[3]
-	var e, e2, ne uint64
-
-	u0 := a0 + b0
-	e = (a0&b0 | (a0|b0)&^u0) >> 63
-	u1 := a1 + b1 + e
-	e = (a1&b1 | (a1|b1)&^u1) >> 63
-	u2 := a2 + b2 + e
-	e = (a2&b2 | (a2|b2)&^u2) >> 63
-	u3 := a3 + b3 + e
-	e = (a3&b3 | (a3|b3)&^u3) >> 63
-
-	v0 := u0 - p0
-	e2 = (^u0&p0 | (^u0|p0)&v0) >> 63
-	v1 := u1 - p1 - e2
-	e2 = (^u1&p1 | (^u1|p1)&v1) >> 63
-	v2 := u2 - p2 - e2
-	e2 = (^u2&p2 | (^u2|p2)&v2) >> 63
-	v3 := u3 - p3 - e2
-	e2 = (^u3&p3 | (^u3|p3)&v3) >> 63
-
-	e = e - e2
-	ne = ^e
+	f.add(c, a, b)
+}
+
+func (f *Field) addGeneric(c, a, b *FieldElement) {
+	u0, e := bits.Add64(a[0], b[0], 0)
+	u1, e := bits.Add64(a[1], b[1], e)
+	u2, e := bits.Add64(a[2], b[2], e)
+	u3, e := bits.Add64(a[3], b[3], e)
+
+	v0, e2 := bits.Sub64(u0, f.p[0], 0)
+	v1, e2 := bits.Sub64(u1, f.p[1], e2)
+	v2, e2 := bits.Sub64(u2, f.p[2], e2)
+	v3, e2 := bits.Sub64(u3, f.p[3], e2)
+	_, e2 = bits.Sub64(e, 0, e2)
 
+	// u if u < p, v otherwise
+	e = -e2
+	ne := ^e
 	c[0] = (u0 & e) | (v0 & ne)
 	c[1] = (u1 & e) | (v1 & ne)
 	c[2] = (u2 & e) | (v2 & ne)
@@ -125,34 +148,21 @@ func (f *Field) Add(c, a, b *FieldElement) {
 
 // c = (a + a) modp
 func (f *Field) Double(c, a *FieldElement) {
-
-	a0 := a[0]
-	a1 := a[1]
-	a2 := a[2]
-	a3 := a[3]
-	p0 := f.p[0]
-	p1 := f.p[1]
-	p2 := f.p[2]
-	p3 := f.p[3]
-
-	e := a3 >> 63
-	u3 := a3<<1 | a2>>63
-	u2 := a2<<1 | a1>>63
-	u1 := a1<<1 | a0>>63
-	u0 := a0 << 1
-
-	v0 := u0 - p0
-	e2 := (^u0&p0 | (^u0|p0)&v0) >> 63
-	v1 := u1 - p1 - e2
-	e2 = (^u1&p1 | (^u1|p1)&v1) >> 63
-	v2 := u2 - p2 - e2
-	e2 = (^u2&p2 | (^u2|p2)&v2) >> 63
-	v3 := u3 - p3 - e2
-	e2 = (^u3&p3 | (^u3|p3)&v3) >> 63
-
-	e = e - e2
+	e := a[3] >> 63
+	u3 := a[3]<<1 | a[2]>>63
+	u2 := a[2]<<1 | a[1]>>63
+	u1 := a[1]<<1 | a[0]>>63
+	u0 := a[0] << 1
+
+	v0, e2 := bits.Sub64(u0, f.p[0], 0)
+	v1, e2 := bits.Sub64(u1, f.p[1], e2)
+	v2, e2 := bits.Sub64(u2, f.p[2], e2)
+	v3, e2 := bits.Sub64(u3, f.p[3], e2)
+	_, e2 = bits.Sub64(e, 0, e2)
+
+	// u if u < p, v otherwise
+	e = -e2
 	ne := ^e
-
 	c[0] = (u0 & e) | (v0 & ne)
 	c[1] = (u1 & e) | (v1 & ne)
 	c[2] = (u2 & e) | (v2 & ne)
@@ -161,44 +171,22 @@ func (f *Field) Double(c, a *FieldElement) {
 
 // c = (a - b) modp
 func (f *Field) Sub(c, a, b *FieldElement) {
-	a0 := a[0]
-	a1 := a[1]
-	a2 := a[2]
-	a3 := a[3]
-	b0 := b[0]
-	b1 := b[1]
-	b2 := b[2]
-	b3 := b[3]
-	p0 := f.p[0]
-	p1 := f.p[1]
-	p2 := f.p[2]
-	p3 := f.p[3]
+	f.sub(c, a, b)
+}
 
-	var e, e2, ne uint64
-
-	u0 := a0 - b0
-	e = (^a0&b0 | (^a0|b0)&u0) >> 63
-	u1 := a1 - b1 - e
-	e = (^a1&b1 | (^a1|b1)&u1) >> 63
-	u2 := a2 - b2 - e
-	e = (^a2&b2 | (^a2|b2)&u2) >> 63
-	u3 := a3 - b3 - e
-	e = (^a3&b3 | (^a3|b3)&u3) >> 63
-
-	v0 := u0 + p0
-	e2 = (u0&p0 | (u0|p0)&^v0) >> 63
-	v1 := u1 + p1 + e2
-	e2 = (u1&p1 | (u1|p1)&^v1) >> 63
-	v2 := u2 + p2 + e2
-	e2 = (u2&p2 | (u2|p2)&^v2) >> 63
-	v3 := u3 + p3 + e2
-
-	e--
-	ne = ^e
-	c[0] = (u0 & e) | (v0 & ne)
-	c[1] = (u1 & e) | (v1 & ne)
-	c[2] = (u2 & e) | (v2 & ne)
-	c[3] = (u3 & e) | (v3 & ne)
+func (f *Field) subGeneric(c, a, b *FieldElement) {
+	var e2 uint64
+	u0, e := bits.Sub64(a[0], b[0], 0)
+	u1, e := bits.Sub64(a[1], b[1], e)
+	u2, e := bits.Sub64(a[2], b[2], e)
+	u3, e := bits.Sub64(a[3], b[3], e)
+
+	// u + p if a < b, u otherwise
+	e = -e
+	c[0], e2 = bits.Add64(u0, f.p[0]&e, 0)
+	c[1], e2 = bits.Add64(u1, f.p[1]&e, e2)
+	c[2], e2 = bits.Add64(u2, f.p[2]&e, e2)
+	c[3], _ = bits.Add64(u3, f.p[3]&e, e2)
 }
 
 func (f *Field) Neg(c, a *FieldElement) {
@@ -207,18 +195,79 @@ func (f *Field) Neg(c, a *FieldElement) {
 
 // Sets c as a^2(R^-1) modp
 func (f *Field) Square(c, a *FieldElement) {
+	f.square(c, a)
+}
+
+// Sets c as ab(R^-1) modp
+func (f *Field) Mul(c, a, b *FieldElement) {
+	f.mul(c, a, b)
+}
+
+func (f *Field) squareGeneric(c, a *FieldElement) {
 	var T [8]uint64
 	square256(&T, *a)
 	f.montReduce(c, T)
 }
 
-// Sets c as ab(R^-1) modp
-func (f *Field) Mul(c, a, b *FieldElement) {
+func (f *Field) mulGeneric(c, a, b *FieldElement) {
 	var T [8]uint64
 	mul256(&T, *a, *b)
 	f.montReduce(c, T)
 }
 
+// Sets c as a^e modp, a and c are in Montgomery domain
+func (f *Field) Exp(c, a *FieldElement, e *big.Int) {
+	z := new(FieldElement).Set(f.r1)
+	b := new(FieldElement).Set(a)
+	for i := e.BitLen() - 1; i >= 0; i-- {
+		f.Square(z, z)
+		if e.Bit(i) == 1 {
+			f.Mul(z, z, b)
+		}
+	}
+	c.Set(z)
+}
+
+// Tonelli-Shanks square root
+// Sets c as the square root of a and returns true
+// if a is a quadratic residue, otherwise c is not modified.
+// a and c are in Montgomery domain
+func (f *Field) Sqrt(c, a *FieldElement) bool {
+	if a.IsZero() {
+		c.Set(a)
+		return true
+	}
+	x, b, z := new(FieldElement), new(FieldElement), new(FieldElement)
+	t := new(FieldElement)
+	f.Exp(x, a, f.t1)
+	f.Exp(b, a, f.t)
+	z.Set(f.z)
+	m := f.s
+	for !b.Eq(f.r1) {
+		// least i such that b^(2^i) = 1
+		i := 0
+		t.Set(b)
+		for !t.Eq(f.r1) {
+			f.Square(t, t)
+			i++
+			if i == m {
+				return false
+			}
+		}
+		// w = z^(2^(m-i-1))
+		t.Set(z)
+		for j := 0; j < m-i-1; j++ {
+			f.Square(t, t)
+		}
+		f.Mul(x, x, t)
+		f.Square(z, t)
+		f.Mul(b, b, z)
+		m = i
+	}
+	c.Set(x)
+	return true
+}
+
 // Reduces T as T (R^-1) modp
 // Handbook of Applied Cryptography
 // Hankerson, Menezes, Vanstone
@@ -236,162 +285,53 @@ func (f *Field) montReduce(c *FieldElement, w [8]uint64) {
 	p1 := f.p[1]
 	p2 := f.p[2]
 	p3 := f.p[3]
-	var e1, e2, el, res uint64
-	var t1, t2, u uint64
+	var e1, el, u uint64
 
 	// i = 0
 	u = w0 * f.inp
-	//
-	e1, res = mul64(u, p0)
-	t1 = res + w0
-	e1 += (res&w0 | (res|w0)&^t1) >> 63
-	w0 = t1
-	//
-	e2, res = mul64(u, p1)
-	t1 = res + e1
-	e2 += (res&e1 | (res|e1)&^t1) >> 63
-	t2 = t1 + w1
-	e2 += (t1&w1 | (t1|w1)&^t2) >> 63
-	w1 = t2
-	//
-	e1, res = mul64(u, p2)
-	t1 = res + e2
-	e1 += (res&e2 | (res|e2)&^t1) >> 63
-	t2 = t1 + w2
-	e1 += (t1&w2 | (t1|w2)&^t2) >> 63
-	w2 = t2
-	//
-	e2, res = mul64(u, p3)
-	t1 = res + e1
-	e2 += (res&e1 | (res|e1)&^t1) >> 63
-	t2 = t1 + w3
-	e2 += (t1&w3 | (t1|w3)&^t2) >> 63
-	w3 = t2
-	//
-	t1 = w4 + el
-	e1 = (w4&el | (w4|el)&^t1) >> 63
-	t2 = t1 + e2
-	e1 += (t1&e2 | (t1|e2)&^t2) >> 63
-	w4 = t2
-	el = e1
+	e1, _ = madd(u, p0, w0, 0)
+	e1, w1 = madd(u, p1, w1, e1)
+	e1, w2 = madd(u, p2, w2, e1)
+	e1, w3 = madd(u, p3, w3, e1)
+	w4, el = bits.Add64(w4, e1, 0)
 
 	// i = 1
 	u = w1 * f.inp
-	//
-	e1, res = mul64(u, p0)
-	t1 = res + w1
-	e1 += (res&w1 | (res|w1)&^t1) >> 63
-	w1 = t1
-	//
-	e2, res = mul64(u, p1)
-	t1 = res + e1
-	e2 += (res&e1 | (res|e1)&^t1) >> 63
-	t2 = t1 + w2
-	e2 += (t1&w2 | (t1|w2)&^t2) >> 63
-	w2 = t2
-	//
-	e1, res = mul64(u, p2)
-	t1 = res + e2
-	e1 += (res&e2 | (res|e2)&^t1) >> 63
-	t2 = t1 + w3
-	e1 += (t1&w3 | (t1|w3)&^t2) >> 63
-	w3 = t2
-	//
-	e2, res = mul64(u, p3)
-	t1 = res + e1
-	e2 += (res&e1 | (res|e1)&^t1) >> 63
-	t2 = t1 + w4
-	e2 += (t1&w4 | (t1|w4)&^t2) >> 63
-	w4 = t2
-	//
-	t1 = w5 + el
-	e1 = (w5&el | (w5|el)&^t1) >> 63
-	t2 = t1 + e2
-	e1 += (t1&e2 | (t1|e2)&^t2) >> 63
-	w5 = t2
-	el = e1
+	e1, _ = madd(u, p0, w1, 0)
+	e1, w2 = madd(u, p1, w2, e1)
+	e1, w3 = madd(u, p2, w3, e1)
+	e1, w4 = madd(u, p3, w4, e1)
+	w5, el = bits.Add64(w5, e1, el)
 
 	// i = 2
 	u = w2 * f.inp
-	//
-	e1, res = mul64(u, p0)
-	t1 = res + w2
-	e1 += (res&w2 | (res|w2)&^t1) >> 63
-	w2 = t1
-	//
-	e2, res = mul64(u, p1)
-	t1 = res + e1
-	e2 += (res&e1 | (res|e1)&^t1) >> 63
-	t2 = t1 + w3
-	e2 += (t1&w3 | (t1|w3)&^t2) >> 63
-	w3 = t2
-	//
-	e1, res = mul64(u, p2)
-	t1 = res + e2
-	e1 += (res&e2 | (res|e2)&^t1) >> 63
-	t2 = t1 + w4
-	e1 += (t1&w4 | (t1|w4)&^t2) >> 63
-	w4 = t2
-	//
-	e2, res = mul64(u, p3)
-	t1 = res + e1
-	e2 += (res&e1 | (res|e1)&^t1) >> 63
-	t2 = t1 + w5
-	e2 += (t1&w5 | (t1|w5)&^t2) >> 63
-	w5 = t2
-	//
-	t1 = w6 + el
-	e1 = (w6&el | (w6|el)&^t1) >> 63
-	t2 = t1 + e2
-	e1 += (t1&e2 | (t1|e2)&^t2) >> 63
-	w6 = t2
-	el = e1
+	e1, _ = madd(u, p0, w2, 0)
+	e1, w3 = madd(u, p1, w3, e1)
+	e1, w4 = madd(u, p2, w4, e1)
+	e1, w5 = madd(u, p3, w5, e1)
+	w6, el = bits.Add64(w6, e1, el)
 
 	// i = 3
 	u = w3 * f.inp
-	//
-	e1, res = mul64(u, p0)
-	t1 = res + w3
-	e1 += (res&w3 | (res|w3)&^t1) >> 63
-	w3 = t1
-	//
-	e2, res = mul64(u, p1)
-	t1 = res + e1
-	e2 += (res&e1 | (res|e1)&^t1) >> 63
-	t2 = t1 + w4
-	e2 += (t1&w4 | (t1|w4)&^t2) >> 63
-	w4 = t2
-	//
-	e1, res = mul64(u, p2)
-	t1 = res + e2
-	e1 += (res&e2 | (res|e2)&^t1) >> 63
-	t2 = t1 + w5
-	e1 += (t1&w5 | (t1|w5)&^t2) >> 63
-	w5 = t2
-	//
-	e2, res = mul64(u, p3)
-	t1 = res + e1
-	e2 += (res&e1 | (res|e1)&^t1) >> 63
-	t2 = t1 + w6
-	e2 += (t1&w6 | (t1|w6)&^t2) >> 63
-	w6 = t2
-	//
-	t1 = w7 + el
-	e1 = (w7&el | (w7|el)&^t1) >> 63
-	t2 = t1 + e2
-	e1 += (t1&e2 | (t1|e2)&^t2) >> 63
-	w7 = t2
-
-	e1--
-	c[0] = w4 - ((p0) & ^e1)
-	e2 = (^w4&p0 | (^w4|p0)&c[0]) >> 63
-	c[1] = w5 - ((p1 + e2) & ^e1)
-	e2 = (^w5&p1 | (^w5|p1)&c[1]) >> 63
-	c[2] = w6 - ((p2 + e2) & ^e1)
-	e2 = (^w6&p2 | (^w6|p2)&c[2]) >> 63
-	c[3] = w7 - ((p3 + e2) & ^e1)
-
-	f.Sub(c, c, f.p)
+	e1, _ = madd(u, p0, w3, 0)
+	e1, w4 = madd(u, p1, w4, e1)
+	e1, w5 = madd(u, p2, w5, e1)
+	e1, w6 = madd(u, p3, w6, e1)
+	w7, el = bits.Add64(w7, e1, el)
+
+	// (el, w7, w6, w5, w4) < 2p
+	v0, e2 := bits.Sub64(w4, p0, 0)
+	v1, e2 := bits.Sub64(w5, p1, e2)
+	v2, e2 := bits.Sub64(w6, p2, e2)
+	v3, e2 := bits.Sub64(w7, p3, e2)
+	_, e2 = bits.Sub64(el, 0, e2)
+
+	e1 = -e2
+	ne := ^e1
+	c[0] = (w4 & e1) | (v0 & ne)
+	c[1] = (w5 & e1) | (v1 & ne)
+	c[2] = (w6 & e1) | (v2 & ne)
+	c[3] = (w7 & e1) | (v3 & ne)
 }
 
 // Guide to Elliptic Curve Cryptography Algorithm
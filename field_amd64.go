@@ -0,0 +1,42 @@
+//go:build amd64 && !pure_go
+// +build amd64,!pure_go
+
+package jubjub
+
+import "golang.org/x/sys/cpu"
+
+// MULX, ADCX and ADOX are required by the assembly backend
+var supportADX = cpu.X86.HasADX && cpu.X86.HasBMI2
+
+// implemented in field_amd64.s
+// requires p < 2^255 and a.b < p.2^256
+//
+//go:noescape
+func montMulADX(c, a, b, p *FieldElement, inp uint64)
+
+//go:noescape
+func montSquareADX(c, a, p *FieldElement, inp uint64)
+
+func (f *Field) add(c, a, b *FieldElement) {
+	f.addGeneric(c, a, b)
+}
+
+func (f *Field) sub(c, a, b *FieldElement) {
+	f.subGeneric(c, a, b)
+}
+
+func (f *Field) mul(c, a, b *FieldElement) {
+	if supportADX && f.p[3]>>63 == 0 {
+		montMulADX(c, a, b, f.p, f.inp)
+		return
+	}
+	f.mulGeneric(c, a, b)
+}
+
+func (f *Field) square(c, a *FieldElement) {
+	if supportADX && f.p[3]>>63 == 0 {
+		montSquareADX(c, a, f.p, f.inp)
+		return
+	}
+	f.squareGeneric(c, a)
+}
@@ -0,0 +1,119 @@
+//go:build amd64 && !pure_go
+// +build amd64,!pure_go
+
+#include "textflag.h"
+
+// Coarsely Integrated Operand Scanning (CIOS) Montgomery multiplication
+// Analyzing and Comparing Montgomery Multiplication Algorithms
+// Koc, Acar, Kaliski
+//
+// MULX leaves flags untouched, ADCX and ADOX carry through CF and OF
+// respectively, so two independent carry chains are interleaved.
+//
+// SI = a, DI = b, R10 = p, R11 = 0, R12 = inp
+// t = (R13:R8:BX:CX:R15:R14)
+// AX, R9 are used as scratch, DX holds the current multiplier word
+
+// t = t + a * DX
+#define MUL_WORD() \
+	XORQ  AX, AX       \
+	MULXQ 0(SI), AX, R9  \
+	ADOXQ AX, R14      \
+	ADCXQ R9, R15      \
+	MULXQ 8(SI), AX, R9  \
+	ADOXQ AX, R15      \
+	ADCXQ R9, CX       \
+	MULXQ 16(SI), AX, R9 \
+	ADOXQ AX, CX       \
+	ADCXQ R9, BX       \
+	MULXQ 24(SI), AX, R9 \
+	ADOXQ AX, BX       \
+	ADCXQ R9, R8       \
+	ADOXQ R11, R8      \
+	MOVQ  $0, R13      \
+	ADCXQ R11, R13     \
+	ADOXQ R11, R13
+
+// t = (t + m * p) / 2^64 where m = t0 * inp mod 2^64
+#define REDUCE_WORD() \
+	MOVQ  R12, DX       \
+	IMULQ R14, DX       \
+	XORQ  AX, AX        \
+	MULXQ 0(R10), AX, R9  \
+	ADCXQ R14, AX       \
+	MOVQ  R9, R14       \
+	ADCXQ R15, R14      \
+	MULXQ 8(R10), AX, R15 \
+	ADOXQ AX, R14       \
+	ADCXQ CX, R15       \
+	MULXQ 16(R10), AX, CX \
+	ADOXQ AX, R15       \
+	ADCXQ BX, CX        \
+	MULXQ 24(R10), AX, BX \
+	ADOXQ AX, CX        \
+	ADCXQ R8, BX        \
+	ADOXQ R11, BX       \
+	MOVQ  R13, R8       \
+	ADCXQ R11, R8       \
+	ADOXQ R11, R8
+
+#define MONT_MUL() \
+	XORQ R11, R11    \
+	XORQ R14, R14    \
+	XORQ R15, R15    \
+	XORQ CX, CX      \
+	XORQ BX, BX      \
+	XORQ R8, R8      \
+	MOVQ 0(DI), DX   \
+	MUL_WORD()       \
+	REDUCE_WORD()    \
+	MOVQ 8(DI), DX   \
+	MUL_WORD()       \
+	REDUCE_WORD()    \
+	MOVQ 16(DI), DX  \
+	MUL_WORD()       \
+	REDUCE_WORD()    \
+	MOVQ 24(DI), DX  \
+	MUL_WORD()       \
+	REDUCE_WORD()
+
+// c = t - p if t >= p else t
+#define FINAL_SUB() \
+	MOVQ    R14, AX     \
+	MOVQ    R15, R9     \
+	MOVQ    CX, DX      \
+	MOVQ    BX, SI      \
+	SUBQ    0(R10), AX  \
+	SBBQ    8(R10), R9  \
+	SBBQ    16(R10), DX \
+	SBBQ    24(R10), SI \
+	SBBQ    $0, R8      \
+	CMOVQCS R14, AX     \
+	CMOVQCS R15, R9     \
+	CMOVQCS CX, DX      \
+	CMOVQCS BX, SI      \
+	MOVQ    c+0(FP), DI \
+	MOVQ    AX, 0(DI)   \
+	MOVQ    R9, 8(DI)   \
+	MOVQ    DX, 16(DI)  \
+	MOVQ    SI, 24(DI)
+
+// func montMulADX(c, a, b, p *FieldElement, inp uint64)
+TEXT ·montMulADX(SB), NOSPLIT, $0-40
+	MOVQ a+8(FP), SI
+	MOVQ b+16(FP), DI
+	MOVQ p+24(FP), R10
+	MOVQ inp+32(FP), R12
+	MONT_MUL()
+	FINAL_SUB()
+	RET
+
+// func montSquareADX(c, a, p *FieldElement, inp uint64)
+TEXT ·montSquareADX(SB), NOSPLIT, $0-32
+	MOVQ a+8(FP), SI
+	MOVQ SI, DI
+	MOVQ p+16(FP), R10
+	MOVQ inp+24(FP), R12
+	MONT_MUL()
+	FINAL_SUB()
+	RET
@@ -0,0 +1,80 @@
+//go:build amd64 && !pure_go
+// +build amd64,!pure_go
+
+package jubjub
+
+import (
+	"crypto/rand"
+	"testing"
+)
+
+func TestBoxMontgomeryMultiplicationADX(t *testing.T) {
+	if !supportADX {
+		t.Skip("ADX/BMI2 is not supported")
+	}
+	p := bigFromStr16("0x73eda753299d7d483339d80809a1d80553bda402fffe5bfeffffffff00000001")
+	field := NewField(p)
+	var a, b, c1, c2 FieldElement
+	for i := 0; i < nBox; i++ {
+		field.RandElement(&a, rand.Reader)
+		field.RandElement(&b, rand.Reader)
+		montMulADX(&c1, &a, &b, field.p, field.inp)
+		field.mulGeneric(&c2, &a, &b)
+		if !c1.Eq(&c2) {
+			t.Errorf("adx multiplication fails a:%s, b:%s, have:%s, want:%s",
+				a.String(), b.String(), c1.String(), c2.String())
+		}
+	}
+}
+
+func TestBoxMontgomerySquareADX(t *testing.T) {
+	if !supportADX {
+		t.Skip("ADX/BMI2 is not supported")
+	}
+	p := bigFromStr16("0x73eda753299d7d483339d80809a1d80553bda402fffe5bfeffffffff00000001")
+	field := NewField(p)
+	var a, c1, c2 FieldElement
+	for i := 0; i < nBox; i++ {
+		field.RandElement(&a, rand.Reader)
+		montSquareADX(&c1, &a, field.p, field.inp)
+		field.squareGeneric(&c2, &a)
+		if !c1.Eq(&c2) {
+			t.Errorf("adx squaring fails a:%s, have:%s, want:%s",
+				a.String(), c1.String(), c2.String())
+		}
+	}
+}
+
+func TestMontgomeryMultiplicationADXEdges(t *testing.T) {
+	if !supportADX {
+		t.Skip("ADX/BMI2 is not supported")
+	}
+	p := bigFromStr16("0x73eda753299d7d483339d80809a1d80553bda402fffe5bfeffffffff00000001")
+	field := NewField(p)
+	pMinus1 := new(FieldElement).Set(field.p)
+	pMinus1[0]--
+	edges := []*FieldElement{
+		{0, 0, 0, 0},
+		{1, 0, 0, 0},
+		pMinus1,
+		field.r1,
+		field.r2,
+	}
+	var c1, c2 FieldElement
+	for _, a := range edges {
+		for _, b := range edges {
+			montMulADX(&c1, a, b, field.p, field.inp)
+			field.mulGeneric(&c2, a, b)
+			if !c1.Eq(&c2) {
+				t.Errorf("adx multiplication fails a:%s, b:%s, have:%s, want:%s",
+					a.String(), b.String(), c1.String(), c2.String())
+			}
+		}
+	}
+	a := &FieldElement{0xffffffffffffffff, 0xffffffffffffffff, 0xffffffffffffffff, 0xffffffffffffffff}
+	montMulADX(&c1, a, field.r1, field.p, field.inp)
+	field.mulGeneric(&c2, a, field.r1)
+	if !c1.Eq(&c2) {
+		t.Errorf("adx modular reduction fails, have:%s, want:%s", c1.String(), c2.String())
+	}
+}
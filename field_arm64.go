@@ -0,0 +1,34 @@
+//go:build arm64 && !pure_go
+// +build arm64,!pure_go
+
+package jubjub
+
+// implemented in field_arm64.s
+//
+//go:noescape
+func montMulARM64(c, a, b, p *FieldElement, inp uint64)
+
+//go:noescape
+func montSquareARM64(c, a, p *FieldElement, inp uint64)
+
+//go:noescape
+func addARM64(c, a, b, p *FieldElement)
+
+//go:noescape
+func subARM64(c, a, b, p *FieldElement)
+
+func (f *Field) add(c, a, b *FieldElement) {
+	addARM64(c, a, b, f.p)
+}
+
+func (f *Field) sub(c, a, b *FieldElement) {
+	subARM64(c, a, b, f.p)
+}
+
+func (f *Field) mul(c, a, b *FieldElement) {
+	montMulARM64(c, a, b, f.p, f.inp)
+}
+
+func (f *Field) square(c, a *FieldElement) {
+	montSquareARM64(c, a, f.p, f.inp)
+}
@@ -0,0 +1,193 @@
+//go:build arm64 && !pure_go
+// +build arm64,!pure_go
+
+#include "textflag.h"
+
+// Coarsely Integrated Operand Scanning (CIOS) Montgomery multiplication
+// Analyzing and Comparing Montgomery Multiplication Algorithms
+// Koc, Acar, Kaliski
+//
+// a = (R4:R3:R2:R1), p = (R8:R7:R6:R5), inp = R9
+// t = (R15:R14:R13:R12:R11:R10)
+// R0 holds the address of b, R16 the current multiplier word
+// low words of the partial products are kept in R17, R19, R20, R21
+// high words in R22, R23, R24, R25
+
+// t = t + a * R16
+#define MUL_WORD() \
+	MUL   R1, R16, R17 \
+	MUL   R2, R16, R19 \
+	MUL   R3, R16, R20 \
+	MUL   R4, R16, R21 \
+	UMULH R1, R16, R22 \
+	UMULH R2, R16, R23 \
+	UMULH R3, R16, R24 \
+	UMULH R4, R16, R25 \
+	ADDS  R17, R10     \
+	ADCS  R19, R11     \
+	ADCS  R20, R12     \
+	ADCS  R21, R13     \
+	ADCS  ZR, R14      \
+	ADC   ZR, ZR, R15  \
+	ADDS  R22, R11     \
+	ADCS  R23, R12     \
+	ADCS  R24, R13     \
+	ADCS  R25, R14     \
+	ADC   ZR, R15
+
+// t = (t + m * p) / 2^64 where m = t0 * inp mod 2^64
+#define REDUCE_WORD() \
+	MUL   R9, R10, R16 \
+	MUL   R5, R16, R17 \
+	MUL   R6, R16, R19 \
+	MUL   R7, R16, R20 \
+	MUL   R8, R16, R21 \
+	UMULH R5, R16, R22 \
+	UMULH R6, R16, R23 \
+	UMULH R7, R16, R24 \
+	UMULH R8, R16, R25 \
+	ADDS  R17, R10     \
+	ADCS  R19, R11     \
+	ADCS  R20, R12     \
+	ADCS  R21, R13     \
+	ADCS  ZR, R14      \
+	ADC   ZR, R15      \
+	ADDS  R22, R11     \
+	ADCS  R23, R12     \
+	ADCS  R24, R13     \
+	ADCS  R25, R14     \
+	ADC   ZR, R15      \
+	MOVD  R11, R10     \
+	MOVD  R12, R11     \
+	MOVD  R13, R12     \
+	MOVD  R14, R13     \
+	MOVD  R15, R14
+
+#define MONT_MUL() \
+	MOVD  ZR, R10     \
+	MOVD  ZR, R11     \
+	MOVD  ZR, R12     \
+	MOVD  ZR, R13     \
+	MOVD  ZR, R14     \
+	MOVD  0(R0), R16  \
+	MUL_WORD()        \
+	REDUCE_WORD()     \
+	MOVD  8(R0), R16  \
+	MUL_WORD()        \
+	REDUCE_WORD()     \
+	MOVD  16(R0), R16 \
+	MUL_WORD()        \
+	REDUCE_WORD()     \
+	MOVD  24(R0), R16 \
+	MUL_WORD()        \
+	REDUCE_WORD()
+
+// c = t - p if t >= p else t
+#define FINAL_SUB() \
+	SUBS R5, R10, R17      \
+	SBCS R6, R11, R19      \
+	SBCS R7, R12, R20      \
+	SBCS R8, R13, R21      \
+	SBCS ZR, R14, R14      \
+	CSEL CS, R17, R10, R10 \
+	CSEL CS, R19, R11, R11 \
+	CSEL CS, R20, R12, R12 \
+	CSEL CS, R21, R13, R13 \
+	MOVD c+0(FP), R0       \
+	STP  (R10, R11), 0(R0) \
+	STP  (R12, R13), 16(R0)
+
+// func montMulARM64(c, a, b, p *FieldElement, inp uint64)
+TEXT ·montMulARM64(SB), NOSPLIT, $0-40
+	MOVD a+8(FP), R0
+	LDP  0(R0), (R1, R2)
+	LDP  16(R0), (R3, R4)
+	MOVD p+24(FP), R0
+	LDP  0(R0), (R5, R6)
+	LDP  16(R0), (R7, R8)
+	MOVD inp+32(FP), R9
+	MOVD b+16(FP), R0
+	MONT_MUL()
+	FINAL_SUB()
+	RET
+
+// func montSquareARM64(c, a, p *FieldElement, inp uint64)
+TEXT ·montSquareARM64(SB), NOSPLIT, $0-32
+	MOVD p+16(FP), R0
+	LDP  0(R0), (R5, R6)
+	LDP  16(R0), (R7, R8)
+	MOVD inp+24(FP), R9
+	MOVD a+8(FP), R0
+	LDP  0(R0), (R1, R2)
+	LDP  16(R0), (R3, R4)
+	MONT_MUL()
+	FINAL_SUB()
+	RET
+
+// func addARM64(c, a, b, p *FieldElement)
+TEXT ·addARM64(SB), NOSPLIT, $0-32
+	MOVD a+8(FP), R0
+	LDP  0(R0), (R1, R2)
+	LDP  16(R0), (R3, R4)
+	MOVD b+16(FP), R0
+	LDP  0(R0), (R5, R6)
+	LDP  16(R0), (R7, R8)
+	MOVD p+24(FP), R0
+	LDP  0(R0), (R9, R10)
+	LDP  16(R0), (R11, R12)
+
+	// u = a + b
+	ADDS R5, R1
+	ADCS R6, R2
+	ADCS R7, R3
+	ADCS R8, R4
+	ADC  ZR, ZR, R13
+
+	// v = u - p
+	SUBS R9, R1, R5
+	SBCS R10, R2, R6
+	SBCS R11, R3, R7
+	SBCS R12, R4, R8
+	SBCS ZR, R13, R13
+
+	// c = v if u >= p else u
+	CSEL CS, R5, R1, R1
+	CSEL CS, R6, R2, R2
+	CSEL CS, R7, R3, R3
+	CSEL CS, R8, R4, R4
+	MOVD c+0(FP), R0
+	STP  (R1, R2), 0(R0)
+	STP  (R3, R4), 16(R0)
+	RET
+
+// func subARM64(c, a, b, p *FieldElement)
+TEXT ·subARM64(SB), NOSPLIT, $0-32
+	MOVD a+8(FP), R0
+	LDP  0(R0), (R1, R2)
+	LDP  16(R0), (R3, R4)
+	MOVD b+16(FP), R0
+	LDP  0(R0), (R5, R6)
+	LDP  16(R0), (R7, R8)
+	MOVD p+24(FP), R0
+	LDP  0(R0), (R9, R10)
+	LDP  16(R0), (R11, R12)
+
+	// u = a - b
+	SUBS R5, R1
+	SBCS R6, R2
+	SBCS R7, R3
+	SBCS R8, R4
+
+	// c = u + p if a < b else u
+	CSEL CC, R9, ZR, R9
+	CSEL CC, R10, ZR, R10
+	CSEL CC, R11, ZR, R11
+	CSEL CC, R12, ZR, R12
+	ADDS R9, R1
+	ADCS R10, R2
+	ADCS R11, R3
+	ADC  R12, R4
+	MOVD c+0(FP), R0
+	STP  (R1, R2), 0(R0)
+	STP  (R3, R4), 16(R0)
+	RET
@@ -0,0 +1,194 @@
+//go:build arm64 && !pure_go
+// +build arm64,!pure_go
+
+package jubjub
+
+import (
+	"crypto/rand"
+	"testing"
+)
+
+// BLS12-381 scalar field and BN254 scalar field, base fields of Jubjub and Baby Jubjub
+func fieldsARM64() []*Field {
+	return []*Field{
+		NewField(bigFromStr16("0x73eda753299d7d483339d80809a1d80553bda402fffe5bfeffffffff00000001")),
+		NewField(bigFromStr16("0x30644e72e131a029b85045b68181585d2833e84879b9709143e1f593f0000001")),
+	}
+}
+
+// 0, 1, p-1, R mod p and R^2 mod p
+func fieldEdgesARM64(field *Field) []*FieldElement {
+	pMinus1 := new(FieldElement).Set(field.p)
+	pMinus1[0]--
+	return []*FieldElement{
+		{0, 0, 0, 0},
+		{1, 0, 0, 0},
+		pMinus1,
+		field.r1,
+		field.r2,
+	}
+}
+
+func TestBoxMontgomeryMultiplicationARM64(t *testing.T) {
+	for _, field := range fieldsARM64() {
+		var a, b, c1, c2 FieldElement
+		for i := 0; i < nBox; i++ {
+			field.RandElement(&a, rand.Reader)
+			field.RandElement(&b, rand.Reader)
+			montMulARM64(&c1, &a, &b, field.p, field.inp)
+			field.mulGeneric(&c2, &a, &b)
+			if !c1.Eq(&c2) {
+				t.Errorf("arm64 multiplication fails p:%s, a:%s, b:%s, have:%s, want:%s",
+					field.p.String(), a.String(), b.String(), c1.String(), c2.String())
+			}
+		}
+	}
+}
+
+func TestBoxMontgomerySquareARM64(t *testing.T) {
+	for _, field := range fieldsARM64() {
+		var a, c1, c2 FieldElement
+		for i := 0; i < nBox; i++ {
+			field.RandElement(&a, rand.Reader)
+			montSquareARM64(&c1, &a, field.p, field.inp)
+			field.squareGeneric(&c2, &a)
+			if !c1.Eq(&c2) {
+				t.Errorf("arm64 squaring fails p:%s, a:%s, have:%s, want:%s",
+					field.p.String(), a.String(), c1.String(), c2.String())
+			}
+		}
+	}
+}
+
+func TestBoxAdditionARM64(t *testing.T) {
+	for _, field := range fieldsARM64() {
+		var a, b, c1, c2 FieldElement
+		for i := 0; i < nBox; i++ {
+			field.RandElement(&a, rand.Reader)
+			field.RandElement(&b, rand.Reader)
+			addARM64(&c1, &a, &b, field.p)
+			field.addGeneric(&c2, &a, &b)
+			if !c1.Eq(&c2) {
+				t.Errorf("arm64 addition fails p:%s, a:%s, b:%s, have:%s, want:%s",
+					field.p.String(), a.String(), b.String(), c1.String(), c2.String())
+			}
+		}
+	}
+}
+
+func TestBoxSubtractionARM64(t *testing.T) {
+	for _, field := range fieldsARM64() {
+		var a, b, c1, c2 FieldElement
+		for i := 0; i < nBox; i++ {
+			field.RandElement(&a, rand.Reader)
+			field.RandElement(&b, rand.Reader)
+			subARM64(&c1, &a, &b, field.p)
+			field.subGeneric(&c2, &a, &b)
+			if !c1.Eq(&c2) {
+				t.Errorf("arm64 subtraction fails p:%s, a:%s, b:%s, have:%s, want:%s",
+					field.p.String(), a.String(), b.String(), c1.String(), c2.String())
+			}
+		}
+	}
+}
+
+func TestMontgomeryMultiplicationARM64Edges(t *testing.T) {
+	for _, field := range fieldsARM64() {
+		edges := fieldEdgesARM64(field)
+		var c1, c2 FieldElement
+		for _, a := range edges {
+			for _, b := range edges {
+				montMulARM64(&c1, a, b, field.p, field.inp)
+				field.mulGeneric(&c2, a, b)
+				if !c1.Eq(&c2) {
+					t.Errorf("arm64 multiplication fails p:%s, a:%s, b:%s, have:%s, want:%s",
+						field.p.String(), a.String(), b.String(), c1.String(), c2.String())
+				}
+			}
+			montSquareARM64(&c1, a, field.p, field.inp)
+			field.squareGeneric(&c2, a)
+			if !c1.Eq(&c2) {
+				t.Errorf("arm64 squaring fails p:%s, a:%s, have:%s, want:%s",
+					field.p.String(), a.String(), c1.String(), c2.String())
+			}
+		}
+		a := &FieldElement{0xffffffffffffffff, 0xffffffffffffffff, 0xffffffffffffffff, 0xffffffffffffffff}
+		montMulARM64(&c1, a, field.r1, field.p, field.inp)
+		field.mulGeneric(&c2, a, field.r1)
+		if !c1.Eq(&c2) {
+			t.Errorf("arm64 modular reduction fails p:%s, have:%s, want:%s", field.p.String(), c1.String(), c2.String())
+		}
+	}
+}
+
+func TestAdditionSubtractionARM64Edges(t *testing.T) {
+	for _, field := range fieldsARM64() {
+		edges := fieldEdgesARM64(field)
+		var c1, c2 FieldElement
+		for _, a := range edges {
+			for _, b := range edges {
+				addARM64(&c1, a, b, field.p)
+				field.addGeneric(&c2, a, b)
+				if !c1.Eq(&c2) {
+					t.Errorf("arm64 addition fails p:%s, a:%s, b:%s, have:%s, want:%s",
+						field.p.String(), a.String(), b.String(), c1.String(), c2.String())
+				}
+				subARM64(&c1, a, b, field.p)
+				field.subGeneric(&c2, a, b)
+				if !c1.Eq(&c2) {
+					t.Errorf("arm64 subtraction fails p:%s, a:%s, b:%s, have:%s, want:%s",
+						field.p.String(), a.String(), b.String(), c1.String(), c2.String())
+				}
+			}
+		}
+	}
+}
+
+// Assembly counterparts of the generic benchmarks in field_test.go
+
+func BenchmarkFieldMontgomeryMultiplicationARM64(t *testing.B) {
+	p := bigFromStr16("0x73eda753299d7d483339d80809a1d80553bda402fffe5bfeffffffff00000001")
+	field := NewField(p)
+	a := fe(nil, "0x6aaaaaaaaaaaaaaa44aa44aa44aa44aa91919191ffffff0000119999ffaa01aa")
+	b := fe(nil, "0x4fffffffffffffffdddddddd8888888888888888000000119a9a9a9a8b8b8b8b")
+	c := new(FieldElement)
+	t.ResetTimer()
+	for i := 0; i < t.N; i++ {
+		montMulARM64(c, a, b, field.p, field.inp)
+	}
+}
+
+func BenchmarkFieldMontgomerySquaringARM64(t *testing.B) {
+	p := bigFromStr16("0x73eda753299d7d483339d80809a1d80553bda402fffe5bfeffffffff00000001")
+	field := NewField(p)
+	a := fe(nil, "0x6aaaaaaaaaaaaaaa44aa44aa44aa44aa91919191ffffff0000119999ffaa01aa")
+	c := new(FieldElement)
+	t.ResetTimer()
+	for i := 0; i < t.N; i++ {
+		montSquareARM64(c, a, field.p, field.inp)
+	}
+}
+
+func BenchmarkFieldAdditionARM64(t *testing.B) {
+	p := bigFromStr16("0x73eda753299d7d483339d80809a1d80553bda402fffe5bfeffffffff00000001")
+	field := NewField(p)
+	a := fe(nil, "0x6aaaaaaaaaaaaaaa44aa44aa44aa44aa91919191ffffff0000119999ffaa01aa")
+	b := fe(nil, "0x4fffffffffffffffdddddddd8888888888888888000000119a9a9a9a8b8b8b8b")
+	c := new(FieldElement)
+	t.ResetTimer()
+	for i := 0; i < t.N; i++ {
+		addARM64(c, a, b, field.p)
+	}
+}
+
+func BenchmarkFieldSubtractionARM64(t *testing.B) {
+	p := bigFromStr16("0x73eda753299d7d483339d80809a1d80553bda402fffe5bfeffffffff00000001")
+	field := NewField(p)
+	a := fe(nil, "0x6aaaaaaaaaaaaaaa44aa44aa44aa44aa91919191ffffff0000119999ffaa01aa")
+	b := fe(nil, "0x4fffffffffffffffdddddddd8888888888888888000000119a9a9a9a8b8b8b8b")
+	c := new(FieldElement)
+	t.ResetTimer()
+	for i := 0; i < t.N; i++ {
+		subARM64(c, a, b, field.p)
+	}
+}
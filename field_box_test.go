@@ -2,6 +2,7 @@ package jubjub
 
 import (
 	"crypto/rand"
+	"math/big"
 	"testing"
 )
 
@@ -244,3 +245,36 @@ func TestBoxSquare(t *testing.T) {
 		}
 	}
 }
+
+func TestBoxGenericAgainstBig(t *testing.T) {
+	pBig := bigFromStr16("0x73eda753299d7d483339d80809a1d80553bda402fffe5bfeffffffff00000001")
+	field := NewField(pBig)
+	rInv := new(big.Int).ModInverse(new(big.Int).Lsh(big1, 256), pBig)
+	toBig := func(a *FieldElement) *big.Int {
+		out := make([]byte, 32)
+		a.Marshal(out)
+		return new(big.Int).SetBytes(out)
+	}
+	var a, b, c FieldElement
+	for i := 0; i < nBox/10; i++ {
+		field.RandElement(&a, rand.Reader)
+		field.RandElement(&b, rand.Reader)
+		x, y := toBig(&a), toBig(&b)
+		field.addGeneric(&c, &a, &b)
+		if e := new(big.Int).Add(x, y); e.Mod(e, pBig).Cmp(toBig(&c)) != 0 {
+			t.Errorf("generic addition fails a:%s, b:%s, have:%s", a.String(), b.String(), c.String())
+		}
+		field.subGeneric(&c, &a, &b)
+		if e := new(big.Int).Sub(x, y); e.Mod(e, pBig).Cmp(toBig(&c)) != 0 {
+			t.Errorf("generic subtraction fails a:%s, b:%s, have:%s", a.String(), b.String(), c.String())
+		}
+		field.mulGeneric(&c, &a, &b)
+		if e := new(big.Int).Mul(x, y); e.Mul(e, rInv).Mod(e, pBig).Cmp(toBig(&c)) != 0 {
+			t.Errorf("generic multiplication fails a:%s, b:%s, have:%s", a.String(), b.String(), c.String())
+		}
+		field.squareGeneric(&c, &a)
+		if e := new(big.Int).Mul(x, x); e.Mul(e, rInv).Mod(e, pBig).Cmp(toBig(&c)) != 0 {
+			t.Errorf("generic squaring fails a:%s, have:%s", a.String(), c.String())
+		}
+	}
+}
@@ -2,6 +2,7 @@ package jubjub
 
 import (
 	"fmt"
+	"math/bits"
 )
 
 type FieldElement [4]uint64
@@ -112,42 +113,18 @@ func (fe *FieldElement) leftShift() uint64 {
 
 func (fe *FieldElement) add(fe2 *FieldElement) uint64 {
 	var e uint64
-	a0, a1, a2, a3 := fe[0], fe[1], fe[2], fe[3]
-	b0, b1, b2, b3 := fe2[0], fe2[1], fe2[2], fe2[3]
-
-	r0 := a0 + b0
-	e = (a0&b0 | (a0|b0)&^r0) >> 63
-	r1 := a1 + b1 + e
-	e = (a1&b1 | (a1|b1)&^r1) >> 63
-	r2 := a2 + b2 + e
-	e = (a2&b2 | (a2|b2)&^r2) >> 63
-	r3 := a3 + b3 + e
-	e = (a3&b3 | (a3|b3)&^r3) >> 63
-
-	fe[0] = r0
-	fe[1] = r1
-	fe[2] = r2
-	fe[3] = r3
+	fe[0], e = bits.Add64(fe[0], fe2[0], 0)
+	fe[1], e = bits.Add64(fe[1], fe2[1], e)
+	fe[2], e = bits.Add64(fe[2], fe2[2], e)
+	fe[3], e = bits.Add64(fe[3], fe2[3], e)
 	return e
 }
 
 func (fe *FieldElement) sub(fe2 *FieldElement) uint64 {
 	var e uint64
-	a0, a1, a2, a3 := fe[0], fe[1], fe[2], fe[3]
-	b0, b1, b2, b3 := fe2[0], fe2[1], fe2[2], fe2[3]
-
-	diff0 := a0 - b0
-	e = (^a0&b0 | (^a0|b0)&diff0) >> 63
-	diff1 := a1 - b1 - e
-	e = (^a1&b1 | (^a1|b1)&diff1) >> 63
-	diff2 := a2 - b2 - e
-	e = (^a2&b2 | (^a2|b2)&diff2) >> 63
-	diff3 := a3 - b3 - e
-	e = (^a3&b3 | (^a3|b3)&diff3) >> 63
-
-	fe[0] = diff0
-	fe[1] = diff1
-	fe[2] = diff2
-	fe[3] = diff3
+	fe[0], e = bits.Sub64(fe[0], fe2[0], 0)
+	fe[1], e = bits.Sub64(fe[1], fe2[1], e)
+	fe[2], e = bits.Sub64(fe[2], fe2[2], e)
+	fe[3], e = bits.Sub64(fe[3], fe2[3], e)
 	return e
 }
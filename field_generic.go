@@ -0,0 +1,20 @@
+//go:build (!amd64 && !arm64) || pure_go
+// +build !amd64,!arm64 pure_go
+
+package jubjub
+
+func (f *Field) add(c, a, b *FieldElement) {
+	f.addGeneric(c, a, b)
+}
+
+func (f *Field) sub(c, a, b *FieldElement) {
+	f.subGeneric(c, a, b)
+}
+
+func (f *Field) mul(c, a, b *FieldElement) {
+	f.mulGeneric(c, a, b)
+}
+
+func (f *Field) square(c, a *FieldElement) {
+	f.squareGeneric(c, a)
+}
@@ -1,6 +1,7 @@
 package jubjub
 
 import (
+	"crypto/rand"
 	"encoding/binary"
 	"math/big"
 	"reflect"
@@ -426,6 +427,53 @@ func BenchmarkFieldMontgomerySquaring(t *testing.B) {
 	}
 }
 
+func BenchmarkFieldMontgomeryMultiplicationGeneric(t *testing.B) {
+	p := bigFromStr16("0x73eda753299d7d483339d80809a1d80553bda402fffe5bfeffffffff00000001")
+	field := NewField(p)
+	a := fe(nil, "0x6aaaaaaaaaaaaaaa44aa44aa44aa44aa91919191ffffff0000119999ffaa01aa")
+	b := fe(nil, "0x4fffffffffffffffdddddddd8888888888888888000000119a9a9a9a8b8b8b8b")
+	c := new(FieldElement)
+	t.ResetTimer()
+	for i := 0; i < t.N; i++ {
+		field.mulGeneric(c, a, b)
+	}
+}
+
+func BenchmarkFieldMontgomerySquaringGeneric(t *testing.B) {
+	p := bigFromStr16("0x73eda753299d7d483339d80809a1d80553bda402fffe5bfeffffffff00000001")
+	field := NewField(p)
+	a := fe(nil, "0x6aaaaaaaaaaaaaaa44aa44aa44aa44aa91919191ffffff0000119999ffaa01aa")
+	c := new(FieldElement)
+	t.ResetTimer()
+	for i := 0; i < t.N; i++ {
+		field.squareGeneric(c, a)
+	}
+}
+
+func BenchmarkFieldAdditionGeneric(t *testing.B) {
+	p := bigFromStr16("0x73eda753299d7d483339d80809a1d80553bda402fffe5bfeffffffff00000001")
+	field := NewField(p)
+	a := fe(nil, "0x6aaaaaaaaaaaaaaa44aa44aa44aa44aa91919191ffffff0000119999ffaa01aa")
+	b := fe(nil, "0x4fffffffffffffffdddddddd8888888888888888000000119a9a9a9a8b8b8b8b")
+	c := new(FieldElement)
+	t.ResetTimer()
+	for i := 0; i < t.N; i++ {
+		field.addGeneric(c, a, b)
+	}
+}
+
+func BenchmarkFieldSubtractionGeneric(t *testing.B) {
+	p := bigFromStr16("0x73eda753299d7d483339d80809a1d80553bda402fffe5bfeffffffff00000001")
+	field := NewField(p)
+	a := fe(nil, "0x6aaaaaaaaaaaaaaa44aa44aa44aa44aa91919191ffffff0000119999ffaa01aa")
+	b := fe(nil, "0x4fffffffffffffffdddddddd8888888888888888000000119a9a9a9a8b8b8b8b")
+	c := new(FieldElement)
+	t.ResetTimer()
+	for i := 0; i < t.N; i++ {
+		field.subGeneric(c, a, b)
+	}
+}
+
 func BenchmarkFieldInverse1(t *testing.B) {
 	p := bigFromStr16("0x73eda753299d7d483339d80809a1d80553bda402fffe5bfeffffffff00000001")
 	field := NewField(p)
@@ -458,3 +506,28 @@ func BenchmarkFieldInverse3(t *testing.B) {
 		field.InvMontUp(inv, a)
 	}
 }
+
+func TestFieldSquareRoot(t *testing.T) {
+	for _, pStr := range []string{
+		"0x73eda753299d7d483339d80809a1d80553bda402fffe5bfeffffffff00000001",
+		"0x30644e72e131a029b85045b68181585d2833e84879b9709143e1f593f0000001",
+	} {
+		field := NewField(bigFromStr16(pStr))
+		var a, b, c FieldElement
+		for i := 0; i < 100; i++ {
+			field.RandElement(&a, rand.Reader)
+			field.Square(&b, &a)
+			if !field.Sqrt(&c, &b) {
+				t.Fatalf("square root expected to exist")
+			}
+			field.Square(&c, &c)
+			if !c.Eq(&b) {
+				t.Errorf("bad square root, have: %s, want: %s", c.String(), b.String())
+			}
+		}
+		// z is a non residue
+		if field.Sqrt(&c, field.z) {
+			t.Errorf("square root is not expected to exist")
+		}
+	}
+}
@@ -0,0 +1,178 @@
+package frost
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/kilic/go-jubjub"
+	"github.com/kilic/go-jubjub/vss"
+)
+
+// Distributed key generation for FROST
+// FROST: Flexible Round-Optimized Schnorr Threshold Signatures, Komlo, Goldberg, Figure 1
+// Pedersen DKG where each participant proves knowledge of its secret term
+//
+// Round 1, participant i broadcasts C_i = ([a_i0]G, ..., [a_i(t-1)]G)
+// and a proof of knowledge (R_i, mu_i) of a_i0, mu_i = k + a_i0 c_i
+// where c_i = H*(contextString || "dkg" || SerializeScalar(i) || repr(C_i0) || repr(R_i))
+// Round 2, participant i sends f_i(j) to participant j privately
+// Round 3, participant j verifies received shares against commitments,
+// its signing share is sum_i f_i(j) and the group key is sum_i C_i0
+
+// Broadcast message of the first round
+type DKGPackage struct {
+	Identifier uint16
+	Commitment []*jubjub.ExtendedPoint
+	ProofR     *jubjub.ExtendedPoint
+	ProofMu    *jubjub.ScalarFieldElement
+}
+
+// Secret state of a participant between rounds
+type DKGSecret struct {
+	identifier   uint16
+	minSigners   int
+	maxSigners   int
+	coefficients vss.Polynomial
+	commitment   []*jubjub.ExtendedPoint
+}
+
+func (f *FROST) dkgChallenge(id uint16, c0, r *jubjub.ExtendedPoint) *jubjub.ScalarFieldElement {
+	return f.redJubjubHash([]byte(contextString), []byte("dkg"),
+		f.encodeIdentifier(id), f.encodePoint(c0), f.encodePoint(r))
+}
+
+func (f *FROST) DKGPart1(rand io.Reader, id uint16, minSigners, maxSigners int) (*DKGSecret, *DKGPackage, error) {
+	if err := checkParams(minSigners, maxSigners); err != nil {
+		return nil, nil, err
+	}
+	if id == 0 || int(id) > maxSigners {
+		return nil, nil, fmt.Errorf("bad identifier")
+	}
+	secret, err := f.scalarField.RandElement(rand)
+	if err != nil {
+		return nil, nil, err
+	}
+	coefficients, err := f.vss.RandPolynomial(rand, secret, minSigners-1)
+	if err != nil {
+		return nil, nil, err
+	}
+	state := &DKGSecret{
+		identifier:   id,
+		minSigners:   minSigners,
+		maxSigners:   maxSigners,
+		coefficients: coefficients,
+	}
+	for _, a := range coefficients {
+		state.commitment = append(state.commitment, f.mulBase(a))
+	}
+	k, err := f.scalarField.RandElement(rand)
+	if err != nil {
+		return nil, nil, err
+	}
+	pkg := &DKGPackage{
+		Identifier: id,
+		Commitment: state.commitment,
+		ProofR:     f.mulBase(k),
+	}
+	mu := f.dkgChallenge(id, state.commitment[0], pkg.ProofR)
+	f.scalarField.Mul(mu, mu, coefficients[0])
+	f.scalarField.Add(mu, mu, k)
+	pkg.ProofMu = mu
+	return state, pkg, nil
+}
+
+// Checks that packages are from all of the other participants and their proofs of knowledge
+func (f *FROST) checkDKGPackages(state *DKGSecret, packages []*DKGPackage) error {
+	if len(packages) != state.maxSigners-1 {
+		return fmt.Errorf("bad number of packages")
+	}
+	seen := make(map[uint16]bool)
+	for _, pkg := range packages {
+		if pkg == nil || pkg.ProofR == nil || pkg.ProofMu == nil {
+			return fmt.Errorf("malformed package")
+		}
+		id := pkg.Identifier
+		if id == 0 || int(id) > state.maxSigners || id == state.identifier || seen[id] {
+			return fmt.Errorf("bad package identifier %d", id)
+		}
+		seen[id] = true
+		if len(pkg.Commitment) != state.minSigners {
+			return fmt.Errorf("bad commitment size of participant %d", id)
+		}
+		for _, c := range pkg.Commitment {
+			if c == nil {
+				return fmt.Errorf("bad commitment of participant %d", id)
+			}
+		}
+		// R = [mu]G - [c]C_0
+		c := f.dkgChallenge(id, pkg.Commitment[0], pkg.ProofR)
+		r := f.curve.NewExtendedPoint()
+		f.curve.Mul(r, pkg.Commitment[0], c)
+		f.curve.Sub(r, f.mulBase(pkg.ProofMu), r)
+		if !r.Eq(pkg.ProofR) {
+			return fmt.Errorf("bad proof of knowledge of participant %d", id)
+		}
+	}
+	return nil
+}
+
+// Verifies the packages of the other participants
+// and returns the secret shares to send them keyed by identifier
+func (f *FROST) DKGPart2(state *DKGSecret, packages []*DKGPackage) (map[uint16]*jubjub.ScalarFieldElement, error) {
+	if err := f.checkDKGPackages(state, packages); err != nil {
+		return nil, err
+	}
+	shares := make(map[uint16]*jubjub.ScalarFieldElement)
+	for _, pkg := range packages {
+		shares[pkg.Identifier] = f.vss.Eval(state.coefficients, uint32(pkg.Identifier))
+	}
+	return shares, nil
+}
+
+// Verifies shares received from the other participants keyed by the sender identifier
+// and returns the key share of the participant and the public keys of the group
+func (f *FROST) DKGPart3(state *DKGSecret, packages []*DKGPackage, shares map[uint16]*jubjub.ScalarFieldElement) (*KeyShare, *PublicKeys, error) {
+	if err := f.checkDKGPackages(state, packages); err != nil {
+		return nil, nil, err
+	}
+	if len(shares) != len(packages) {
+		return nil, nil, fmt.Errorf("bad number of shares")
+	}
+	sk := f.vss.Eval(state.coefficients, uint32(state.identifier))
+	commitment := make([]*jubjub.ExtendedPoint, state.minSigners)
+	for i := range commitment {
+		commitment[i] = new(jubjub.ExtendedPoint).Set(state.commitment[i])
+	}
+	for _, pkg := range packages {
+		share, ok := shares[pkg.Identifier]
+		if !ok {
+			return nil, nil, fmt.Errorf("missing share of participant %d", pkg.Identifier)
+		}
+		if !f.mulBase(share).Eq(f.vss.EvalCommitment(pkg.Commitment, uint32(state.identifier))) {
+			return nil, nil, fmt.Errorf("bad share of participant %d", pkg.Identifier)
+		}
+		f.scalarField.Add(sk, sk, share)
+		for i := range commitment {
+			f.curve.Add(commitment[i], commitment[i], pkg.Commitment[i])
+		}
+	}
+	// the sum of commitments is the commitment to the shared polynomial
+	pub := &PublicKeys{
+		Commitment:      commitment,
+		VerifyingShares: make(map[uint16]*jubjub.ExtendedPoint),
+	}
+	for id := 1; id <= state.maxSigners; id++ {
+		pub.VerifyingShares[uint16(id)] = f.vss.EvalCommitment(commitment, uint32(id))
+	}
+	share := &KeyShare{
+		Identifier:     state.identifier,
+		MinSigners:     state.minSigners,
+		SigningShare:   sk,
+		VerifyingShare: pub.VerifyingShares[state.identifier],
+		GroupKey:       pub.GroupKey(),
+	}
+	if !share.VerifyingShare.Eq(f.mulBase(sk)) {
+		return nil, nil, fmt.Errorf("bad signing share")
+	}
+	return share, pub, nil
+}
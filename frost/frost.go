@@ -0,0 +1,357 @@
+// Package frost implements FROST threshold RedJubjub spend authorization signatures.
+//
+// RFC 9591 The Flexible Round-Optimized Schnorr Threshold (FROST) Protocol for Two-Round Schnorr Signatures
+// FROST(Jubjub, BLAKE2b-512) with G = G^Sapling where aggregated signatures are
+// RedJubjub spend authorization signatures of the group key, H2 is the RedJubjub hash H*
+//
+// H1(m) = H*(contextString || "rho" || m)
+// H2(m) = H*(m)
+// H3(m) = H*(contextString || "nonce" || m)
+// H4(m) = BLAKE2b-512("Zcash_RedJubjubH", contextString || "msg" || m)
+// H5(m) = BLAKE2b-512("Zcash_RedJubjubH", contextString || "com" || m)
+package frost
+
+import (
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/kilic/go-jubjub"
+	"github.com/kilic/go-jubjub/sapling"
+	"github.com/kilic/go-jubjub/vss"
+)
+
+const contextString = "FROST-RedJubjub-BLAKE2b-512-v1"
+
+type FROST struct {
+	sapling     *sapling.Sapling
+	curve       *jubjub.Curve
+	scalarField *jubjub.ScalarField
+	// polynomials, Lagrange coefficients and commitment evaluation
+	vss *vss.VSS
+	// G^Sapling
+	g *jubjub.ExtendedPoint
+}
+
+// Signing key share of a participant, identifiers are non zero
+type KeyShare struct {
+	Identifier     uint16
+	MinSigners     int
+	SigningShare   *jubjub.ScalarFieldElement
+	VerifyingShare *jubjub.ExtendedPoint
+	GroupKey       *jubjub.ExtendedPoint
+}
+
+// Public keys of a key generation,
+// Commitment is the VSS commitment to the coefficients of the shared polynomial
+// and its first element is the group key
+type PublicKeys struct {
+	Commitment      []*jubjub.ExtendedPoint
+	VerifyingShares map[uint16]*jubjub.ExtendedPoint
+}
+
+func (p *PublicKeys) GroupKey() *jubjub.ExtendedPoint {
+	return p.Commitment[0]
+}
+
+// Secret nonces of a participant for a single signing session
+type Nonces struct {
+	hiding  *jubjub.ScalarFieldElement
+	binding *jubjub.ScalarFieldElement
+}
+
+type Commitment struct {
+	Identifier uint16
+	Hiding     *jubjub.ExtendedPoint
+	Binding    *jubjub.ExtendedPoint
+}
+
+type SignatureShare struct {
+	Identifier uint16
+	Z          *jubjub.ScalarFieldElement
+}
+
+func New() *FROST {
+	s := sapling.NewSapling()
+	return &FROST{
+		sapling:     s,
+		curve:       s.Curve(),
+		scalarField: jubjub.NewJubjubScalarField(),
+		vss:         vss.New(s.Curve()),
+		g:           s.SpendingKeyGenerator(),
+	}
+}
+
+func (f *FROST) hash(tag string, m ...[]byte) []byte {
+	return sapling.RedJubjubDigest(append([][]byte{[]byte(contextString), []byte(tag)}, m...)...)
+}
+
+func (f *FROST) hashToScalar(tag string, m ...[]byte) *jubjub.ScalarFieldElement {
+	return f.scalarField.FromBytesWide(f.hash(tag, m...))
+}
+
+// H*(m)
+func (f *FROST) redJubjubHash(m ...[]byte) *jubjub.ScalarFieldElement {
+	return f.scalarField.FromBytesWide(sapling.RedJubjubDigest(m...))
+}
+
+func (f *FROST) encodePoint(p *jubjub.ExtendedPoint) []byte {
+	return f.curve.Compress(p.ToAffine())
+}
+
+func (f *FROST) encodeIdentifier(id uint16) []byte {
+	return f.scalarField.ToBytes(f.scalarField.NewElementFromUint64(uint64(id)))
+}
+
+func (f *FROST) mulBase(x *jubjub.ScalarFieldElement) *jubjub.ExtendedPoint {
+	p := f.curve.NewExtendedPoint()
+	f.curve.Mul(p, f.g, x)
+	return p
+}
+
+func checkParams(minSigners, maxSigners int) error {
+	if minSigners < 2 || minSigners > maxSigners || maxSigners > 1<<16-1 {
+		return fmt.Errorf("bad number of signers")
+	}
+	return nil
+}
+
+// Appendix C. Trusted Dealer Key Generation
+// Splits secret into maxSigners shares any minSigners of which can sign,
+// a random secret is used if secret is nil
+func (f *FROST) TrustedDealerKeygen(rand io.Reader, secret *jubjub.ScalarFieldElement, minSigners, maxSigners int) ([]*KeyShare, *PublicKeys, error) {
+	if err := checkParams(minSigners, maxSigners); err != nil {
+		return nil, nil, err
+	}
+	if secret == nil {
+		var err error
+		if secret, err = f.scalarField.RandElement(rand); err != nil {
+			return nil, nil, err
+		}
+	}
+	coefficients, err := f.vss.RandPolynomial(rand, secret, minSigners-1)
+	if err != nil {
+		return nil, nil, err
+	}
+	pub := &PublicKeys{VerifyingShares: make(map[uint16]*jubjub.ExtendedPoint)}
+	for _, a := range coefficients {
+		pub.Commitment = append(pub.Commitment, f.mulBase(a))
+	}
+	shares := make([]*KeyShare, maxSigners)
+	for i := range shares {
+		id := uint16(i + 1)
+		sk := f.vss.Eval(coefficients, uint32(id))
+		shares[i] = &KeyShare{
+			Identifier:     id,
+			MinSigners:     minSigners,
+			SigningShare:   sk,
+			VerifyingShare: f.mulBase(sk),
+			GroupKey:       pub.GroupKey(),
+		}
+		pub.VerifyingShares[id] = shares[i].VerifyingShare
+	}
+	return shares, pub, nil
+}
+
+// Appendix C.2 Verifiable Secret Sharing
+// Reports whether [sk_i]G = sum_j [i^j] C_j
+func (f *FROST) VerifyKeyShare(share *KeyShare, pub *PublicKeys) bool {
+	if share.Identifier == 0 || share.SigningShare == nil || len(pub.Commitment) == 0 {
+		return false
+	}
+	return f.mulBase(share.SigningShare).Eq(f.vss.EvalCommitment(pub.Commitment, uint32(share.Identifier)))
+}
+
+// 5.1 Round One - Commitment
+// nonce = H3(random_bytes || SerializeScalar(sk_i))
+func (f *FROST) Commit(rand io.Reader, share *KeyShare) (*Nonces, *Commitment, error) {
+	nonce := func() (*jubjub.ScalarFieldElement, error) {
+		buf := make([]byte, 32)
+		if _, err := io.ReadFull(rand, buf); err != nil {
+			return nil, err
+		}
+		return f.hashToScalar("nonce", buf, f.scalarField.ToBytes(share.SigningShare)), nil
+	}
+	hiding, err := nonce()
+	if err != nil {
+		return nil, nil, err
+	}
+	binding, err := nonce()
+	if err != nil {
+		return nil, nil, err
+	}
+	return &Nonces{hiding: hiding, binding: binding}, &Commitment{
+		Identifier: share.Identifier,
+		Hiding:     f.mulBase(hiding),
+		Binding:    f.mulBase(binding),
+	}, nil
+}
+
+// Signing session values derived from the commitment list
+type session struct {
+	commitments    []*Commitment
+	identifiers    []uint32
+	bindingFactors map[uint16]*jubjub.ScalarFieldElement
+	groupCommit    *jubjub.ExtendedPoint
+	challenge      *jubjub.ScalarFieldElement
+}
+
+// 4.4 Binding Factors Computation, 4.5 Group Commitment Computation, 4.6 Signature Challenge Computation
+func (f *FROST) session(groupKey *jubjub.ExtendedPoint, msg []byte, commitments []*Commitment) (*session, error) {
+	sorted := append([]*Commitment{}, commitments...)
+	for _, c := range sorted {
+		if c == nil || c.Hiding == nil || c.Binding == nil {
+			return nil, fmt.Errorf("malformed commitment")
+		}
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Identifier < sorted[j].Identifier
+	})
+	s := &session{
+		commitments:    sorted,
+		bindingFactors: make(map[uint16]*jubjub.ScalarFieldElement),
+	}
+	var encoded []byte
+	for i, c := range sorted {
+		if c.Identifier == 0 || (i > 0 && sorted[i-1].Identifier == c.Identifier) {
+			return nil, fmt.Errorf("bad commitment identifiers")
+		}
+		if c.Hiding.Eq(f.curve.NewExtendedPoint()) || c.Binding.Eq(f.curve.NewExtendedPoint()) {
+			return nil, fmt.Errorf("commitment is the identity")
+		}
+		s.identifiers = append(s.identifiers, uint32(c.Identifier))
+		encoded = append(encoded, f.encodeIdentifier(c.Identifier)...)
+		encoded = append(encoded, f.encodePoint(c.Hiding)...)
+		encoded = append(encoded, f.encodePoint(c.Binding)...)
+	}
+	groupKeyBar := f.encodePoint(groupKey)
+	prefix := append(append(append([]byte{}, groupKeyBar...), f.hash("msg", msg)...), f.hash("com", encoded)...)
+	points := make([]*jubjub.ExtendedPoint, 0, 2*len(sorted))
+	scalars := make([]*jubjub.ScalarFieldElement, 0, 2*len(sorted))
+	one := f.scalarField.NewElementFromUint64(1)
+	for _, c := range sorted {
+		rho := f.hashToScalar("rho", prefix, f.encodeIdentifier(c.Identifier))
+		s.bindingFactors[c.Identifier] = rho
+		points = append(points, c.Hiding, c.Binding)
+		scalars = append(scalars, one, rho)
+	}
+	// R = sum_i D_i + [rho_i] E_i
+	s.groupCommit = f.curve.NewExtendedPoint()
+	if err := f.curve.MultiExp(s.groupCommit, points, scalars); err != nil {
+		return nil, err
+	}
+	s.challenge = f.redJubjubHash(f.encodePoint(s.groupCommit), groupKeyBar, msg)
+	return s, nil
+}
+
+// 4.2 Polynomials
+// lambda_i = prod_{j != i} x_j / (x_j - x_i) over identifiers of the signing participants
+func (f *FROST) lagrange(s *session, id uint16) (*jubjub.ScalarFieldElement, error) {
+	return f.vss.LagrangeCoefficient(s.identifiers, uint32(id), 0)
+}
+
+// 5.2 Round Two - Signature Share Generation
+// z_i = d_i + e_i rho_i + lambda_i sk_i c
+// nonces are erased and can not be used again
+func (f *FROST) Sign(share *KeyShare, nonces *Nonces, msg []byte, commitments []*Commitment) (*SignatureShare, error) {
+	if nonces.hiding == nil {
+		return nil, fmt.Errorf("nonces are already used")
+	}
+	if len(commitments) < share.MinSigners {
+		return nil, fmt.Errorf("not enough signers")
+	}
+	s, err := f.session(share.GroupKey, msg, commitments)
+	if err != nil {
+		return nil, err
+	}
+	var own *Commitment
+	for _, c := range s.commitments {
+		if c.Identifier == share.Identifier {
+			own = c
+		}
+	}
+	if own == nil || !own.Hiding.Eq(f.mulBase(nonces.hiding)) || !own.Binding.Eq(f.mulBase(nonces.binding)) {
+		return nil, fmt.Errorf("commitment of signer is missing or mismatches")
+	}
+	z, err := f.lagrange(s, share.Identifier)
+	if err != nil {
+		return nil, err
+	}
+	f.scalarField.Mul(z, z, share.SigningShare)
+	f.scalarField.Mul(z, z, s.challenge)
+	e := f.scalarField.NewElement()
+	f.scalarField.Mul(e, nonces.binding, s.bindingFactors[share.Identifier])
+	f.scalarField.Add(z, z, e)
+	f.scalarField.Add(z, z, nonces.hiding)
+	nonces.hiding, nonces.binding = nil, nil
+	return &SignatureShare{Identifier: share.Identifier, Z: z}, nil
+}
+
+// 5.4 Signature Share Verification and Aggregation
+// Reports whether [z_i]G = D_i + [rho_i] E_i + [c lambda_i] PK_i
+func (f *FROST) VerifySignatureShare(pub *PublicKeys, sigShare *SignatureShare, msg []byte, commitments []*Commitment) bool {
+	s, err := f.session(pub.GroupKey(), msg, commitments)
+	if err != nil {
+		return false
+	}
+	return f.verifySignatureShare(pub, sigShare, s)
+}
+
+func (f *FROST) verifySignatureShare(pub *PublicKeys, sigShare *SignatureShare, s *session) bool {
+	if sigShare == nil || sigShare.Z == nil {
+		return false
+	}
+	pk, ok := pub.VerifyingShares[sigShare.Identifier]
+	rho, signer := s.bindingFactors[sigShare.Identifier]
+	if !ok || !signer || pk == nil {
+		return false
+	}
+	var c *Commitment
+	for _, ci := range s.commitments {
+		if ci.Identifier == sigShare.Identifier {
+			c = ci
+		}
+	}
+	cl, err := f.lagrange(s, sigShare.Identifier)
+	if err != nil {
+		return false
+	}
+	f.scalarField.Mul(cl, cl, s.challenge)
+	r := f.curve.NewExtendedPoint()
+	f.curve.MultiExp(r, []*jubjub.ExtendedPoint{c.Hiding, c.Binding, pk}, []*jubjub.ScalarFieldElement{f.scalarField.NewElementFromUint64(1), rho, cl})
+	return f.mulBase(sigShare.Z).Eq(r)
+}
+
+// Aggregates signature shares into the RedJubjub signature R || z of msg under the group key.
+// If the signature doesn't verify shares are checked to identify a misbehaving participant.
+func (f *FROST) Aggregate(pub *PublicKeys, msg []byte, commitments []*Commitment, sigShares []*SignatureShare) ([]byte, error) {
+	s, err := f.session(pub.GroupKey(), msg, commitments)
+	if err != nil {
+		return nil, err
+	}
+	if len(sigShares) != len(s.commitments) {
+		return nil, fmt.Errorf("number of signature shares mismatches commitments")
+	}
+	z := f.scalarField.NewElement()
+	seen := make(map[uint16]bool)
+	for _, share := range sigShares {
+		if share == nil || share.Z == nil {
+			return nil, fmt.Errorf("malformed signature share")
+		}
+		if _, ok := s.bindingFactors[share.Identifier]; !ok || seen[share.Identifier] {
+			return nil, fmt.Errorf("bad signature share identifiers")
+		}
+		seen[share.Identifier] = true
+		f.scalarField.Add(z, z, share.Z)
+	}
+	sig := append(f.encodePoint(s.groupCommit), f.scalarField.ToBytes(z)...)
+	if f.sapling.SpendAuthVerify(pub.GroupKey(), msg, sig) {
+		return sig, nil
+	}
+	for _, share := range sigShares {
+		if !f.verifySignatureShare(pub, share, s) {
+			return nil, fmt.Errorf("invalid signature share of participant %d", share.Identifier)
+		}
+	}
+	return nil, fmt.Errorf("invalid signature")
+}
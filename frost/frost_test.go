@@ -0,0 +1,238 @@
+package frost
+
+import (
+	"crypto/rand"
+	"testing"
+
+	"github.com/kilic/go-jubjub"
+	"github.com/kilic/go-jubjub/vss"
+)
+
+// Runs both signing rounds with the given key shares
+func sign(t *testing.T, f *FROST, pub *PublicKeys, shares []*KeyShare, msg []byte) ([]byte, error) {
+	nonces := make([]*Nonces, len(shares))
+	commitments := make([]*Commitment, len(shares))
+	for i, share := range shares {
+		var err error
+		if nonces[i], commitments[i], err = f.Commit(rand.Reader, share); err != nil {
+			t.Fatal(err)
+		}
+	}
+	sigShares := make([]*SignatureShare, len(shares))
+	for i, share := range shares {
+		var err error
+		if sigShares[i], err = f.Sign(share, nonces[i], msg, commitments); err != nil {
+			t.Fatal(err)
+		}
+		if !f.VerifySignatureShare(pub, sigShares[i], msg, commitments) {
+			t.Errorf("bad signature share %d", share.Identifier)
+		}
+	}
+	return f.Aggregate(pub, msg, commitments, sigShares)
+}
+
+func TestTrustedDealer(t *testing.T) {
+	f := New()
+	secret, _ := f.scalarField.RandElement(rand.Reader)
+	shares, pub, err := f.TrustedDealerKeygen(rand.Reader, secret, 3, 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !pub.GroupKey().Eq(f.mulBase(secret)) {
+		t.Fatalf("bad group key")
+	}
+	for _, share := range shares {
+		if !f.VerifyKeyShare(share, pub) {
+			t.Errorf("bad key share %d", share.Identifier)
+		}
+	}
+	bad := *shares[0]
+	bad.SigningShare = f.scalarField.NewElementFromUint64(1)
+	if f.VerifyKeyShare(&bad, pub) {
+		t.Errorf("expected key share verification to fail")
+	}
+	// any 3 shares interpolate to the secret
+	var signers []*vss.Share
+	for _, i := range []int{0, 2, 4} {
+		signers = append(signers, &vss.Share{Index: uint32(shares[i].Identifier), Value: shares[i].SigningShare})
+	}
+	recovered, err := f.vss.Combine(signers)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if recovered.Big().Cmp(secret.Big()) != 0 {
+		t.Errorf("bad interpolated secret")
+	}
+
+	msg := []byte("threshold")
+	for _, set := range [][]int{{0, 2, 4}, {1, 2, 3}, {0, 1, 2, 3, 4}} {
+		var signing []*KeyShare
+		for _, i := range set {
+			signing = append(signing, shares[i])
+		}
+		sig, err := sign(t, f, pub, signing, msg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !f.sapling.SpendAuthVerify(pub.GroupKey(), msg, sig) {
+			t.Errorf("bad aggregated signature of signers %v", set)
+		}
+	}
+	if _, _, err := f.TrustedDealerKeygen(rand.Reader, nil, 4, 3); err == nil {
+		t.Errorf("expected error for bad number of signers")
+	}
+}
+
+func TestMisbehaviour(t *testing.T) {
+	f := New()
+	shares, pub, err := f.TrustedDealerKeygen(rand.Reader, nil, 2, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	msg := []byte("threshold")
+	n1, c1, _ := f.Commit(rand.Reader, shares[0])
+	n2, c2, _ := f.Commit(rand.Reader, shares[1])
+	commitments := []*Commitment{c1, c2}
+	if _, err := f.Sign(shares[0], n1, msg, commitments[:1]); err == nil {
+		t.Errorf("expected error for not enough signers")
+	}
+	if _, err := f.Sign(shares[2], n1, msg, commitments); err == nil {
+		t.Errorf("expected error for missing commitment of signer")
+	}
+	z1, err := f.Sign(shares[0], n1, msg, commitments)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.Sign(shares[0], n1, msg, commitments); err == nil {
+		t.Errorf("expected error for nonce reuse")
+	}
+	z2, err := f.Sign(shares[1], n2, msg, commitments)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.Aggregate(pub, msg, commitments, []*SignatureShare{z1}); err == nil {
+		t.Errorf("expected error for missing signature share")
+	}
+	bad := &SignatureShare{Identifier: 2, Z: f.scalarField.NewElementFromUint64(1)}
+	f.scalarField.Add(bad.Z, bad.Z, z2.Z)
+	if f.VerifySignatureShare(pub, bad, msg, commitments) {
+		t.Errorf("expected signature share verification to fail")
+	}
+	malformed := &SignatureShare{Identifier: 2}
+	if f.VerifySignatureShare(pub, malformed, msg, commitments) {
+		t.Errorf("expected signature share verification to fail for missing z")
+	}
+	if _, err := f.Aggregate(pub, msg, commitments, []*SignatureShare{z1, malformed}); err == nil {
+		t.Errorf("expected error for missing z")
+	}
+	if f.VerifySignatureShare(pub, z2, msg, []*Commitment{c1, {Identifier: 2}}) {
+		t.Errorf("expected signature share verification to fail for malformed commitment")
+	}
+	if _, err := f.Aggregate(pub, msg, commitments, []*SignatureShare{z1, bad}); err == nil || err.Error() != "invalid signature share of participant 2" {
+		t.Errorf("expected misbehaving participant to be identified, have: %v", err)
+	}
+	sig, err := f.Aggregate(pub, msg, commitments, []*SignatureShare{z1, z2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if f.sapling.SpendAuthVerify(pub.GroupKey(), []byte("another"), sig) {
+		t.Errorf("expected verification to fail for another message")
+	}
+}
+
+func TestDKG(t *testing.T) {
+	f := New()
+	const minSigners, maxSigners = 3, 4
+	secrets := make([]*DKGSecret, maxSigners)
+	packages := make([]*DKGPackage, maxSigners)
+	for i := range secrets {
+		var err error
+		if secrets[i], packages[i], err = f.DKGPart1(rand.Reader, uint16(i+1), minSigners, maxSigners); err != nil {
+			t.Fatal(err)
+		}
+	}
+	others := func(i int) []*DKGPackage {
+		var out []*DKGPackage
+		for j, pkg := range packages {
+			if j != i {
+				out = append(out, pkg)
+			}
+		}
+		return out
+	}
+	// received[j][i] is the share sent from i to j
+	received := make([]map[uint16]*jubjub.ScalarFieldElement, maxSigners)
+	for i := range received {
+		received[i] = make(map[uint16]*jubjub.ScalarFieldElement)
+	}
+	for i, secret := range secrets {
+		sent, err := f.DKGPart2(secret, others(i))
+		if err != nil {
+			t.Fatal(err)
+		}
+		for id, share := range sent {
+			received[id-1][uint16(i+1)] = share
+		}
+	}
+	shares := make([]*KeyShare, maxSigners)
+	var pub *PublicKeys
+	for i, secret := range secrets {
+		var p *PublicKeys
+		var err error
+		if shares[i], p, err = f.DKGPart3(secret, others(i), received[i]); err != nil {
+			t.Fatal(err)
+		}
+		if pub == nil {
+			pub = p
+		} else if !pub.GroupKey().Eq(p.GroupKey()) {
+			t.Errorf("bad group key of participant %d", i+1)
+		}
+	}
+	for _, share := range shares {
+		if !f.VerifyKeyShare(share, pub) {
+			t.Errorf("bad key share %d", share.Identifier)
+		}
+	}
+	msg := []byte("dkg")
+	sig, err := sign(t, f, pub, shares[1:], msg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !f.sapling.SpendAuthVerify(pub.GroupKey(), msg, sig) {
+		t.Errorf("bad aggregated signature")
+	}
+
+	// misbehaving participants
+	received[0][2] = f.scalarField.NewElementFromUint64(1)
+	if _, _, err := f.DKGPart3(secrets[0], others(0), received[0]); err == nil {
+		t.Errorf("expected error for bad share")
+	}
+	packages[1].ProofMu = f.scalarField.NewElementFromUint64(1)
+	if _, err := f.DKGPart2(secrets[0], others(0)); err == nil {
+		t.Errorf("expected error for bad proof of knowledge")
+	}
+	if _, err := f.DKGPart2(secrets[0], others(0)[1:]); err == nil {
+		t.Errorf("expected error for missing package")
+	}
+}
+
+func BenchmarkAggregate(t *testing.B) {
+	f := New()
+	shares, pub, _ := f.TrustedDealerKeygen(rand.Reader, nil, 3, 3)
+	msg := []byte("benchmark")
+	nonces := make([]*Nonces, len(shares))
+	commitments := make([]*Commitment, len(shares))
+	for i, share := range shares {
+		nonces[i], commitments[i], _ = f.Commit(rand.Reader, share)
+	}
+	sigShares := make([]*SignatureShare, len(shares))
+	for i, share := range shares {
+		sigShares[i], _ = f.Sign(share, nonces[i], msg, commitments)
+	}
+	t.ResetTimer()
+	for i := 0; i < t.N; i++ {
+		if _, err := f.Aggregate(pub, msg, commitments, sigShares); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,123 @@
+package jubjub
+
+import "math/big"
+
+// Gallant-Lambert-Vanstone scalar multiplication
+// Faster Point Multiplication on Elliptic Curves with Efficient Endomorphisms
+// Gallant, Lambert, Vanstone
+type glv struct {
+	// psi(P) = lambda.P for P in prime order subgroup
+	psi    func(r, p *ExtendedPoint)
+	lambda *big.Int
+	// short basis of the lattice {(a, b) : a + b.lambda = 0 mod n}
+	a1, b1 *big.Int
+	a2, b2 *big.Int
+	n      *big.Int
+}
+
+// Guide to Elliptic Curve Cryptography
+// Hankerson, Menezes, Vanstone
+// Algorithm 3.74 Balanced length-two representation of a multiplier
+func newGLV(n, lambda *big.Int, psi func(r, p *ExtendedPoint)) *glv {
+	sqrtN := bn().Sqrt(n)
+	// s_i.n + t_i.lambda = r_i
+	r0, r1 := bn().Set(n), bn().Set(lambda)
+	t0, t1 := bn(), bn().Set(big1)
+	q, tmp := bn(), bn()
+	for r1.Cmp(sqrtN) >= 0 {
+		q.Div(r0, r1)
+		tmp.Mul(q, r1)
+		r0, r1 = r1, bn().Sub(r0, tmp)
+		tmp.Mul(q, t1)
+		t0, t1 = t1, bn().Sub(t0, tmp)
+	}
+	// r_l = r0, r_{l+1} = r1
+	a1, b1 := bn().Set(r1), bn().Neg(t1)
+	q.Div(r0, r1)
+	r2 := bn().Sub(r0, bn().Mul(q, r1))
+	t2 := bn().Sub(t0, bn().Mul(q, t1))
+	a2, b2 := bn().Set(r0), bn().Neg(t0)
+	l0 := bn().Add(bn().Mul(r0, r0), bn().Mul(t0, t0))
+	l2 := bn().Add(bn().Mul(r2, r2), bn().Mul(t2, t2))
+	if l2.Cmp(l0) < 0 {
+		a2, b2 = r2, bn().Neg(t2)
+	}
+	// decomposition assumes a1.b2 - a2.b1 = n
+	det := bn().Sub(bn().Mul(a1, b2), bn().Mul(a2, b1))
+	if det.Sign() < 0 {
+		a2.Neg(a2)
+		b2.Neg(b2)
+	}
+	return &glv{
+		psi:    psi,
+		lambda: bn().Set(lambda),
+		a1:     a1,
+		b1:     b1,
+		a2:     a2,
+		b2:     b2,
+		n:      bn().Set(n),
+	}
+}
+
+// Given k, returns k1, k2 such that
+// k = k1 + k2.lambda mod n where |k1|, |k2| ~ sqrt(n)
+func (g *glv) decompose(k *big.Int) (*big.Int, *big.Int) {
+	c1 := g.round(bn().Mul(g.b2, k))
+	c2 := g.round(bn().Neg(bn().Mul(g.b1, k)))
+	k1 := bn().Sub(k, bn().Mul(c1, g.a1))
+	k1.Sub(k1, bn().Mul(c2, g.a2))
+	k2 := bn().Neg(bn().Mul(c1, g.b1))
+	k2.Sub(k2, bn().Mul(c2, g.b2))
+	return k1, k2
+}
+
+// round(x/n)
+func (g *glv) round(x *big.Int) *big.Int {
+	n2 := bn().Lsh(g.n, 1)
+	x = bn().Lsh(x, 1)
+	x.Add(x, g.n)
+	return x.Div(x, n2)
+}
+
+// Sets r as s.p for p in the prime order subgroup using the curve endomorphism,
+// s is reduced modulo the subgroup order.
+// The result is wrong for points outside the subgroup, check untrusted points with IsInSubgroup.
+// For curves without an efficient endomorphism falls back to Mul.
+func (e *Curve) MulSubgroup(r *ExtendedPoint, p *ExtendedPoint, s *ScalarFieldElement) {
+	if e.glv == nil || p.x.IsZero() {
+		e.Mul(r, p, s)
+		return
+	}
+	k := bn().Mod(s.n, e.order)
+	k1, k2 := e.glv.decompose(k)
+	P := new(ExtendedPoint).Set(p)
+	Q := e.NewExtendedPoint()
+	e.glv.psi(Q, p)
+	if k1.Sign() < 0 {
+		k1.Neg(k1)
+		P.Neg()
+	}
+	if k2.Sign() < 0 {
+		k2.Neg(k2)
+		Q.Neg()
+	}
+	PQ := e.NewExtendedPoint()
+	e.Add(PQ, P, Q)
+	R := e.NewExtendedPoint()
+	l := k1.BitLen()
+	if k2.BitLen() > l {
+		l = k2.BitLen()
+	}
+	for i := l - 1; i >= 0; i-- {
+		e.double(R, R.toProjective())
+		switch k1.Bit(i) | k2.Bit(i)<<1 {
+		case 1:
+			e.Add(R, R, P)
+		case 2:
+			e.Add(R, R, Q)
+		case 3:
+			e.Add(R, R, PQ)
+		}
+	}
+	r.Set(R)
+}
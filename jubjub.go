@@ -2,6 +2,7 @@ package jubjub
 
 import (
 	"encoding/hex"
+	"math/big"
 )
 
 func fe(field *Field, s string) *FieldElement {
@@ -17,20 +18,14 @@ func fe(field *Field, s string) *FieldElement {
 
 func NewJubjub() *Curve {
 	field := NewField(bigFromStr16("0x73eda753299d7d483339d80809a1d80553bda402fffe5bfeffffffff00000001"))
+	a := fe(field, "0x73eda753299d7d483339d80809a1d80553bda402fffe5bfeffffffff00000000")
 	d := fe(field, "0x2a9318e74bfa2b48f5fd9207e6bd7fd4292d7f6d37579d2601065fd6d6343eb1")
-	d2 := fe(field, "0x552631ce97f45691ebfb240fcd7affa8525afeda6eaf3a4c020cbfadac687d62")
-	// TODO fix generator
-	generator := &ExtendedPoint{
-		x: &FieldElement{0, 0, 0, 0},
-		y: &FieldElement{1, 0, 0, 0},
-		t: &FieldElement{0, 0, 0, 0},
-		z: &FieldElement{1, 0, 0, 0}}
-	return &Curve{
-		d:         d,
-		twoD:      d2,
-		field:     field,
-		generator: generator,
-	}
+	generator := new(AffinePoint).NewPoint(field).SetCoordinates(
+		fe(field, "0x11dafe5d23e1218086a365b99fbf3d3be72f6afd7d1f72623e6b071492d1122b"),
+		fe(field, "0x1d523cf1ddab1a1793132e78c866c0c33e26ba5cc220fed7cc3f870e59d292aa"))
+	order := bigFromStr16("0x0e7db4ea6533afa906673b0101343b00a6682093ccc81082d0970e5ed6f72cb7")
+	curve := mustNewTwistedEdwardsCurve(field, a, d, generator, order, big.NewInt(8))
+	return curve
 }
 
 func NewJubjubScalarField() *ScalarField {
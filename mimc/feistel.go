@@ -0,0 +1,89 @@
+package mimc
+
+import (
+	"fmt"
+
+	"github.com/kilic/go-jubjub"
+)
+
+// MiMC x^5 Feistel permutation and the sponge built on it,
+// known as MiMCSponge in circomlib
+type Feistel struct {
+	field *jubjub.Field
+	c     []*jubjub.FieldElement
+}
+
+// Returns circomlib instance with 220 rounds
+func NewFeistelCircom(field *jubjub.Field) *Feistel {
+	c := Constants(field, "mimcsponge", 220)
+	// circomlib zeroes the last round constant
+	c[len(c)-1] = new(jubjub.FieldElement)
+	m, _ := NewFeistel(field, c)
+	return m
+}
+
+func NewFeistel(field *jubjub.Field, constants []*jubjub.FieldElement) (*Feistel, error) {
+	if len(constants) == 0 {
+		return nil, fmt.Errorf("at least one round is required")
+	}
+	c := make([]*jubjub.FieldElement, len(constants))
+	for i := range constants {
+		c[i] = new(jubjub.FieldElement).Set(constants[i])
+	}
+	return &Feistel{
+		field: field,
+		c:     c,
+	}, nil
+}
+
+func (m *Feistel) Field() *jubjub.Field {
+	return m.field
+}
+
+// Applies the permutation to (xL, xR) in place with key k
+// t = xL + k + c_i
+// (xL, xR) = (xR + t^5, xL)
+// last round doesn't swap
+func (m *Feistel) Permute(xL, xR, k *jubjub.FieldElement) {
+	f := m.field
+	t, t2 := new(jubjub.FieldElement), new(jubjub.FieldElement)
+	n := len(m.c)
+	for i := 0; i < n; i++ {
+		f.Add(t, xL, k)
+		f.Add(t, t, m.c[i])
+		// t = t^5
+		f.Square(t2, t)
+		f.Square(t2, t2)
+		f.Mul(t, t, t2)
+		if i < n-1 {
+			f.Add(t, t, xR)
+			xR.Set(xL)
+			xL.Set(t)
+		} else {
+			f.Add(xR, xR, t)
+		}
+	}
+}
+
+// Sponge with rate 1 and capacity 1
+// inputs are added to the rate element, each squeezed output costs one more permutation.
+// nil key is zero
+func (m *Feistel) Hash(inputs []*jubjub.FieldElement, key *jubjub.FieldElement, outputs int) []*jubjub.FieldElement {
+	k := new(jubjub.FieldElement)
+	if key != nil {
+		k.Set(key)
+	}
+	r, c := new(jubjub.FieldElement), new(jubjub.FieldElement)
+	for _, x := range inputs {
+		m.field.Add(r, r, x)
+		m.Permute(r, c, k)
+	}
+	out := make([]*jubjub.FieldElement, 0, outputs)
+	for i := 0; i < outputs; i++ {
+		if i > 0 {
+			m.Permute(r, c, k)
+		}
+		out = append(out, new(jubjub.FieldElement).Set(r))
+	}
+	return out
+}
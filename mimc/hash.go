@@ -0,0 +1,83 @@
+package mimc
+
+import (
+	"fmt"
+	"hash"
+	"math/big"
+
+	"github.com/kilic/go-jubjub"
+)
+
+const blockSize = 32
+
+// Digest is a hash.Hash over MiMC-7 Miyaguchi-Preneel multi hash.
+// Written bytes are split into 32 bytes big endian blocks
+// which are required to be canonical field elements.
+// Sum absorbs a final block 0x01 || trailing bytes read as a big endian integer,
+// so inputs of different lengths don't collide,
+// and returns the big endian encoding of Hash of the blocks with zero key.
+type Digest struct {
+	m   *MiMC7
+	p   *big.Int
+	r   *jubjub.FieldElement
+	buf []byte
+}
+
+var _ hash.Hash = (*Digest)(nil)
+
+func (m *MiMC7) NewDigest() *Digest {
+	return &Digest{
+		m: m,
+		p: m.field.Modulus(),
+		r: new(jubjub.FieldElement),
+	}
+}
+
+// Writes the 32 bytes encoding of x
+func (d *Digest) WriteElement(x *jubjub.FieldElement) error {
+	_, err := d.Write(d.m.field.ToBytes(x))
+	return err
+}
+
+// Absorbs complete blocks, a non canonical block is discarded with the rest of p
+func (d *Digest) Write(p []byte) (int, error) {
+	d.buf = append(d.buf, p...)
+	e := new(jubjub.FieldElement)
+	for len(d.buf) >= blockSize {
+		if new(big.Int).SetBytes(d.buf[:blockSize]).Cmp(d.p) >= 0 {
+			n := len(p) - len(d.buf)
+			if n < 0 {
+				n = 0
+			}
+			d.buf = nil
+			return n, fmt.Errorf("non canonical block")
+		}
+		x := d.m.field.NewElement(d.buf[:blockSize])
+		d.m.compress(d.r, x, e)
+		d.buf = d.buf[blockSize:]
+	}
+	return len(p), nil
+}
+
+// Appends the digest to b without changing the underlying state
+func (d *Digest) Sum(b []byte) []byte {
+	r := new(jubjub.FieldElement).Set(d.r)
+	last := make([]byte, blockSize)
+	last[blockSize-len(d.buf)-1] = 1
+	copy(last[blockSize-len(d.buf):], d.buf)
+	d.m.compress(r, d.m.field.NewElement(last), new(jubjub.FieldElement))
+	return append(b, d.m.field.ToBytes(r)...)
+}
+
+func (d *Digest) Reset() {
+	d.r = new(jubjub.FieldElement)
+	d.buf = nil
+}
+
+func (d *Digest) Size() int {
+	return blockSize
+}
+
+func (d *Digest) BlockSize() int {
+	return blockSize
+}
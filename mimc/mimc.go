@@ -0,0 +1,107 @@
+// Package mimc implements MiMC-7 and the MiMC x^5 Feistel sponge over jubjub.Field
+// as instantiated by circomlib.
+//
+// MiMC: Efficient Encryption and Cryptographic Hashing with Minimal Multiplicative Complexity
+// Albrecht, Grassi, Rechberger, Roy, Tiessen
+package mimc
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/kilic/go-jubjub"
+	"golang.org/x/crypto/sha3"
+)
+
+// Returns round constants derived as in circomlib
+// c_0 = 0 and c_i = keccak256^{i+1}(seed) mod p
+func Constants(field *jubjub.Field, seed string, rounds int) []*jubjub.FieldElement {
+	p := field.Modulus()
+	c := make([]*jubjub.FieldElement, rounds)
+	if rounds == 0 {
+		return c
+	}
+	c[0] = new(jubjub.FieldElement)
+	h := keccak256([]byte(seed))
+	for i := 1; i < rounds; i++ {
+		h = keccak256(h)
+		c[i] = field.NewElement(new(big.Int).Mod(new(big.Int).SetBytes(h), p).Bytes())
+	}
+	return c
+}
+
+func keccak256(in []byte) []byte {
+	h := sha3.NewLegacyKeccak256()
+	h.Write(in)
+	return h.Sum(nil)
+}
+
+// MiMC-7 block cipher with Miyaguchi-Preneel compression
+type MiMC7 struct {
+	field *jubjub.Field
+	c     []*jubjub.FieldElement
+}
+
+// Returns circomlib instance with 91 rounds
+func NewMiMC7Circom(field *jubjub.Field) *MiMC7 {
+	m, _ := NewMiMC7(field, Constants(field, "mimc", 91))
+	return m
+}
+
+func NewMiMC7(field *jubjub.Field, constants []*jubjub.FieldElement) (*MiMC7, error) {
+	if len(constants) == 0 {
+		return nil, fmt.Errorf("at least one round is required")
+	}
+	c := make([]*jubjub.FieldElement, len(constants))
+	for i := range constants {
+		c[i] = new(jubjub.FieldElement).Set(constants[i])
+	}
+	return &MiMC7{
+		field: field,
+		c:     c,
+	}, nil
+}
+
+func (m *MiMC7) Field() *jubjub.Field {
+	return m.field
+}
+
+// Sets c as E_k(x)
+// t_i = r_{i-1} + k + c_i, r_i = t_i^7, r_{-1} = x
+// E_k(x) = r_{n-1} + k
+func (m *MiMC7) Encrypt(c, x, k *jubjub.FieldElement) {
+	f := m.field
+	t, t2 := new(jubjub.FieldElement), new(jubjub.FieldElement)
+	r := new(jubjub.FieldElement).Set(x)
+	for i := range m.c {
+		f.Add(t, r, k)
+		f.Add(t, t, m.c[i])
+		// r = t^7
+		f.Square(t2, t)
+		f.Mul(r, t2, t)
+		f.Square(t2, t2)
+		f.Mul(r, r, t2)
+	}
+	f.Add(c, r, k)
+}
+
+// Miyaguchi-Preneel multi hash
+// r_{-1} = key, r_i = r_{i-1} + x_i + E_{r_{i-1}}(x_i)
+// nil key is zero
+func (m *MiMC7) Hash(inputs []*jubjub.FieldElement, key *jubjub.FieldElement) *jubjub.FieldElement {
+	r := new(jubjub.FieldElement)
+	if key != nil {
+		r.Set(key)
+	}
+	e := new(jubjub.FieldElement)
+	for _, x := range inputs {
+		m.compress(r, x, e)
+	}
+	return r
+}
+
+func (m *MiMC7) compress(r, x, e *jubjub.FieldElement) {
+	m.Encrypt(e, x, r)
+	m.field.Add(r, r, x)
+	m.field.Add(r, r, e)
+}
@@ -0,0 +1,251 @@
+package mimc
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+
+	"github.com/kilic/go-jubjub"
+)
+
+// test vectors are from circomlib
+
+func fromStr10(field *jubjub.Field, s string) *jubjub.FieldElement {
+	n, _ := new(big.Int).SetString(s, 10)
+	return field.NewElement(n.Bytes())
+}
+
+func toStr16(field *jubjub.Field, a *jubjub.FieldElement) string {
+	return new(big.Int).SetBytes(field.ToBytes(a)).Text(16)
+}
+
+func elements(field *jubjub.Field, in ...string) []*jubjub.FieldElement {
+	out := make([]*jubjub.FieldElement, len(in))
+	for i := range in {
+		out[i] = fromStr10(field, in[i])
+	}
+	return out
+}
+
+func TestMiMC7Constants(t *testing.T) {
+	field := jubjub.NewBabyJubjub().Field()
+	c := Constants(field, "mimc", 91)
+	if !c[0].IsZero() {
+		t.Errorf("first constant is expected to be zero")
+	}
+	if !c[1].Eq(fromStr10(field, "20888961410941983456478427210666206549300505294776164667214940546594746570981")) {
+		t.Errorf("bad constant")
+	}
+	if !c[2].Eq(fromStr10(field, "15265126113435022738560151911929040668591755459209400716467504685752745317193")) {
+		t.Errorf("bad constant")
+	}
+}
+
+func TestMiMC7(t *testing.T) {
+	field := jubjub.NewBabyJubjub().Field()
+	m := NewMiMC7Circom(field)
+	e := new(jubjub.FieldElement)
+	m.Encrypt(e, fromStr10(field, "12"), fromStr10(field, "45"))
+	if out := toStr16(field, e); out != "2ba7ebad3c6b6f5a20bdecba2333c63173ca1a5f2f49d958081d9fa7179c44e4" {
+		t.Errorf("bad encryption, have: %s", out)
+	}
+	vectors := []struct {
+		in  []string
+		out string
+	}{
+		{[]string{"12"}, "237c92644dbddb86d8a259e0e923aaab65a93f1ec5758b8799988894ac0958fd"},
+		{[]string{"78", "41"}, "67f3202335ea256ae6e6aadcd2d5f7f4b06a00b2d1e0de903980d5ab552dc70"},
+		{[]string{"12", "45"}, "15ff7fe9793346a17c3150804bcb36d161c8662b110c50f55ccb7113948d8879"},
+		{[]string{"12", "45", "78", "41"}, "284bc1f34f335933a23a433b6ff3ee179d682cd5e5e2fcdd2d964afa85104beb"},
+	}
+	for i, v := range vectors {
+		if out := toStr16(field, m.Hash(elements(field, v.in...), nil)); out != v.out {
+			t.Errorf("bad hash %d, have: %s, want: %s", i, out, v.out)
+		}
+	}
+}
+
+func TestMiMC7Rounds(t *testing.T) {
+	field := jubjub.NewJubjub().Field()
+	if _, err := NewMiMC7(field, nil); err == nil {
+		t.Errorf("expected error for zero rounds")
+	}
+	m0, _ := NewMiMC7(field, Constants(field, "seed", 91))
+	m1, _ := NewMiMC7(field, Constants(field, "seed", 92))
+	in := elements(field, "1", "2")
+	if m0.Hash(in, nil).Eq(m1.Hash(in, nil)) {
+		t.Errorf("different round counts are not expected to give the same output")
+	}
+	key := fromStr10(field, "3")
+	if m0.Hash(in, nil).Eq(m0.Hash(in, key)) {
+		t.Errorf("different keys are not expected to give the same output")
+	}
+}
+
+func TestFeistelConstants(t *testing.T) {
+	field := jubjub.NewBabyJubjub().Field()
+	m := NewFeistelCircom(field)
+	if len(m.c) != 220 || !m.c[0].IsZero() || !m.c[219].IsZero() {
+		t.Errorf("first and last constants are expected to be zero")
+	}
+	if !m.c[1].Eq(fromStr10(field, "7120861356467848435263064379192047478074060781135320967663101236819528304084")) {
+		t.Errorf("bad constant")
+	}
+	if !m.c[2].Eq(fromStr10(field, "5024705281721889198577876690145313457398658950011302225525409148828000436681")) {
+		t.Errorf("bad constant")
+	}
+}
+
+// Inverts the permutation
+func (m *Feistel) invert(xL, xR, k *jubjub.FieldElement) {
+	f := m.field
+	t, t2 := new(jubjub.FieldElement), new(jubjub.FieldElement)
+	l, r := new(jubjub.FieldElement).Set(xL), new(jubjub.FieldElement).Set(xR)
+	for i := len(m.c) - 1; i >= 0; i-- {
+		if i < len(m.c)-1 {
+			l, r = r, l
+		}
+		f.Add(t, l, k)
+		f.Add(t, t, m.c[i])
+		f.Square(t2, t)
+		f.Square(t2, t2)
+		f.Mul(t, t, t2)
+		f.Sub(r, r, t)
+	}
+	xL.Set(l)
+	xR.Set(r)
+}
+
+func TestFeistel(t *testing.T) {
+	field := jubjub.NewBabyJubjub().Field()
+	m := NewFeistelCircom(field)
+	xL, xR, k := fromStr10(field, "1"), fromStr10(field, "2"), fromStr10(field, "3")
+	yL, yR := new(jubjub.FieldElement).Set(xL), new(jubjub.FieldElement).Set(xR)
+	m.Permute(yL, yR, k)
+	if yL.Eq(xL) || yR.Eq(xR) {
+		t.Fatalf("permutation is not expected to have a fixed point here")
+	}
+	zL, zR := new(jubjub.FieldElement).Set(yL), new(jubjub.FieldElement).Set(yR)
+	m.invert(zL, zR, k)
+	if !zL.Eq(xL) || !zR.Eq(xR) {
+		t.Fatalf("bad inversion")
+	}
+	// sponge with a single input is a single permutation
+	out := m.Hash(elements(field, "1"), k, 3)
+	yL, yR = fromStr10(field, "1"), new(jubjub.FieldElement)
+	m.Permute(yL, yR, k)
+	if !out[0].Eq(yL) {
+		t.Errorf("bad sponge output")
+	}
+	m.Permute(yL, yR, k)
+	if !out[1].Eq(yL) {
+		t.Errorf("bad sponge output")
+	}
+	if len(m.Hash(nil, nil, 0)) != 0 {
+		t.Errorf("no output expected")
+	}
+}
+
+func TestDigest(t *testing.T) {
+	field := jubjub.NewBabyJubjub().Field()
+	m := NewMiMC7Circom(field)
+	in := elements(field, "12", "45", "78", "41")
+	buf := []byte{}
+	for i := range in {
+		buf = append(buf, field.ToBytes(in[i])...)
+	}
+	// blocks followed by the final block of no trailing bytes
+	e := field.ToBytes(m.Hash(append(in, elements(field, "1")...), nil))
+	d := m.NewDigest()
+	d.Write(buf[:20])
+	d.Write(buf[20:77])
+	d.Write(buf[77:])
+	if !bytes.Equal(d.Sum(nil), e) {
+		t.Errorf("bad digest")
+	}
+	// sum doesn't change the state
+	if !bytes.Equal(d.Sum(nil), e) {
+		t.Errorf("bad digest")
+	}
+	d.Reset()
+	for i := range in {
+		if err := d.WriteElement(in[i]); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if !bytes.Equal(d.Sum([]byte{0xff})[1:], e) {
+		t.Errorf("bad digest")
+	}
+	// trailing bytes are prefixed with 0x01, 0x010c = 268
+	d.Reset()
+	d.Write([]byte{12})
+	if !bytes.Equal(d.Sum(nil), field.ToBytes(m.Hash(elements(field, "268"), nil))) {
+		t.Errorf("bad digest")
+	}
+}
+
+func TestDigestCollisions(t *testing.T) {
+	field := jubjub.NewBabyJubjub().Field()
+	m := NewMiMC7Circom(field)
+	inputs := [][]byte{
+		{},
+		{0},
+		{1},
+		{0, 1},
+		{1, 0},
+		make([]byte, 31),
+		make([]byte, 32),
+		make([]byte, 33),
+		append([]byte{0x01}, make([]byte, 31)...),
+	}
+	seen := map[string]int{}
+	for i, in := range inputs {
+		d := m.NewDigest()
+		if _, err := d.Write(in); err != nil {
+			t.Fatal(err)
+		}
+		sum := string(d.Sum(nil))
+		if j, ok := seen[sum]; ok {
+			t.Errorf("digests of inputs %d and %d collide", j, i)
+		}
+		seen[sum] = i
+	}
+}
+
+func TestDigestNonCanonical(t *testing.T) {
+	field := jubjub.NewBabyJubjub().Field()
+	m := NewMiMC7Circom(field)
+	d := m.NewDigest()
+	in := append(field.ToBytes(elements(field, "7")[0]), field.Modulus().Bytes()...)
+	n, err := d.Write(in)
+	if err == nil {
+		t.Errorf("expected error for non canonical block")
+	}
+	if n != 32 {
+		t.Errorf("bad number of written bytes, have: %d, want: %d", n, 32)
+	}
+	// p is rejected rather than reduced to zero
+	if _, err := m.NewDigest().Write(field.Modulus().FillBytes(make([]byte, 32))); err == nil {
+		t.Errorf("expected error for modulus")
+	}
+}
+
+func BenchmarkMiMC7(t *testing.B) {
+	field := jubjub.NewBabyJubjub().Field()
+	m := NewMiMC7Circom(field)
+	in := elements(field, "1", "2")
+	t.ResetTimer()
+	for i := 0; i < t.N; i++ {
+		m.Hash(in, nil)
+	}
+}
+
+func BenchmarkFeistel(t *testing.B) {
+	field := jubjub.NewBabyJubjub().Field()
+	m := NewFeistelCircom(field)
+	in := elements(field, "1", "2")
+	t.ResetTimer()
+	for i := 0; i < t.N; i++ {
+		m.Hash(in, nil, 1)
+	}
+}
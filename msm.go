@@ -0,0 +1,53 @@
+package jubjub
+
+import (
+	"fmt"
+	"math/bits"
+)
+
+// Sets r as sum_i [scalars_i] points_i with Pippenger's bucket method.
+// Scalars are not required to be reduced.
+func (e *Curve) MultiExp(r *ExtendedPoint, points []*ExtendedPoint, scalars []*ScalarFieldElement) error {
+	if len(points) != len(scalars) {
+		return fmt.Errorf("points and scalars must have the same length")
+	}
+	l := 0
+	for _, s := range scalars {
+		if s.bitLength() > l {
+			l = s.bitLength()
+		}
+	}
+	c := bits.Len(uint(len(points)))/2 + 2
+	buckets := make([]*ExtendedPoint, 1<<uint(c)-1)
+	for i := range buckets {
+		buckets[i] = e.NewExtendedPoint()
+	}
+	acc, sum, running := e.NewExtendedPoint(), e.NewExtendedPoint(), e.NewExtendedPoint()
+	for w := (l+c-1)/c - 1; w >= 0; w-- {
+		for i := 0; i < c; i++ {
+			e.double(acc, acc.toProjective())
+		}
+		for _, b := range buckets {
+			b.Identity()
+		}
+		for j, s := range scalars {
+			var k uint
+			for i := c - 1; i >= 0; i-- {
+				k = k<<1 | s.bit(w*c+i)
+			}
+			if k != 0 {
+				e.Add(buckets[k-1], buckets[k-1], points[j])
+			}
+		}
+		// sum_k k.B_k as a sum of running sums
+		sum.Identity()
+		running.Identity()
+		for k := len(buckets) - 1; k >= 0; k-- {
+			e.Add(running, running, buckets[k])
+			e.Add(sum, sum, running)
+		}
+		e.Add(acc, acc, sum)
+	}
+	r.Set(acc)
+	return nil
+}
@@ -20,6 +20,23 @@ func (p *AffinePoint) MontgomeryDecodeUncompressed(out []byte) {
 	p.y.Marshal(out[32:64])
 }
 
+// Given coordinates in Montgomery domain
+func (p *AffinePoint) SetCoordinates(x, y *FieldElement) *AffinePoint {
+	p.x.Set(x)
+	p.y.Set(y)
+	return p
+}
+
+// Returns x coordinate in Montgomery domain
+func (p *AffinePoint) X() *FieldElement {
+	return new(FieldElement).Set(p.x)
+}
+
+// Returns y coordinate in Montgomery domain
+func (p *AffinePoint) Y() *FieldElement {
+	return new(FieldElement).Set(p.y)
+}
+
 func (p *AffinePoint) Set(p2 *AffinePoint) *AffinePoint {
 	p.NewPoint(p2.field)
 	p.x.Set(p2.x)
@@ -54,6 +71,19 @@ func (p *AffinePoint) IsOnCurve(d *FieldElement) bool {
 	return A.Eq(&C)
 }
 
+func (p *AffinePoint) isOnCurve(a, d *FieldElement) bool {
+	//  a.x^2 + y^2 == 1 + d.x^2.y^2
+	var A, B, C FieldElement
+	p.field.Square(&B, p.x)
+	p.field.Square(&C, p.y)
+	p.field.Mul(&A, &C, &B)
+	p.field.Mul(&A, &A, d)
+	p.field.Add(&A, &A, p.field.r1)
+	p.field.Mul(&B, &B, a)
+	p.field.Add(&C, &C, &B)
+	return A.Eq(&C)
+}
+
 func (p *AffinePoint) String() string {
 	return fmt.Sprintf("(%s, %s)", p.x.String(), p.y.String())
 }
@@ -142,6 +172,22 @@ func (p *ExtendedPoint) IsOnCurve(d *FieldElement) bool {
 	return A.Eq(&B)
 }
 
+func (p *ExtendedPoint) isOnCurve(a, d *FieldElement) bool {
+	// (a.X^2 + Y^2).Z^2 = Z^4 + d.X^2.Y^2
+	var A, B, C, D FieldElement
+	p.field.Square(&B, p.x)
+	p.field.Square(&C, p.y)
+	p.field.Square(&D, p.z)
+	p.field.Mul(&A, &B, a)
+	p.field.Add(&A, &A, &C)
+	p.field.Mul(&A, &A, &D)
+	p.field.Square(&D, &D)
+	p.field.Mul(&B, &C, &B)
+	p.field.Mul(&B, &B, d)
+	p.field.Add(&B, &B, &D)
+	return A.Eq(&B)
+}
+
 func (p *ExtendedPoint) String() string {
 	return fmt.Sprintf("(%s, %s, %s, %s)", p.x.String(), p.y.String(), p.z.String(), p.t.String())
 }
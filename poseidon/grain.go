@@ -0,0 +1,57 @@
+package poseidon
+
+import "math/big"
+
+// Grain LFSR in self-shrinking mode
+// seeded with the instance parameters as in the reference implementation
+type grain struct {
+	state [80]uint8
+}
+
+func newGrain(n, t, rf, rp int) *grain {
+	g := new(grain)
+	i := 0
+	put := func(v, l int) {
+		for j := l - 1; j >= 0; j-- {
+			g.state[i] = uint8(v>>uint(j)) & 1
+			i++
+		}
+	}
+	// field is prime, sbox is x^alpha
+	put(1, 2)
+	put(0, 4)
+	put(n, 12)
+	put(t, 12)
+	put(rf, 10)
+	put(rp, 10)
+	put(1<<30-1, 30)
+	for j := 0; j < 160; j++ {
+		g.update()
+	}
+	return g
+}
+
+func (g *grain) update() uint8 {
+	s := &g.state
+	b := s[62] ^ s[51] ^ s[38] ^ s[23] ^ s[13] ^ s[0]
+	copy(s[:], s[1:])
+	s[79] = b
+	return b
+}
+
+func (g *grain) bit() uint8 {
+	for g.update() == 0 {
+		g.update()
+	}
+	return g.update()
+}
+
+// Returns n output bits read as a big endian integer
+func (g *grain) bits(n int) *big.Int {
+	v := new(big.Int)
+	for i := 0; i < n; i++ {
+		v.Lsh(v, 1)
+		v.SetBit(v, 0, uint(g.bit()))
+	}
+	return v
+}
@@ -0,0 +1,205 @@
+// Package poseidon implements the Poseidon permutation over jubjub.Field
+// with the x^5 S-box, along with a sponge construction on top of it.
+//
+// POSEIDON: A New Hash Function for Zero-Knowledge Proof Systems
+// Grassi, Khovratovich, Rechberger, Roy, Schofnegger
+package poseidon
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/kilic/go-jubjub"
+)
+
+type Poseidon struct {
+	field *jubjub.Field
+	// t is the width, rf and rp are number of full and partial rounds
+	t  int
+	rf int
+	rp int
+	c  []*jubjub.FieldElement
+	m  [][]*jubjub.FieldElement
+}
+
+// Returns Poseidon instance of width 3 at 128-bit security
+// over Jubjub base field, matching poseidonperm_x5_255_3 of the reference implementation
+func NewWidth3() *Poseidon {
+	p, _ := New(jubjub.NewJubjub().Field(), 3, 8, 57)
+	return p
+}
+
+// Returns Poseidon instance of width 5 at 128-bit security
+// over Jubjub base field, matching poseidonperm_x5_255_5 of the reference implementation
+func NewWidth5() *Poseidon {
+	p, _ := New(jubjub.NewJubjub().Field(), 5, 8, 60)
+	return p
+}
+
+// Round constants and MDS matrix are sampled from Grain LFSR as in the reference implementation.
+// MDS matrix is the first Cauchy matrix candidate, it is up to the caller
+// to make sure it passes the security checks of the reference implementation for given parameters.
+func New(field *jubjub.Field, t, rf, rp int) (*Poseidon, error) {
+	if err := checkParams(t, rf, rp); err != nil {
+		return nil, err
+	}
+	g := newGrain(field.Modulus().BitLen(), t, rf, rp)
+	c := roundConstants(field, g, (rf+rp)*t)
+	m := cauchyMatrix(field, g, t)
+	return newPoseidon(field, t, rf, rp, c, m), nil
+}
+
+// Round constants are sampled from Grain LFSR, MDS matrix is given by the caller
+func NewWithMDS(field *jubjub.Field, t, rf, rp int, mds [][]*jubjub.FieldElement) (*Poseidon, error) {
+	if err := checkParams(t, rf, rp); err != nil {
+		return nil, err
+	}
+	if len(mds) != t {
+		return nil, fmt.Errorf("bad mds matrix size")
+	}
+	for i := range mds {
+		if len(mds[i]) != t {
+			return nil, fmt.Errorf("bad mds matrix size")
+		}
+	}
+	g := newGrain(field.Modulus().BitLen(), t, rf, rp)
+	c := roundConstants(field, g, (rf+rp)*t)
+	m := make([][]*jubjub.FieldElement, t)
+	for i := range m {
+		m[i] = make([]*jubjub.FieldElement, t)
+		for j := range m[i] {
+			m[i][j] = new(jubjub.FieldElement).Set(mds[i][j])
+		}
+	}
+	return newPoseidon(field, t, rf, rp, c, m), nil
+}
+
+func checkParams(t, rf, rp int) error {
+	if t < 2 {
+		return fmt.Errorf("width must be at least 2")
+	}
+	if rf < 2 || rf%2 != 0 {
+		return fmt.Errorf("number of full rounds must be positive and even")
+	}
+	if rp < 0 {
+		return fmt.Errorf("number of partial rounds must be non negative")
+	}
+	return nil
+}
+
+func newPoseidon(field *jubjub.Field, t, rf, rp int, c []*jubjub.FieldElement, m [][]*jubjub.FieldElement) *Poseidon {
+	return &Poseidon{
+		field: field,
+		t:     t,
+		rf:    rf,
+		rp:    rp,
+		c:     c,
+		m:     m,
+	}
+}
+
+func roundConstants(field *jubjub.Field, g *grain, n int) []*jubjub.FieldElement {
+	p := field.Modulus()
+	l := p.BitLen()
+	c := make([]*jubjub.FieldElement, n)
+	for i := range c {
+		v := g.bits(l)
+		for v.Cmp(p) >= 0 {
+			v = g.bits(l)
+		}
+		c[i] = field.NewElement(v.Bytes())
+	}
+	return c
+}
+
+// m_ij = 1 / (x_i + y_j)
+func cauchyMatrix(field *jubjub.Field, g *grain, t int) [][]*jubjub.FieldElement {
+	p := field.Modulus()
+	l := p.BitLen()
+	xy := make([]*big.Int, 2*t)
+	for i := range xy {
+		xy[i] = g.bits(l)
+	}
+	m := make([][]*jubjub.FieldElement, t)
+	for i := 0; i < t; i++ {
+		m[i] = make([]*jubjub.FieldElement, t)
+		for j := 0; j < t; j++ {
+			v := new(big.Int).Add(xy[i], xy[t+j])
+			v.ModInverse(v.Mod(v, p), p)
+			m[i][j] = field.NewElement(v.Bytes())
+		}
+	}
+	return m
+}
+
+func (p *Poseidon) Field() *jubjub.Field {
+	return p.field
+}
+
+// Returns the width of the permutation
+func (p *Poseidon) Width() int {
+	return p.t
+}
+
+// Applies the permutation to the state in place
+func (p *Poseidon) Permute(state []*jubjub.FieldElement) error {
+	if len(state) != p.t {
+		return fmt.Errorf("bad state size")
+	}
+	p.permute(state)
+	return nil
+}
+
+func (p *Poseidon) permute(state []*jubjub.FieldElement) {
+	f := p.field
+	t := p.t
+	next := make([]jubjub.FieldElement, t)
+	tmp := new(jubjub.FieldElement)
+	half := p.rf / 2
+	for r := 0; r < p.rf+p.rp; r++ {
+		for i := 0; i < t; i++ {
+			f.Add(state[i], state[i], p.c[r*t+i])
+		}
+		if r < half || r >= half+p.rp {
+			for i := 0; i < t; i++ {
+				p.sbox(state[i])
+			}
+		} else {
+			p.sbox(state[0])
+		}
+		for i := 0; i < t; i++ {
+			next[i] = jubjub.FieldElement{}
+			for j := 0; j < t; j++ {
+				f.Mul(tmp, p.m[i][j], state[j])
+				f.Add(&next[i], &next[i], tmp)
+			}
+		}
+		for i := 0; i < t; i++ {
+			state[i].Set(&next[i])
+		}
+	}
+}
+
+// a = a^5
+func (p *Poseidon) sbox(a *jubjub.FieldElement) {
+	t := new(jubjub.FieldElement)
+	p.field.Square(t, a)
+	p.field.Square(t, t)
+	p.field.Mul(a, a, t)
+}
+
+// Fixed length hash as in circomlib
+// state is initialized as [0, inputs...] and the first element is the output
+// expects exactly t - 1 inputs
+func (p *Poseidon) Hash(inputs []*jubjub.FieldElement) (*jubjub.FieldElement, error) {
+	if len(inputs) != p.t-1 {
+		return nil, fmt.Errorf("bad number of inputs")
+	}
+	state := make([]*jubjub.FieldElement, p.t)
+	state[0] = new(jubjub.FieldElement)
+	for i := range inputs {
+		state[i+1] = new(jubjub.FieldElement).Set(inputs[i])
+	}
+	p.permute(state)
+	return state[0], nil
+}
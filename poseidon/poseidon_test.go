@@ -0,0 +1,187 @@
+package poseidon
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/kilic/go-jubjub"
+)
+
+func fromStr16(field *jubjub.Field, s string) *jubjub.FieldElement {
+	n, _ := new(big.Int).SetString(s, 16)
+	return field.NewElement(n.Bytes())
+}
+
+func fromUint(field *jubjub.Field, a uint64) *jubjub.FieldElement {
+	return field.NewElement(new(big.Int).SetUint64(a).Bytes())
+}
+
+func toStr10(field *jubjub.Field, a *jubjub.FieldElement) string {
+	return new(big.Int).SetBytes(field.ToBytes(a)).String()
+}
+
+// test vectors are from the reference implementation
+// https://extgit.iaik.tugraz.at/krypto/hadeshash
+
+func TestPoseidonWidth3(t *testing.T) {
+	p := NewWidth3()
+	field := p.Field()
+	state := []*jubjub.FieldElement{fromUint(field, 0), fromUint(field, 1), fromUint(field, 2)}
+	expected := []string{
+		"28ce19420fc246a05553ad1e8c98f5c9d67166be2c18e9e4cb4b4e317dd2a78a",
+		"51f3e312c95343a896cfd8945ea82ba956c1118ce9b9859b6ea56637b4b1ddc4",
+		"3b2b69139b235626a0bfb56c9527ae66a7bf486ad8c11c14d1da0c69bbe0f79a",
+	}
+	if err := p.Permute(state); err != nil {
+		t.Fatal(err)
+	}
+	for i := range state {
+		if !state[i].Eq(fromStr16(field, expected[i])) {
+			t.Errorf("bad permutation output %d", i)
+		}
+	}
+}
+
+func TestPoseidonWidth5(t *testing.T) {
+	p := NewWidth5()
+	field := p.Field()
+	state := make([]*jubjub.FieldElement, 5)
+	for i := range state {
+		state[i] = fromUint(field, uint64(i))
+	}
+	expected := []string{
+		"2a918b9c9f9bd7bb509331c81e297b5707f6fc7393dcee1b13901a0b22202e18",
+		"65ebf8671739eeb11fb217f2d5c5bf4a0c3f210e3f3cd3b08b5db75675d797f7",
+		"2cc176fc26bc70737a696a9dfd1b636ce360ee76926d182390cdb7459cf585ce",
+		"4dc4e29d283afd2a491fe6aef122b9a968e74eff05341f3cc23fda1781dcb566",
+		"03ff622da276830b9451b88b85e6184fd6ae15c8ab3ee25a5667be8592cce3b1",
+	}
+	if err := p.Permute(state); err != nil {
+		t.Fatal(err)
+	}
+	for i := range state {
+		if !state[i].Eq(fromStr16(field, expected[i])) {
+			t.Errorf("bad permutation output %d", i)
+		}
+	}
+}
+
+// test vectors are from circomlib which uses the same constant generation over BN254
+func TestPoseidonCircomlib(t *testing.T) {
+	field := jubjub.NewBabyJubjub().Field()
+	vectors := []struct {
+		rp  int
+		in  []uint64
+		out string
+	}{
+		{56, []uint64{1}, "18586133768512220936620570745912940619677854269274689475585506675881198879027"},
+		{57, []uint64{1, 2}, "7853200120776062878684798364095072458815029376092732009249414926327459813530"},
+		{60, []uint64{1, 2, 0, 0, 0}, "1018317224307729531995786483840663576608797660851238720571059489595066344487"},
+		{60, []uint64{3, 4, 0, 0, 0}, "5811595552068139067952687508729883632420015185677766880877743348592482390548"},
+		{63, []uint64{1, 2, 3, 4, 5, 6}, "20400040500897583745843009878988256314335038853985262692600694741116813247201"},
+	}
+	for i, v := range vectors {
+		p, err := New(field, len(v.in)+1, 8, v.rp)
+		if err != nil {
+			t.Fatal(err)
+		}
+		in := make([]*jubjub.FieldElement, len(v.in))
+		for j := range v.in {
+			in[j] = fromUint(field, v.in[j])
+		}
+		out, err := p.Hash(in)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if toStr10(field, out) != v.out {
+			t.Errorf("bad hash %d, have: %s, want: %s", i, toStr10(field, out), v.out)
+		}
+	}
+}
+
+func TestPoseidonParams(t *testing.T) {
+	field := jubjub.NewJubjub().Field()
+	if _, err := New(field, 1, 8, 57); err == nil {
+		t.Errorf("expected error for width 1")
+	}
+	if _, err := New(field, 3, 7, 57); err == nil {
+		t.Errorf("expected error for odd number of full rounds")
+	}
+	if _, err := NewWithMDS(field, 3, 8, 57, nil); err == nil {
+		t.Errorf("expected error for bad mds matrix")
+	}
+	p := NewWidth3()
+	if _, err := NewWithMDS(field, 3, 8, 57, p.m); err != nil {
+		t.Fatal(err)
+	}
+	if err := p.Permute(make([]*jubjub.FieldElement, 2)); err == nil {
+		t.Errorf("expected error for bad state size")
+	}
+	if _, err := p.Hash(make([]*jubjub.FieldElement, 3)); err == nil {
+		t.Errorf("expected error for bad number of inputs")
+	}
+}
+
+func TestPoseidonSponge(t *testing.T) {
+	p := NewWidth3()
+	field := p.Field()
+	in := make([]*jubjub.FieldElement, 7)
+	for i := range in {
+		in[i] = fromUint(field, uint64(i+1))
+	}
+	s0 := p.NewSponge()
+	s0.Absorb(in...)
+	s1 := p.NewSponge()
+	for i := range in {
+		s1.Absorb(in[i])
+	}
+	for i := 0; i < 5; i++ {
+		if !s0.Squeeze().Eq(s1.Squeeze()) {
+			t.Fatalf("absorbing at once and one by one are expected to give the same output")
+		}
+	}
+	// padding separates inputs with trailing zeros
+	s0, s1 = p.NewSponge(), p.NewSponge()
+	s0.Absorb(in[0])
+	s1.Absorb(in[0], new(jubjub.FieldElement))
+	if s0.Squeeze().Eq(s1.Squeeze()) {
+		t.Errorf("inputs of different lengths are not expected to collide")
+	}
+	s0, s1 = p.NewSponge(), p.NewSponge()
+	s0.Absorb(in[:2]...)
+	s1.Absorb(in[:3]...)
+	if s0.Squeeze().Eq(s1.Squeeze()) {
+		t.Errorf("inputs of different lengths are not expected to collide")
+	}
+	// squeezed outputs are distinct
+	s0 = p.NewSponge()
+	s0.Absorb(in...)
+	a, b, c := s0.Squeeze(), s0.Squeeze(), s0.Squeeze()
+	if a.Eq(b) || b.Eq(c) {
+		t.Errorf("squeezed elements are not expected to repeat")
+	}
+}
+
+func BenchmarkPoseidonWidth3(t *testing.B) {
+	p := NewWidth3()
+	state := make([]*jubjub.FieldElement, 3)
+	for i := range state {
+		state[i] = fromUint(p.Field(), uint64(i))
+	}
+	t.ResetTimer()
+	for i := 0; i < t.N; i++ {
+		p.permute(state)
+	}
+}
+
+func BenchmarkPoseidonWidth5(t *testing.B) {
+	p := NewWidth5()
+	state := make([]*jubjub.FieldElement, 5)
+	for i := range state {
+		state[i] = fromUint(p.Field(), uint64(i))
+	}
+	t.ResetTimer()
+	for i := 0; i < t.N; i++ {
+		p.permute(state)
+	}
+}
@@ -0,0 +1,62 @@
+package poseidon
+
+import "github.com/kilic/go-jubjub"
+
+// Sponge over the Poseidon permutation
+// first element of the state is the capacity, rest is the rate.
+// When switching from absorbing to squeezing the input is padded with 10*
+// so that inputs of different lengths never collide.
+type Sponge struct {
+	p         *Poseidon
+	state     []*jubjub.FieldElement
+	pos       int
+	squeezing bool
+}
+
+func (p *Poseidon) NewSponge() *Sponge {
+	state := make([]*jubjub.FieldElement, p.t)
+	for i := range state {
+		state[i] = new(jubjub.FieldElement)
+	}
+	return &Sponge{
+		p:     p,
+		state: state,
+	}
+}
+
+func (s *Sponge) rate() int {
+	return s.p.t - 1
+}
+
+func (s *Sponge) Absorb(inputs ...*jubjub.FieldElement) {
+	f := s.p.field
+	if s.squeezing {
+		s.squeezing = false
+		s.pos = 0
+	}
+	for _, in := range inputs {
+		f.Add(s.state[1+s.pos], s.state[1+s.pos], in)
+		s.pos++
+		if s.pos == s.rate() {
+			s.p.permute(s.state)
+			s.pos = 0
+		}
+	}
+}
+
+func (s *Sponge) Squeeze() *jubjub.FieldElement {
+	f := s.p.field
+	if !s.squeezing {
+		f.Add(s.state[1+s.pos], s.state[1+s.pos], f.NewElement([]byte{1}))
+		s.p.permute(s.state)
+		s.squeezing = true
+		s.pos = 0
+	}
+	if s.pos == s.rate() {
+		s.p.permute(s.state)
+		s.pos = 0
+	}
+	out := new(jubjub.FieldElement).Set(s.state[1+s.pos])
+	s.pos++
+	return out
+}
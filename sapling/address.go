@@ -0,0 +1,135 @@
+package sapling
+
+import (
+	"fmt"
+
+	"github.com/kilic/go-jubjub"
+)
+
+// 4.2.2 Sapling Key Components
+// 5.6.3.1 Sapling Payment Addresses
+
+type Diversifier [11]byte
+
+type PaymentAddress struct {
+	D   Diversifier
+	PkD *jubjub.ExtendedPoint
+}
+
+type Network int
+
+const (
+	Mainnet Network = iota
+	Testnet
+	Regtest
+)
+
+var paymentAddressHRP = map[Network]string{
+	Mainnet: "zs",
+	Testnet: "ztestsapling",
+	Regtest: "zregtestsapling",
+}
+
+// DiversifyHash(d) = GroupHash^J("Zcash_gd", d)
+// returns false for invalid diversifiers
+func (s *Sapling) DiversifyHash(d *Diversifier) (*jubjub.ExtendedPoint, bool) {
+	return s.groupHash("Zcash_gd", d[:])
+}
+
+// pk_d = ivk.g_d where g_d = DiversifyHash(d)
+func (s *Sapling) Address(ivk *IncomingViewingKey, d *Diversifier) (*PaymentAddress, error) {
+	gd, ok := s.DiversifyHash(d)
+	if !ok {
+		return nil, fmt.Errorf("invalid diversifier")
+	}
+	addr := &PaymentAddress{D: *d, PkD: s.curve.NewExtendedPoint()}
+	s.curve.Mul(addr.PkD, gd, ivk.Ivk)
+	return addr, nil
+}
+
+// Returns the address at the first valid diversifier starting from d
+// where diversifiers are incremented as 88 bits little endian integers
+func (s *Sapling) NextAddress(ivk *IncomingViewingKey, d *Diversifier) (*PaymentAddress, error) {
+	next := *d
+	for {
+		if addr, err := s.Address(ivk, &next); err == nil {
+			return addr, nil
+		}
+		if !incrementDiversifier(&next) {
+			return nil, fmt.Errorf("diversifier space is exhausted")
+		}
+	}
+}
+
+func incrementDiversifier(d *Diversifier) bool {
+	for i := range d {
+		d[i]++
+		if d[i] != 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// Default diversifier is the first valid truncate_11(PRF^expand(sk, [3, i]))
+func (s *Sapling) DefaultAddress(sk *SpendingKey) (*PaymentAddress, error) {
+	fvk := s.FullViewingKey(s.ExpandSpendingKey(sk))
+	ivk := s.IncomingViewingKey(fvk)
+	var d Diversifier
+	for i := 0; i < 256; i++ {
+		copy(d[:], PRFExpand(sk[:], 3, byte(i)))
+		if addr, err := s.Address(ivk, &d); err == nil {
+			return addr, nil
+		}
+	}
+	return nil, fmt.Errorf("no valid default diversifier")
+}
+
+// Returns 43 bytes raw encoding d || repr(pk_d)
+func (s *Sapling) EncodeAddress(addr *PaymentAddress) []byte {
+	out := make([]byte, 0, 43)
+	out = append(out, addr.D[:]...)
+	return append(out, s.encodePoint(addr.PkD)...)
+}
+
+func (s *Sapling) DecodeAddress(in []byte) (*PaymentAddress, error) {
+	if len(in) != 43 {
+		return nil, fmt.Errorf("bad address input size")
+	}
+	addr := new(PaymentAddress)
+	copy(addr.D[:], in[:11])
+	if _, ok := s.DiversifyHash(&addr.D); !ok {
+		return nil, fmt.Errorf("invalid diversifier")
+	}
+	pkd, err := s.curve.NewExtendedPointFromCompressed(in[11:])
+	if err != nil {
+		return nil, err
+	}
+	if !s.curve.IsInSubgroup(pkd) {
+		return nil, fmt.Errorf("point is not in prime order subgroup")
+	}
+	addr.PkD = pkd
+	return addr, nil
+}
+
+func (s *Sapling) EncodeAddressBech32(addr *PaymentAddress, network Network) (string, error) {
+	hrp, ok := paymentAddressHRP[network]
+	if !ok {
+		return "", fmt.Errorf("unknown network")
+	}
+	return bech32Encode(hrp, s.EncodeAddress(addr)), nil
+}
+
+func (s *Sapling) DecodeAddressBech32(in string) (*PaymentAddress, Network, error) {
+	hrp, data, err := bech32Decode(in)
+	if err != nil {
+		return nil, 0, err
+	}
+	for network, h := range paymentAddressHRP {
+		if h == hrp {
+			addr, err := s.DecodeAddress(data)
+			return addr, network, err
+		}
+	}
+	return nil, 0, fmt.Errorf("unknown human readable part %s", hrp)
+}
@@ -0,0 +1,170 @@
+package sapling
+
+import (
+	"encoding/hex"
+	"strings"
+	"testing"
+)
+
+func TestDefaultAddress(t *testing.T) {
+	s := NewSapling()
+	vectors := []struct {
+		sk, d, pkd string
+	}{
+		{"0000000000000000000000000000000000000000000000000000000000000000", "f19d9b797e39f337445839", "db4cd2b0aac4f7eb8ca131f16567c445a9555126d3c29f14e3d776e841ae7415"},
+		{"0101010101010101010101010101010101010101010101010101010101010101", "aef180f6e34e354b888f81", "a6b13ea336ddb7a67bb09a0e68e9d3cfb39210831ea3a296ba09a922060fd38b"},
+		{"0202020202020202020202020202020202020202020202020202020202020202", "7599f0bf9b57cd2dc299b6", "66141739514b28f05def8a18eeee5eed4d44c6225c3c65d88dd9907708012f5a"},
+		{"0303030303030303030303030303030303030303030303030303030303030303", "1b81614f1dadea0f8d0a58", "25eb55fccf761fc64e85a588efe6ead7832fb1f0f7a83165895bdff942925f5c"},
+		{"0404040404040404040404040404040404040404040404040404040404040404", "fcfb68a40d4bc6a04b09c4", "8b2a337f03622c24ff381d4c546f6977f90522e92fde44c9d1bb099714b9db2b"},
+		{"0505050505050505050505050505050505050505050505050505050505050505", "eb519882ad1e5cc654cd59", "6b27daccb5a8207f532d10ca238f9786648a11b5966e51a2f7d89e15d29b8fdf"},
+		{"0606060606060606060606060606060606060606060606060606060606060606", "bebb0fb46b8aaff89040f6", "d11da01f0b43bdd5288d32385b8771d223493c69802544043f77cf1d71c1cb8c"},
+		{"0707070707070707070707070707070707070707070707070707070707070707", "ad6e2e185a3100e3a6a8b3", "32cb2806b882f1368b0d4a898f72c4c8f728132cc12456946e7f4cb0fb058da9"},
+		{"0808080808080808080808080808080808080808080808080808080808080808", "21c90e1c658b3efe86af58", "9e64174b4ab981405c323b5e12475945a46d4fedf8060828041cd20e62fd2cef"},
+		{"0909090909090909090909090909090909090909090909090909090909090909", "233c4ab886a55e3ba374c0", "b68e9ee0c0678d7b3036931c831a25255f7ee487385a30316e15f6482b874fda"},
+	}
+	for i, v := range vectors {
+		var sk SpendingKey
+		copy(sk[:], fromHex(v.sk))
+		addr, err := s.DefaultAddress(&sk)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if hex.EncodeToString(addr.D[:]) != v.d {
+			t.Errorf("bad default diversifier %d", i)
+		}
+		if hex.EncodeToString(s.encodePoint(addr.PkD)) != v.pkd {
+			t.Errorf("bad pk_d %d", i)
+		}
+	}
+}
+
+func TestNextAddress(t *testing.T) {
+	s := NewSapling()
+	var sk SpendingKey
+	ivk := s.IncomingViewingKey(s.FullViewingKey(s.ExpandSpendingKey(&sk)))
+	var d Diversifier
+	found := 0
+	for i := 0; i < 16; i++ {
+		addr, err := s.NextAddress(ivk, &d)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, ok := s.DiversifyHash(&addr.D); !ok {
+			t.Fatalf("diversifier is expected to be valid")
+		}
+		if addr.D != d {
+			// every skipped diversifier must be invalid
+			for e := d; e != addr.D; incrementDiversifier(&e) {
+				if _, ok := s.DiversifyHash(&e); ok {
+					t.Fatalf("valid diversifier is skipped")
+				}
+			}
+		}
+		found++
+		d = addr.D
+		incrementDiversifier(&d)
+	}
+	if found != 16 {
+		t.Errorf("expected 16 addresses")
+	}
+	d = Diversifier{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff}
+	if incrementDiversifier(&d) || d != (Diversifier{}) {
+		t.Errorf("diversifier is expected to wrap around")
+	}
+}
+
+// Bech32 strings are computed with the reference implementation
+// in https://github.com/zcash/zcash-test-vectors
+func TestAddressBech32(t *testing.T) {
+	s := NewSapling()
+	vectors := []struct {
+		raw     string
+		network Network
+		out     string
+	}{
+		{"f19d9b797e39f337445839db4cd2b0aac4f7eb8ca131f16567c445a9555126d3c29f14e3d776e841ae7415", Mainnet,
+			"zs17xwek7t788enw3zc88d5e54s4tz006uv5yclzet8c3z6j423ymfu98c5u0thd6zp4e6p2jumnna"},
+		{"f19d9b797e39f337445839db4cd2b0aac4f7eb8ca131f16567c445a9555126d3c29f14e3d776e841ae7415", Testnet,
+			"ztestsapling17xwek7t788enw3zc88d5e54s4tz006uv5yclzet8c3z6j423ymfu98c5u0thd6zp4e6p26tfs5f"},
+		{"aef180f6e34e354b888f81a6b13ea336ddb7a67bb09a0e68e9d3cfb39210831ea3a296ba09a922060fd38b", Mainnet,
+			"zs14mccpahrfc65hzy0sxntz04rxmwm0fnmkzdqu68f608m8ysssv028g5khgy6jgsxplfckyxhys5"},
+		{"aef180f6e34e354b888f81a6b13ea336ddb7a67bb09a0e68e9d3cfb39210831ea3a296ba09a922060fd38b", Testnet,
+			"ztestsapling14mccpahrfc65hzy0sxntz04rxmwm0fnmkzdqu68f608m8ysssv028g5khgy6jgsxplfckv398hq"},
+	}
+	for i, v := range vectors {
+		addr, err := s.DecodeAddress(fromHex(v.raw))
+		if err != nil {
+			t.Fatal(err)
+		}
+		out, err := s.EncodeAddressBech32(addr, v.network)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if out != v.out {
+			t.Errorf("bad encoding %d, have: %s, want: %s", i, out, v.out)
+		}
+		addr2, network, err := s.DecodeAddressBech32(strings.ToUpper(v.out))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if network != v.network || hex.EncodeToString(s.EncodeAddress(addr2)) != v.raw {
+			t.Errorf("bad decoding %d", i)
+		}
+	}
+	// flip a character
+	bad := []byte(vectors[0].out)
+	bad[10] = 'q'
+	if _, _, err := s.DecodeAddressBech32(string(bad)); err == nil {
+		t.Errorf("bad checksum is not expected to be accepted")
+	}
+	if _, _, err := s.DecodeAddressBech32("zs1" + vectors[0].out[3:10] + strings.ToUpper(vectors[0].out[10:])); err == nil {
+		t.Errorf("mixed case is not expected to be accepted")
+	}
+	if _, _, err := s.DecodeAddressBech32(bech32Encode("zc", fromHex(vectors[0].raw))); err == nil {
+		t.Errorf("unknown human readable part is not expected to be accepted")
+	}
+	if _, _, err := s.DecodeAddressBech32(bech32Encode("zs", fromHex(vectors[0].raw)[:42])); err == nil {
+		t.Errorf("short address is not expected to be accepted")
+	}
+}
+
+// test vectors are from BIP-173
+func TestBech32(t *testing.T) {
+	valid := []string{
+		"A12UEL5L",
+		"a12uel5l",
+		"an83characterlonghumanreadablepartthatcontainsthenumber1andtheexcludedcharactersbio1tt5tgs",
+		"abcdef1qpzry9x8gf2tvdw0s3jn54khce6mua7lmqqqxw",
+		"split1checkupstagehandshakeupstreamerranterredcaperred2y9e3w",
+		"?1ezyfcl",
+	}
+	for _, v := range valid {
+		hrp, _, err := bech32Decode(v)
+		if err != nil {
+			// data part of some vectors is not byte aligned
+			if err.Error() == "bad bech32 padding" {
+				continue
+			}
+			t.Errorf("%s is expected to be valid: %v", v, err)
+			continue
+		}
+		if hrp != strings.ToLower(v[:strings.LastIndexByte(v, '1')]) {
+			t.Errorf("bad human readable part")
+		}
+	}
+	invalid := []string{
+		"\x201nwldj5",
+		"pzry9x0s0muk",
+		"1pzry9x0s0muk",
+		"x1b4n0q5v",
+		"li1dgmt3",
+		"A1G7SGD8",
+		"10a06t8",
+		"1qzzfhee",
+	}
+	for _, v := range invalid {
+		if _, _, err := bech32Decode(v); err == nil {
+			t.Errorf("%s is not expected to be valid", v)
+		}
+	}
+}
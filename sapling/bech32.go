@@ -0,0 +1,114 @@
+package sapling
+
+import (
+	"fmt"
+	"strings"
+)
+
+// BIP-173 Bech32 without the 90 characters length limit
+// since ZIP-32 extended keys are longer
+const bech32Charset = "qpzry9x8gf2tvdw0s3jn54khce6mua7l"
+
+func bech32Polymod(values []byte) uint32 {
+	gen := [5]uint32{0x3b6a57b2, 0x26508e6d, 0x1ea119fa, 0x3d4233dd, 0x2a1462b3}
+	chk := uint32(1)
+	for _, v := range values {
+		b := chk >> 25
+		chk = (chk&0x1ffffff)<<5 ^ uint32(v)
+		for i := 0; i < 5; i++ {
+			if (b>>uint(i))&1 == 1 {
+				chk ^= gen[i]
+			}
+		}
+	}
+	return chk
+}
+
+func bech32HRPExpand(hrp string) []byte {
+	out := make([]byte, 0, 2*len(hrp)+1)
+	for i := 0; i < len(hrp); i++ {
+		out = append(out, hrp[i]>>5)
+	}
+	out = append(out, 0)
+	for i := 0; i < len(hrp); i++ {
+		out = append(out, hrp[i]&31)
+	}
+	return out
+}
+
+// Encodes 8 bit data under human readable part hrp
+func bech32Encode(hrp string, data []byte) string {
+	values := convertBits(data, 8, 5, true)
+	in := append(bech32HRPExpand(hrp), values...)
+	in = append(in, 0, 0, 0, 0, 0, 0)
+	mod := bech32Polymod(in) ^ 1
+	var sb strings.Builder
+	sb.WriteString(hrp)
+	sb.WriteByte('1')
+	for _, v := range values {
+		sb.WriteByte(bech32Charset[v])
+	}
+	for i := 0; i < 6; i++ {
+		sb.WriteByte(bech32Charset[(mod>>uint(5*(5-i)))&31])
+	}
+	return sb.String()
+}
+
+// Returns human readable part and 8 bit data
+func bech32Decode(s string) (string, []byte, error) {
+	lower, upper := strings.ToLower(s), strings.ToUpper(s)
+	if s != lower && s != upper {
+		return "", nil, fmt.Errorf("mixed case bech32 string")
+	}
+	s = lower
+	pos := strings.LastIndexByte(s, '1')
+	if pos < 1 || pos+7 > len(s) {
+		return "", nil, fmt.Errorf("bad bech32 separator position")
+	}
+	hrp := s[:pos]
+	for i := 0; i < len(hrp); i++ {
+		if hrp[i] < 33 || hrp[i] > 126 {
+			return "", nil, fmt.Errorf("bad bech32 human readable part")
+		}
+	}
+	values := make([]byte, 0, len(s)-pos-1)
+	for i := pos + 1; i < len(s); i++ {
+		v := strings.IndexByte(bech32Charset, s[i])
+		if v < 0 {
+			return "", nil, fmt.Errorf("bad bech32 character")
+		}
+		values = append(values, byte(v))
+	}
+	if bech32Polymod(append(bech32HRPExpand(hrp), values...)) != 1 {
+		return "", nil, fmt.Errorf("bad bech32 checksum")
+	}
+	data := convertBits(values[:len(values)-6], 5, 8, false)
+	if data == nil {
+		return "", nil, fmt.Errorf("bad bech32 padding")
+	}
+	return hrp, data, nil
+}
+
+// Regroups bits, returns nil if padding is not allowed and input has non zero or excess padding
+func convertBits(data []byte, from, to uint, pad bool) []byte {
+	var acc uint32
+	var n uint
+	max := uint32(1)<<to - 1
+	out := make([]byte, 0, len(data)*int(from)/int(to)+1)
+	for _, v := range data {
+		acc = acc<<from | uint32(v)
+		n += from
+		for n >= to {
+			n -= to
+			out = append(out, byte((acc>>n)&max))
+		}
+	}
+	if pad {
+		if n > 0 {
+			out = append(out, byte((acc<<(to-n))&max))
+		}
+	} else if n >= from || (acc<<(to-n))&max != 0 {
+		return nil
+	}
+	return out
+}
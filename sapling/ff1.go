@@ -0,0 +1,108 @@
+package sapling
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"fmt"
+	"math/big"
+)
+
+// NIST Special Publication 800-38G
+// Recommendation for Block Cipher Modes of Operation: Methods for Format-Preserving Encryption
+// Dworkin
+//
+// FF1-AES256 specialized to radix 2 and 88 bits inputs as used for Sapling diversifiers.
+// Bit strings are given as one bit per byte.
+
+const ff1Bits = 88
+
+func ff1AES256Encrypt(key, tweak, x []byte) ([]byte, error) {
+	return ff1AES256(key, tweak, x, true)
+}
+
+func ff1AES256Decrypt(key, tweak, x []byte) ([]byte, error) {
+	return ff1AES256(key, tweak, x, false)
+}
+
+func ff1AES256(key, tweak, x []byte, encrypt bool) ([]byte, error) {
+	if len(key) != 32 {
+		return nil, fmt.Errorf("bad ff1 key size")
+	}
+	if len(x) != ff1Bits {
+		return nil, fmt.Errorf("bad ff1 input size")
+	}
+	if len(tweak) > 255 {
+		return nil, fmt.Errorf("ff1 tweak is too long")
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	n, t := len(x), len(tweak)
+	u := n / 2
+	b := (u + 7) / 8
+	d := 4*((b+3)/4) + 4
+	p := []byte{1, 2, 1, 0, 0, 2, 10, byte(u), 0, 0, 0, byte(n), 0, 0, 0, byte(t)}
+	mod := new(big.Int).Lsh(big.NewInt(1), uint(u))
+	a, c := bitsToBig(x[:u]), bitsToBig(x[u:])
+	for j := 0; j < 10; j++ {
+		i := j
+		if !encrypt {
+			i = 9 - j
+		}
+		// Q = T || [0]^((-t-b-1) mod 16) || [i] || [NUM(B)]^b
+		q := make([]byte, t+((-t-b-1)%16+16)%16+1+b)
+		copy(q, tweak)
+		q[len(q)-b-1] = byte(i)
+		if encrypt {
+			c.FillBytes(q[len(q)-b:])
+		} else {
+			a.FillBytes(q[len(q)-b:])
+		}
+		y := new(big.Int).SetBytes(cbcMAC(block, append(p, q...))[:d])
+		if encrypt {
+			// A, B = B, (NUM(A) + y) mod 2^u
+			y.Add(y, a).Mod(y, mod)
+			a, c = c, y
+		} else {
+			// A, B = (NUM(B) - y) mod 2^u, A
+			y.Sub(c, y).Mod(y, mod)
+			a, c = y, a
+		}
+	}
+	out := make([]byte, n)
+	bigToBits(out[:u], a)
+	bigToBits(out[u:], c)
+	return out, nil
+}
+
+// CBC-MAC with zero IV, input is a multiple of the block size
+func cbcMAC(block cipher.Block, in []byte) []byte {
+	y := make([]byte, 16)
+	for i := 0; i < len(in); i += 16 {
+		for j := 0; j < 16; j++ {
+			y[j] ^= in[i+j]
+		}
+		block.Encrypt(y, y)
+	}
+	return y
+}
+
+// NUM_2, big endian bits to integer
+func bitsToBig(x []byte) *big.Int {
+	n := new(big.Int)
+	for _, v := range x {
+		n.Lsh(n, 1)
+		if v != 0 {
+			n.SetBit(n, 0, 1)
+		}
+	}
+	return n
+}
+
+// STR_2, integer to big endian bits
+func bigToBits(out []byte, n *big.Int) {
+	for i := range out {
+		out[i] = byte(n.Bit(len(out) - 1 - i))
+	}
+}
@@ -0,0 +1,36 @@
+package sapling
+
+import "github.com/kilic/go-jubjub"
+
+// Uniform random string, first 64 bytes of the BLAKE2s input in group hash
+const urs = "096b36a5804bfacef1691e173c366a47ff5ba84a44f26ddd7e8d9f79d5b42df0"
+
+// 5.4.9.5 Group Hash into Jubjub
+// GroupHash^J(D, M) = [h_J] abst_J(BLAKE2s-256(D, URS || M))
+// returns false if the hash is not a point or the result is the identity
+func (s *Sapling) groupHash(personal string, msg []byte) (*jubjub.ExtendedPoint, bool) {
+	h := blake2s(32, personal, []byte(urs), msg)
+	p, err := s.curve.NewExtendedPointFromCompressed(h)
+	if err != nil {
+		return nil, false
+	}
+	q := s.curve.NewExtendedPoint()
+	s.curve.Mul(q, p, s.scalarField.NewElementFromBig(s.curve.Cofactor()))
+	if q.Eq(s.curve.NewExtendedPoint()) {
+		return nil, false
+	}
+	return q, true
+}
+
+// FindGroupHash^J(D, M) is the first successful GroupHash^J(D, M || [i])
+func (s *Sapling) findGroupHash(personal string, msg []byte) *jubjub.ExtendedPoint {
+	in := make([]byte, len(msg)+1)
+	copy(in, msg)
+	for i := 0; i < 256; i++ {
+		in[len(msg)] = byte(i)
+		if p, ok := s.groupHash(personal, in); ok {
+			return p
+		}
+	}
+	panic("group hash failed")
+}
@@ -0,0 +1,124 @@
+package sapling
+
+import (
+	"fmt"
+
+	"github.com/kilic/go-jubjub"
+)
+
+// 4.2.2 Sapling Key Components
+
+type SpendingKey [32]byte
+
+type ExpandedSpendingKey struct {
+	Ask *jubjub.ScalarFieldElement
+	Nsk *jubjub.ScalarFieldElement
+	Ovk [32]byte
+}
+
+type FullViewingKey struct {
+	Ak  *jubjub.ExtendedPoint
+	Nk  *jubjub.ExtendedPoint
+	Ovk [32]byte
+}
+
+type IncomingViewingKey struct {
+	Ivk *jubjub.ScalarFieldElement
+}
+
+// ask = ToScalar(PRF^expand(sk, [0]))
+// nsk = ToScalar(PRF^expand(sk, [1]))
+// ovk = truncate_32(PRF^expand(sk, [2]))
+func (s *Sapling) ExpandSpendingKey(sk *SpendingKey) *ExpandedSpendingKey {
+	esk := &ExpandedSpendingKey{
+		Ask: s.toScalar(PRFExpand(sk[:], 0)),
+		Nsk: s.toScalar(PRFExpand(sk[:], 1)),
+	}
+	copy(esk.Ovk[:], PRFExpand(sk[:], 2))
+	return esk
+}
+
+// ak = ask.G^Sapling
+// nk = nsk.H^Sapling
+func (s *Sapling) FullViewingKey(esk *ExpandedSpendingKey) *FullViewingKey {
+	fvk := &FullViewingKey{
+		Ak:  s.curve.NewExtendedPoint(),
+		Nk:  s.curve.NewExtendedPoint(),
+		Ovk: esk.Ovk,
+	}
+	s.curve.Mul(fvk.Ak, s.spendingKeyGenerator, esk.Ask)
+	s.curve.Mul(fvk.Nk, s.proofGenerationKeyGenerator, esk.Nsk)
+	return fvk
+}
+
+// 5.4.1.5 CRH^ivk Hash Function
+// ivk = BLAKE2s-256("Zcashivk", repr(ak) || repr(nk)) truncated to 251 bits
+func (s *Sapling) IncomingViewingKey(fvk *FullViewingKey) *IncomingViewingKey {
+	h := blake2s(32, "Zcashivk", s.encodePoint(fvk.Ak), s.encodePoint(fvk.Nk))
+	h[31] &= 0x07
+	return &IncomingViewingKey{
+		Ivk: s.scalarField.NewElementFromBig(leToBig(h)),
+	}
+}
+
+// Returns ask || nsk || ovk
+func (s *Sapling) EncodeExpandedSpendingKey(esk *ExpandedSpendingKey) []byte {
+	out := make([]byte, 0, 96)
+	out = append(out, s.scalarField.ToBytes(esk.Ask)...)
+	out = append(out, s.scalarField.ToBytes(esk.Nsk)...)
+	return append(out, esk.Ovk[:]...)
+}
+
+func (s *Sapling) DecodeExpandedSpendingKey(in []byte) (*ExpandedSpendingKey, error) {
+	if len(in) != 96 {
+		return nil, fmt.Errorf("bad expanded spending key input size")
+	}
+	ask, err := s.scalarField.FromBytes(in[:32])
+	if err != nil {
+		return nil, err
+	}
+	nsk, err := s.scalarField.FromBytes(in[32:64])
+	if err != nil {
+		return nil, err
+	}
+	esk := &ExpandedSpendingKey{Ask: ask, Nsk: nsk}
+	copy(esk.Ovk[:], in[64:])
+	return esk, nil
+}
+
+// Returns repr(ak) || repr(nk) || ovk
+func (s *Sapling) EncodeFullViewingKey(fvk *FullViewingKey) []byte {
+	out := make([]byte, 0, 96)
+	out = append(out, s.encodePoint(fvk.Ak)...)
+	out = append(out, s.encodePoint(fvk.Nk)...)
+	return append(out, fvk.Ovk[:]...)
+}
+
+// ak and nk are expected to be in the prime order subgroup and ak is not the identity
+func (s *Sapling) DecodeFullViewingKey(in []byte) (*FullViewingKey, error) {
+	if len(in) != 96 {
+		return nil, fmt.Errorf("bad full viewing key input size")
+	}
+	ak, err := s.curve.NewExtendedPointFromCompressed(in[:32])
+	if err != nil {
+		return nil, err
+	}
+	nk, err := s.curve.NewExtendedPointFromCompressed(in[32:64])
+	if err != nil {
+		return nil, err
+	}
+	if !s.curve.IsInSubgroup(ak) || !s.curve.IsInSubgroup(nk) {
+		return nil, fmt.Errorf("point is not in prime order subgroup")
+	}
+	if ak.Eq(s.curve.NewExtendedPoint()) {
+		return nil, fmt.Errorf("ak is the identity")
+	}
+	fvk := &FullViewingKey{Ak: ak, Nk: nk}
+	copy(fvk.Ovk[:], in[64:])
+	return fvk, nil
+}
+
+// Returns 32 bytes little endian encoding of ivk
+func (ivk *IncomingViewingKey) Bytes() []byte {
+	return reverse(ivk.Ivk.Big().FillBytes(make([]byte, 32)))
+}
@@ -0,0 +1,277 @@
+package sapling
+
+import (
+	"fmt"
+)
+
+// 3.8 Note Commitment Trees
+// 4.9 Merkle Path Validity
+// 5.4.1.3 MerkleCRH^Sapling Hash Function
+
+const MerkleDepth = 32
+
+// Nodes of the tree are little endian u-coordinates, leaves are note commitments cm_u
+type MerkleNode [32]byte
+
+// MerkleCRH^Sapling(layer, left, right) = PedersenHash("Zcash_PH", l || LEBS2IP_255(left) || LEBS2IP_255(right))
+// where l = I2LEBSP_6(MerkleDepth - 1 - layer), that is the height of the hashed nodes
+func (s *Sapling) MerkleHash(height int, left, right *MerkleNode) MerkleNode {
+	m := make([]bool, 6, 6+255+255)
+	for i := range m {
+		m[i] = (height>>uint(i))&1 == 1
+	}
+	m = append(m, bytesToBits(left[:])[:255]...)
+	m = append(m, bytesToBits(right[:])[:255]...)
+	var node MerkleNode
+	copy(node[:], s.PedersenHash("Zcash_PH", m))
+	return node
+}
+
+// Roots of empty subtrees of each height, the uncommitted leaf is I2LEBSP_255(1)
+func (s *Sapling) emptyRoots() [MerkleDepth + 1]MerkleNode {
+	var roots [MerkleDepth + 1]MerkleNode
+	roots[0][0] = 1
+	for i := 0; i < MerkleDepth; i++ {
+		roots[i+1] = s.MerkleHash(i, &roots[i], &roots[i])
+	}
+	return roots
+}
+
+// Authentication path of the leaf at Position, AuthPath[i] is the sibling at height i
+type MerklePath struct {
+	Position uint64
+	AuthPath [MerkleDepth]MerkleNode
+}
+
+// Returns the root of the tree the path authenticates leaf in
+func (s *Sapling) MerklePathRoot(path *MerklePath, leaf *MerkleNode) MerkleNode {
+	node := *leaf
+	for i := 0; i < MerkleDepth; i++ {
+		if (path.Position>>uint(i))&1 == 0 {
+			node = s.MerkleHash(i, &node, &path.AuthPath[i])
+		} else {
+			node = s.MerkleHash(i, &path.AuthPath[i], &node)
+		}
+	}
+	return node
+}
+
+// Incremental append only commitment tree.
+// Only the frontier is stored, the most recent leaf and its left siblings,
+// and the authentication paths of marked leaves are updated while leaves are appended.
+// A CommitmentTree is not safe for concurrent use.
+type CommitmentTree struct {
+	s          *Sapling
+	emptyRoots [MerkleDepth + 1]MerkleNode
+	// number of leaves
+	size uint64
+	// most recent leaf and its left siblings at heights where its position has a set bit
+	leaf   MerkleNode
+	ommers [MerkleDepth]MerkleNode
+	// witnesses of marked leaves by position
+	witnesses map[uint64]*witness
+	// checkpoints from the oldest to the most recent
+	checkpoints    []*treeState
+	maxCheckpoints int
+}
+
+type witness struct {
+	path [MerkleDepth]MerkleNode
+	// bit i is set when path[i] is a complete subtree root
+	filled uint32
+}
+
+type treeState struct {
+	size      uint64
+	leaf      MerkleNode
+	ommers    [MerkleDepth]MerkleNode
+	witnesses map[uint64]witness
+}
+
+// Returns an empty tree that keeps at most maxCheckpoints checkpoints
+func (s *Sapling) NewCommitmentTree(maxCheckpoints int) *CommitmentTree {
+	return &CommitmentTree{
+		s:              s,
+		emptyRoots:     s.emptyRoots(),
+		witnesses:      make(map[uint64]*witness),
+		maxCheckpoints: maxCheckpoints,
+	}
+}
+
+// Returns the number of leaves
+func (t *CommitmentTree) Size() uint64 {
+	return t.size
+}
+
+// Returns the root of an empty subtree of the given height
+func (t *CommitmentTree) EmptyRoot(height int) MerkleNode {
+	return t.emptyRoots[height]
+}
+
+// Appends the note commitment cm_u which must be a canonical encoding of a base field element
+func (t *CommitmentTree) Append(cmu []byte) error {
+	if len(cmu) != 32 {
+		return fmt.Errorf("bad note commitment size")
+	}
+	if leToBig(cmu).Cmp(t.s.curve.Field().Modulus()) >= 0 {
+		return fmt.Errorf("non canonical note commitment")
+	}
+	if t.size == 1<<MerkleDepth {
+		return fmt.Errorf("commitment tree is full")
+	}
+	var leaf MerkleNode
+	copy(leaf[:], cmu)
+	if t.size == 0 {
+		t.leaf, t.size = leaf, 1
+		return nil
+	}
+	// the previous leaf is combined with its left siblings into complete subtrees
+	// until a height where it is a left child, where it becomes the left sibling of the new leaf
+	pos := t.size - 1
+	carry := t.leaf
+	height := 0
+	for ; (pos>>uint(height))&1 == 1; height++ {
+		t.fill(height, pos>>uint(height), &carry)
+		carry = t.s.MerkleHash(height, &t.ommers[height], &carry)
+	}
+	t.fill(height, pos>>uint(height), &carry)
+	t.ommers[height] = carry
+	t.leaf = leaf
+	t.size++
+	return nil
+}
+
+// Records the complete subtree root at height and index in paths of witnesses it is a right sibling in
+func (t *CommitmentTree) fill(height int, index uint64, node *MerkleNode) {
+	if height == MerkleDepth || index&1 == 0 {
+		return
+	}
+	for pos, w := range t.witnesses {
+		if pos>>uint(height) == index^1 {
+			w.path[height] = *node
+			w.filled |= 1 << uint(height)
+		}
+	}
+}
+
+// Marks the most recent leaf to track its authentication path and returns its position
+func (t *CommitmentTree) Mark() (uint64, error) {
+	if t.size == 0 {
+		return 0, fmt.Errorf("commitment tree is empty")
+	}
+	pos := t.size - 1
+	if _, ok := t.witnesses[pos]; ok {
+		return pos, nil
+	}
+	w := new(witness)
+	for i := 0; i < MerkleDepth; i++ {
+		if (pos>>uint(i))&1 == 1 {
+			w.path[i] = t.ommers[i]
+			w.filled |= 1 << uint(i)
+		}
+	}
+	t.witnesses[pos] = w
+	return pos, nil
+}
+
+// Stops tracking the authentication path of the leaf at pos
+func (t *CommitmentTree) Unmark(pos uint64) error {
+	if _, ok := t.witnesses[pos]; !ok {
+		return fmt.Errorf("leaf is not marked")
+	}
+	delete(t.witnesses, pos)
+	return nil
+}
+
+// Returns positions of marked leaves
+func (t *CommitmentTree) Marked() []uint64 {
+	marked := make([]uint64, 0, len(t.witnesses))
+	for pos := range t.witnesses {
+		marked = append(marked, pos)
+	}
+	return marked
+}
+
+// Root of the subtree of the given height that contains the most recent leaf,
+// leaves after it are uncommitted
+func (t *CommitmentTree) frontierRoot(height int) MerkleNode {
+	pos := t.size - 1
+	node := t.leaf
+	for i := 0; i < height; i++ {
+		if (pos>>uint(i))&1 == 1 {
+			node = t.s.MerkleHash(i, &t.ommers[i], &node)
+		} else {
+			node = t.s.MerkleHash(i, &node, &t.emptyRoots[i])
+		}
+	}
+	return node
+}
+
+// Returns the root of the tree
+func (t *CommitmentTree) Root() MerkleNode {
+	if t.size == 0 {
+		return t.emptyRoots[MerkleDepth]
+	}
+	return t.frontierRoot(MerkleDepth)
+}
+
+// Returns the authentication path of the marked leaf at pos against the current root
+func (t *CommitmentTree) Path(pos uint64) (*MerklePath, error) {
+	w, ok := t.witnesses[pos]
+	if !ok {
+		return nil, fmt.Errorf("leaf is not marked")
+	}
+	path := &MerklePath{Position: pos, AuthPath: w.path}
+	last := t.size - 1
+	for i := 0; i < MerkleDepth; i++ {
+		if (w.filled>>uint(i))&1 == 1 {
+			continue
+		}
+		// right sibling which is either partially filled or empty
+		if last>>uint(i) == (pos>>uint(i))^1 {
+			path.AuthPath[i] = t.frontierRoot(i)
+		} else {
+			path.AuthPath[i] = t.emptyRoots[i]
+		}
+	}
+	return path, nil
+}
+
+// Saves the current state of the tree and its witnesses,
+// the oldest checkpoint is dropped if there are more than maxCheckpoints
+func (t *CommitmentTree) Checkpoint() {
+	state := &treeState{
+		size:      t.size,
+		leaf:      t.leaf,
+		ommers:    t.ommers,
+		witnesses: make(map[uint64]witness, len(t.witnesses)),
+	}
+	for pos, w := range t.witnesses {
+		state.witnesses[pos] = *w
+	}
+	t.checkpoints = append(t.checkpoints, state)
+	if len(t.checkpoints) > t.maxCheckpoints {
+		t.checkpoints = t.checkpoints[len(t.checkpoints)-t.maxCheckpoints:]
+	}
+}
+
+// Returns the number of checkpoints that can be rewound to
+func (t *CommitmentTree) Checkpoints() int {
+	return len(t.checkpoints)
+}
+
+// Restores the tree and its witnesses to the most recent checkpoint and removes it
+func (t *CommitmentTree) Rewind() error {
+	if len(t.checkpoints) == 0 {
+		return fmt.Errorf("no checkpoint to rewind to")
+	}
+	state := t.checkpoints[len(t.checkpoints)-1]
+	t.checkpoints = t.checkpoints[:len(t.checkpoints)-1]
+	t.size, t.leaf, t.ommers = state.size, state.leaf, state.ommers
+	t.witnesses = make(map[uint64]*witness, len(state.witnesses))
+	for pos, w := range state.witnesses {
+		w := w
+		t.witnesses[pos] = &w
+	}
+	return nil
+}
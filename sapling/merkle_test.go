@@ -0,0 +1,201 @@
+package sapling
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+)
+
+// canonical leaves of the reference trees, BLAKE2b-256 of the leaf index with the top bits cleared
+func testLeaf(i int) []byte {
+	leaf := blake2b(32, "", []byte{byte(i)})
+	leaf[31] &= 0x1f
+	return leaf
+}
+
+func TestMerkleHash(t *testing.T) {
+	s := NewSapling()
+	var a, b MerkleNode
+	copy(a[:], reverse(fromHex("87a086ae7d2252d58729b30263fb7b66308bf94ef59a76c9c86e7ea016536505")))
+	copy(b[:], reverse(fromHex("a75b84a125b2353da7e8d96ee2a15efe4de23df9601b9d9564ba59de57130406")))
+	want := "61a50a5540b4944da27cbd9b3d6ec39234ba229d2c461f4d719bc136573bf45b"
+	c := s.MerkleHash(25, &a, &b)
+	if out := hex.EncodeToString(c[:]); out != want {
+		t.Errorf("bad merkle hash, have: %s, want: %s", out, want)
+	}
+	c = s.MerkleHash(26, &a, &b)
+	if out := hex.EncodeToString(c[:]); out == want {
+		t.Errorf("merkle hash expected to depend on height")
+	}
+}
+
+func TestCommitmentTree(t *testing.T) {
+	s := NewSapling()
+	tree := s.NewCommitmentTree(0)
+	want := "fbc2f4300c01f0b7820d00e3347c8da4ee614674376cbc45359daa54f9b5493e"
+	root := tree.Root()
+	if out := hex.EncodeToString(root[:]); out != want {
+		t.Errorf("bad empty root, have: %s, want: %s", out, want)
+	}
+	roots := map[uint64]string{
+		1: "fd7bede948f48b72f9dad206fe28da1144842fcff3e90c71299cd210a8d94146",
+		2: "ab7c5dec84e2659d9cfaa6a2d5551591d83dd2cdd2475320b00c71b37c7fee45",
+		3: "5769bb1fd4a6fbebeded1de20873961efcea4b8ec0b5bad60043e7863857d604",
+		5: "d12141c8965ef1ef1f2865b6c9f4e06099dd1fee5e0a2d0c6e5bb309a3312a37",
+	}
+	marked := map[uint64]bool{0: true, 3: true, 4: true, 9: true, 16: true, 17: true, 30: true}
+	var leaves []MerkleNode
+	for i := 0; i < 40; i++ {
+		if err := tree.Append(testLeaf(i)); err != nil {
+			t.Fatal(err)
+		}
+		var leaf MerkleNode
+		copy(leaf[:], testLeaf(i))
+		leaves = append(leaves, leaf)
+		if marked[uint64(i)] {
+			if pos, err := tree.Mark(); err != nil || pos != uint64(i) {
+				t.Fatalf("bad marked position %d, have: %d, err: %v", i, pos, err)
+			}
+		}
+		root := tree.Root()
+		if want, ok := roots[tree.Size()]; ok {
+			if out := hex.EncodeToString(root[:]); out != want {
+				t.Errorf("bad root %d, have: %s, want: %s", tree.Size(), out, want)
+			}
+		}
+		for _, pos := range tree.Marked() {
+			path, err := tree.Path(pos)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if s.MerklePathRoot(path, &leaves[pos]) != root {
+				t.Errorf("bad path %d at size %d", pos, tree.Size())
+			}
+		}
+	}
+	if len(tree.Marked()) != len(marked) {
+		t.Errorf("bad number of marked leaves, have: %d, want: %d", len(tree.Marked()), len(marked))
+	}
+	if err := tree.Unmark(3); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tree.Path(3); err == nil {
+		t.Errorf("expected error for unmarked leaf")
+	}
+	if err := tree.Unmark(3); err == nil {
+		t.Errorf("expected error for unmarked leaf")
+	}
+	if err := tree.Append(make([]byte, 31)); err == nil {
+		t.Errorf("expected error for bad leaf size")
+	}
+	if err := tree.Append(bytes.Repeat([]byte{0xff}, 32)); err == nil {
+		t.Errorf("expected error for non canonical leaf")
+	}
+	if _, err := s.NewCommitmentTree(0).Mark(); err == nil {
+		t.Errorf("expected error for marking in empty tree")
+	}
+}
+
+func TestCommitmentTreeRewind(t *testing.T) {
+	s := NewSapling()
+	tree := s.NewCommitmentTree(2)
+	if err := tree.Rewind(); err == nil {
+		t.Errorf("expected error for rewind without checkpoint")
+	}
+	var leaves []MerkleNode
+	appendLeaf := func(i int) {
+		if err := tree.Append(testLeaf(i)); err != nil {
+			t.Fatal(err)
+		}
+		var leaf MerkleNode
+		copy(leaf[:], testLeaf(i))
+		leaves = append(leaves, leaf)
+	}
+	for i := 0; i < 3; i++ {
+		appendLeaf(i)
+	}
+	if _, err := tree.Mark(); err != nil {
+		t.Fatal(err)
+	}
+	tree.Checkpoint()
+	root := tree.Root()
+	path, _ := tree.Path(2)
+	for i := 3; i < 7; i++ {
+		appendLeaf(i)
+	}
+	if _, err := tree.Mark(); err != nil {
+		t.Fatal(err)
+	}
+	tree.Checkpoint()
+	tree.Checkpoint()
+	if tree.Checkpoints() != 2 {
+		t.Errorf("bad number of checkpoints, have: %d, want: %d", tree.Checkpoints(), 2)
+	}
+	if err := tree.Rewind(); err != nil {
+		t.Fatal(err)
+	}
+	if tree.Size() != 7 || len(tree.Marked()) != 2 {
+		t.Errorf("bad state after rewind to the last checkpoint, size: %d, marked: %d", tree.Size(), len(tree.Marked()))
+	}
+	if err := tree.Rewind(); err != nil {
+		t.Fatal(err)
+	}
+	// the first checkpoint is dropped
+	if tree.Checkpoints() != 0 || tree.Size() != 7 {
+		t.Errorf("bad state after rewind, checkpoints: %d, size: %d", tree.Checkpoints(), tree.Size())
+	}
+
+	tree = s.NewCommitmentTree(1)
+	leaves = nil
+	for i := 0; i < 3; i++ {
+		appendLeaf(i)
+	}
+	if _, err := tree.Mark(); err != nil {
+		t.Fatal(err)
+	}
+	tree.Checkpoint()
+	for i := 3; i < 7; i++ {
+		appendLeaf(i)
+	}
+	if _, err := tree.Mark(); err != nil {
+		t.Fatal(err)
+	}
+	if err := tree.Rewind(); err != nil {
+		t.Fatal(err)
+	}
+	if tree.Size() != 3 {
+		t.Errorf("bad size after rewind, have: %d, want: %d", tree.Size(), 3)
+	}
+	if tree.Root() != root {
+		t.Errorf("bad root after rewind")
+	}
+	if _, err := tree.Path(6); err == nil {
+		t.Errorf("expected leaf marked after checkpoint to be removed")
+	}
+	rewound, err := tree.Path(2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *rewound != *path {
+		t.Errorf("bad path after rewind")
+	}
+	// appending after rewind continues from the checkpoint
+	leaves = leaves[:3]
+	appendLeaf(10)
+	rewound, _ = tree.Path(2)
+	if s.MerklePathRoot(rewound, &leaves[2]) != tree.Root() {
+		t.Errorf("bad path after rewind and append")
+	}
+}
+
+func BenchmarkCommitmentTreeAppend(t *testing.B) {
+	s := NewSapling()
+	tree := s.NewCommitmentTree(0)
+	leaf := testLeaf(0)
+	t.ResetTimer()
+	for i := 0; i < t.N; i++ {
+		if err := tree.Append(leaf); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package sapling
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	"github.com/kilic/go-jubjub"
+)
+
+// 3.2 Notes
+
+type Note struct {
+	D     Diversifier
+	PkD   *jubjub.ExtendedPoint
+	Value uint64
+	Rcm   *jubjub.ScalarFieldElement
+}
+
+// 5.4.8.2 Windowed Pedersen commitments
+// NoteCommit(rcm, g_d, pk_d, v) = WindowedPedersenCommit_rcm([1]^6 || I2LEBSP_64(v) || repr(g_d) || repr(pk_d))
+// WindowedPedersenCommit_r(s) = PedersenHashToPoint("Zcash_PH", s) + [r] FindGroupHash^J("Zcash_PH", "r")
+func (s *Sapling) NoteCommitment(n *Note) (*jubjub.ExtendedPoint, error) {
+	gd, ok := s.DiversifyHash(&n.D)
+	if !ok {
+		return nil, fmt.Errorf("invalid diversifier")
+	}
+	var v [8]byte
+	binary.LittleEndian.PutUint64(v[:], n.Value)
+	m := make([]bool, 6, 6+64+256+256)
+	for i := range m {
+		m[i] = true
+	}
+	m = append(m, bytesToBits(v[:])...)
+	m = append(m, bytesToBits(s.encodePoint(gd))...)
+	m = append(m, bytesToBits(s.encodePoint(n.PkD))...)
+	cm := s.PedersenHashToPoint("Zcash_PH", m)
+	r := s.curve.NewExtendedPoint()
+	s.curve.Mul(r, s.noteCommitRandomnessGenerator, n.Rcm)
+	s.curve.Add(cm, cm, r)
+	return cm, nil
+}
+
+// cm_u = Extract_J(NoteCommit(rcm, g_d, pk_d, v))
+func (s *Sapling) NoteCommitmentU(n *Note) ([]byte, error) {
+	cm, err := s.NoteCommitment(n)
+	if err != nil {
+		return nil, err
+	}
+	return s.ExtractU(cm), nil
+}
+
+// 4.16 Computing rho and Nullifiers
+// rho = MixingPedersenHash(cm, pos)
+// nf = PRF^nfSapling(repr(nk), repr(rho)) = BLAKE2s-256("Zcash_nf", repr(nk) || repr(rho))
+func (s *Sapling) Nullifier(nk, cm *jubjub.ExtendedPoint, pos uint64) [32]byte {
+	rho := s.MixingPedersenHash(cm, pos)
+	var nf [32]byte
+	copy(nf[:], blake2s(32, "Zcash_nf", s.encodePoint(nk), s.encodePoint(rho)))
+	return nf
+}
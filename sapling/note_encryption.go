@@ -0,0 +1,249 @@
+package sapling
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+
+	"github.com/kilic/go-jubjub"
+	"golang.org/x/crypto/chacha20poly1305"
+)
+
+// 4.19 In-band secret distribution (Sapling and Orchard)
+// 5.5 Encodings of Note Plaintexts and Memo Fields
+// ZIP-212 Allow Recipient to Derive Ephemeral Secret from Note Plaintext
+
+const (
+	leadBytePreZIP212 = 0x01
+	leadByteZIP212    = 0x02
+	noteMemoSize      = 512
+	notePlaintextSize = 52 + noteMemoSize
+	encCiphertextSize = notePlaintextSize + chacha20poly1305.Overhead
+	outPlaintextSize  = 64
+	outCiphertextSize = outPlaintextSize + chacha20poly1305.Overhead
+)
+
+type NotePlaintext struct {
+	LeadByte byte
+	D        Diversifier
+	Value    uint64
+	// rcm for lead byte 0x01 and rseed for lead byte 0x02
+	Rseed [32]byte
+	Memo  [noteMemoSize]byte
+}
+
+type EncryptedNote struct {
+	Epk           [32]byte
+	EncCiphertext [encCiphertextSize]byte
+	OutCiphertext [outCiphertextSize]byte
+}
+
+// rcm = LEOS2IP(rseed) for lead byte 0x01, else ToScalar(PRF^expand(rseed, [4]))
+func (s *Sapling) NoteRcm(np *NotePlaintext) (*jubjub.ScalarFieldElement, error) {
+	switch np.LeadByte {
+	case leadBytePreZIP212:
+		return s.scalarField.FromBytes(np.Rseed[:])
+	case leadByteZIP212:
+		return s.toScalar(PRFExpand(np.Rseed[:], 4)), nil
+	}
+	return nil, fmt.Errorf("unknown note plaintext lead byte")
+}
+
+// esk = ToScalar(PRF^expand(rseed, [5])) for lead byte 0x02, else random
+func (s *Sapling) NoteEsk(rand io.Reader, np *NotePlaintext) (*jubjub.ScalarFieldElement, error) {
+	if np.LeadByte == leadByteZIP212 {
+		return s.toScalar(PRFExpand(np.Rseed[:], 5)), nil
+	}
+	return s.RandScalar(rand)
+}
+
+// Note committed by the plaintext and sent to pk_d
+func (s *Sapling) PlaintextNote(np *NotePlaintext, pkd *jubjub.ExtendedPoint) (*Note, error) {
+	rcm, err := s.NoteRcm(np)
+	if err != nil {
+		return nil, err
+	}
+	return &Note{D: np.D, PkD: pkd, Value: np.Value, Rcm: rcm}, nil
+}
+
+func encodeNotePlaintext(np *NotePlaintext) []byte {
+	out := make([]byte, notePlaintextSize)
+	out[0] = np.LeadByte
+	copy(out[1:12], np.D[:])
+	binary.LittleEndian.PutUint64(out[12:20], np.Value)
+	copy(out[20:52], np.Rseed[:])
+	copy(out[52:], np.Memo[:])
+	return out
+}
+
+func decodeNotePlaintext(in []byte) *NotePlaintext {
+	np := &NotePlaintext{LeadByte: in[0]}
+	copy(np.D[:], in[1:12])
+	np.Value = binary.LittleEndian.Uint64(in[12:20])
+	copy(np.Rseed[:], in[20:52])
+	copy(np.Memo[:], in[52:])
+	return np
+}
+
+// 5.4.5.3 Sapling Key Agreement
+// KA^Sapling.Agree(sk, P) = [h_J] [sk] P
+func (s *Sapling) keyAgreement(sk *jubjub.ScalarFieldElement, p *jubjub.ExtendedPoint) *jubjub.ExtendedPoint {
+	r := s.curve.NewExtendedPoint()
+	s.curve.Mul(r, p, sk)
+	s.curve.Mul(r, r, s.scalarField.NewElementFromBig(s.curve.Cofactor()))
+	return r
+}
+
+// 5.4.5.4 Sapling Key Derivation
+// KDF^Sapling(shared, epk) = BLAKE2b-256("Zcash_SaplingKDF", repr(shared) || repr(epk))
+func kdf(shared, epk []byte) []byte {
+	return blake2b(32, "Zcash_SaplingKDF", shared, epk)
+}
+
+// 5.4.2 PRF^ock(ovk, cv, cmu, epk) = BLAKE2b-256("Zcash_Derive_ock", ovk || cv || cmu || epk)
+func prfOck(ovk *[32]byte, cv, cmu, epk []byte) []byte {
+	return blake2b(32, "Zcash_Derive_ock", ovk[:], cv, cmu, epk)
+}
+
+// Sym.Encrypt with a zero nonce, every key is used once
+func symEncrypt(key, plaintext []byte) []byte {
+	aead, _ := chacha20poly1305.New(key)
+	return aead.Seal(nil, make([]byte, chacha20poly1305.NonceSize), plaintext, nil)
+}
+
+func symDecrypt(key, ciphertext []byte) ([]byte, error) {
+	aead, _ := chacha20poly1305.New(key)
+	return aead.Open(nil, make([]byte, chacha20poly1305.NonceSize), ciphertext, nil)
+}
+
+// 4.19.1 Encryption
+// epk = [esk] g_d, C^enc = Sym.Encrypt(KDF(KA.Agree(esk, pk_d), epk), np)
+// C^out = Sym.Encrypt(PRF^ock(ovk, cv, cmu, epk), repr(pk_d) || esk)
+// if ovk is nil C^out is encrypted with a random key and is not recoverable
+func (s *Sapling) EncryptNote(rand io.Reader, esk *jubjub.ScalarFieldElement, np *NotePlaintext, pkd *jubjub.ExtendedPoint, ovk *[32]byte, cv *ValueCommitment, cmu []byte) (*EncryptedNote, error) {
+	gd, ok := s.DiversifyHash(&np.D)
+	if !ok {
+		return nil, fmt.Errorf("invalid diversifier")
+	}
+	epk := s.curve.NewExtendedPoint()
+	s.curve.Mul(epk, gd, esk)
+	en := new(EncryptedNote)
+	copy(en.Epk[:], s.encodePoint(epk))
+	key := kdf(s.encodePoint(s.keyAgreement(esk, pkd)), en.Epk[:])
+	copy(en.EncCiphertext[:], symEncrypt(key, encodeNotePlaintext(np)))
+
+	var ock, op []byte
+	if ovk == nil {
+		ock, op = make([]byte, chacha20poly1305.KeySize), make([]byte, outPlaintextSize)
+		if _, err := io.ReadFull(rand, ock); err != nil {
+			return nil, err
+		}
+		if _, err := io.ReadFull(rand, op); err != nil {
+			return nil, err
+		}
+	} else {
+		ock = prfOck(ovk, s.EncodeValueCommitment(cv), cmu, en.Epk[:])
+		op = append(s.encodePoint(pkd), s.scalarField.ToBytes(esk)...)
+	}
+	copy(en.OutCiphertext[:], symEncrypt(ock, op))
+	return en, nil
+}
+
+// 4.19.2 Decryption using an Incoming Viewing Key
+// Returns the note plaintext and the recipient address if the note is sent to ivk and commits to cmu
+func (s *Sapling) DecryptNote(ivk *IncomingViewingKey, epk, cmu, encCiphertext []byte) (*NotePlaintext, *PaymentAddress, error) {
+	if len(encCiphertext) != encCiphertextSize {
+		return nil, nil, fmt.Errorf("bad note ciphertext size")
+	}
+	epkPoint, err := s.curve.NewExtendedPointFromCompressed(epk)
+	if err != nil {
+		return nil, nil, err
+	}
+	key := kdf(s.encodePoint(s.keyAgreement(ivk.Ivk, epkPoint)), epk)
+	p, err := symDecrypt(key, encCiphertext)
+	if err != nil {
+		return nil, nil, err
+	}
+	np := decodeNotePlaintext(p)
+	addr, err := s.Address(ivk, &np.D)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := s.checkNotePlaintext(np, addr.PkD, epkPoint, nil, cmu); err != nil {
+		return nil, nil, err
+	}
+	return np, addr, nil
+}
+
+// 4.19.3 Decryption using a Full Viewing Key
+// Recovers the note plaintext and the recipient address of an output sent with ovk
+func (s *Sapling) RecoverOutgoingNote(ovk *[32]byte, cv *ValueCommitment, epk, cmu, encCiphertext, outCiphertext []byte) (*NotePlaintext, *PaymentAddress, error) {
+	if len(encCiphertext) != encCiphertextSize || len(outCiphertext) != outCiphertextSize {
+		return nil, nil, fmt.Errorf("bad note ciphertext size")
+	}
+	op, err := symDecrypt(prfOck(ovk, s.EncodeValueCommitment(cv), cmu, epk), outCiphertext)
+	if err != nil {
+		return nil, nil, err
+	}
+	pkd, err := s.curve.NewExtendedPointFromCompressed(op[:32])
+	if err != nil {
+		return nil, nil, err
+	}
+	esk, err := s.scalarField.FromBytes(op[32:])
+	if err != nil {
+		return nil, nil, err
+	}
+	epkPoint, err := s.curve.NewExtendedPointFromCompressed(epk)
+	if err != nil {
+		return nil, nil, err
+	}
+	key := kdf(s.encodePoint(s.keyAgreement(esk, pkd)), epk)
+	p, err := symDecrypt(key, encCiphertext)
+	if err != nil {
+		return nil, nil, err
+	}
+	np := decodeNotePlaintext(p)
+	if err := s.checkNotePlaintext(np, pkd, epkPoint, esk, cmu); err != nil {
+		return nil, nil, err
+	}
+	return np, &PaymentAddress{D: np.D, PkD: pkd}, nil
+}
+
+// Checks the lead byte, that epk = [esk] g_d for the given esk and the one derived from rseed,
+// and that the note commits to cmu
+func (s *Sapling) checkNotePlaintext(np *NotePlaintext, pkd, epk *jubjub.ExtendedPoint, esk *jubjub.ScalarFieldElement, cmu []byte) error {
+	note, err := s.PlaintextNote(np, pkd)
+	if err != nil {
+		return err
+	}
+	esks := []*jubjub.ScalarFieldElement{}
+	if esk != nil {
+		esks = append(esks, esk)
+	}
+	if np.LeadByte == leadByteZIP212 {
+		derived, _ := s.NoteEsk(nil, np)
+		esks = append(esks, derived)
+	}
+	if len(esks) > 0 {
+		gd, ok := s.DiversifyHash(&np.D)
+		if !ok {
+			return fmt.Errorf("invalid diversifier")
+		}
+		q := s.curve.NewExtendedPoint()
+		for _, esk := range esks {
+			s.curve.Mul(q, gd, esk)
+			if !q.Eq(epk) {
+				return fmt.Errorf("ephemeral key mismatch")
+			}
+		}
+	}
+	cm, err := s.NoteCommitmentU(note)
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(cm, cmu) {
+		return fmt.Errorf("note commitment mismatch")
+	}
+	return nil
+}
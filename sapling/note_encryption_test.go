@@ -0,0 +1,348 @@
+package sapling
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"testing"
+)
+
+func TestNoteEncryption(t *testing.T) {
+	s := NewSapling()
+	vectors := []struct {
+		ovk, ivk, d, pkd   string
+		v                  uint64
+		rcm, cv, cmu       string
+		esk, epk           string
+		sharedSecret, kEnc string
+		cEnc, ock, cOut    string
+	}{
+		{
+			"98d16913d99b04177caba44f6e4d224e03b5ac031d7ce45e865138e1b996d63b",
+			"b70b7cd0ed03cbdfd7ada9502ee245b13e569d54a5719d2daa0f5f1451479204",
+			"f19d9b797e39f337445839",
+			"db4cd2b0aac4f7eb8ca131f16567c445a9555126d3c29f14e3d776e841ae7415",
+			100000000,
+			"39176dac39ace4980ecc8d778e89860255ec3615060000000000000000000000",
+			"a9cb0d137232ff8448d0f078b6814c66cb331b0f2d3d8a085bedba815f00a8db",
+			"635572f572a8a1a0b7acbc0afc6d66f14a02efacde7bdf03443ed4c3e551d470",
+			"81c7b2171ff4415250cac01f5982fd8f49619d61ad78f6830b3c606145962a0e",
+			"ded68f05c658fcae5ae218646ff844406f84426784040d0bef2b09cb3848c4dc",
+			"67f9613404d9e9271f1674011b039b3d4381a4d70c586c8a1342283fd5fc3ade",
+			"e5bf8ab2f941e9b9d2c74ace2df6b33c3c3229fa0b9126f9dddb432966100069",
+			"8d6b27e7eff59bfba01d6588badd366ce59b4d5b0ef93bebcbf211417c56ae700ae18244bac2fb6437db01f83dc149e2786ec4ec32c11b054a4c0e2bdbe343788bb9c33ff42fae99323213e0963e6f976d6fffb8c9fcf5219574c7a94c0e72f6093aedafe380621b3ba815d2b97240f677d390f5fc5d45eeff16688e40b9eee8ee1d393b009750cb73df7a47fd07a28141db49bd9ccab1f18d0b6a55ed101ca16f7345bcb0beaf7cd79a3d2bf288f1d88ebb1e4b742199d330c30a9fee1b44c686a1ff5cc33d4627f83d61ce34d6f1344e2b11a5f7172442296075919005434a574ed4e4c98e238edd5367e8f57524b638dd2d5830e83f7f32080d2d51a08ae84e37429c8438faae1540867b12ac2cf6a77da780d92cfa500c195a071ce8ae3f102ce09501ecdac08a7952a08d53f362d37b64948c9915cbfc9f2d3c4e8222d39a348421447fabe4d5f087809a79e849b28dffbc97fbbf647ff34f79ff64e737ebf03d8add44c154325f2bff14c6e9e90b0f9889f325a926a3685641a7a219ece6fb2b4deebf3109d7ee0f039dac427444993485848444ccafda5ea328740666dd75c323ce7b920ee0f3dc3abce6bd09c13c957c5ea8952827116bb5bd0e5c27f820f2cf72a5105d9555be1e1e5e68fffb7133dc3900194e3b731c7d391170ad6d4af13a78a06c25cfbb0d0991d5a883cff51cb6f591c792d99dcc559cde9b7b39c4f54a6bfb29f1f85e135d1733b49d5dd67018e62e8c1ab0c19a25418726ccf2f5e88b97692112924bda2fde7348bad7295241729db4f38711c7ea98c5d4197c66fd23",
+			"6ce61ead7849204293349e832e95ca3ac6422ec4fe21e5d15386558e4d37796d",
+			"0eb2b01be8880fc0469842271418b52bad4019892cde53eecacdb2e45f5f337585f7f6175d888f6e2c4ed13571cd96fd177a01ab101908d7ca4a6d81d916622f5ff077b13f345590e227c10e0895e204",
+		},
+		{
+			"3b946210ce6d1b1692d7392ac84a8bc8f03b72723c7d36721b809a79c9d6e45b",
+			"c518384466b26988b5109067418d192d9d6bd0d9232205d77418c240fc68a406",
+			"aef180f6e34e354b888f81",
+			"a6b13ea336ddb7a67bb09a0e68e9d3cfb39210831ea3a296ba09a922060fd38b",
+			200000000,
+			"478ba0ee6e1a75b600036f26f18b7015ab556beddf8b960238869f89dd804e06",
+			"fc54319a39be49c0480c4df33b8f77ca673a42bfdedfb80ee46b8f70fc0dcd3d",
+			"0c87417577480b6977ba92c55425d62b03b1e5f3c3829cac49bfe515ae722945",
+			"ad4ad62477c2c883c8babfed5d385b51abdcc698e936e78dc22671729155620b",
+			"f06cbaf8cb5c84823847a120104c85ad707228adba876c6d837efd414e1c1db4",
+			"b98a2c3bf0dc56b2bf65f5bd1525055eed22ac0dcc2c11e300c467802b858897",
+			"b2ef45b0f72536a6c022ddcee62ea7027a49362aa2dd3b5436d88975e02ad0ca",
+			"8a3f60252f4df996392e55afee0722f124b1a134e8a1fb1eaa88889e6ad489cf1ba91255ee56fa1a09db7156c3551aed2969a6ff37f2a77a60b3ea4375faff049e85c27221cc2ba989bd18ff9698000af1a7643f8785d65ebb04c85b2475df625b47e3e9c7aca84c131723776bd8c29f9d1f5fd257e58f72b604f9b57b1c2d0531ebbb19cfc27368890d256e9aba308db9d8856f49d4663afe555072ed64c8198e6ad15c0c43bb168549a5be38c5b46dc12f0c2a961ff3cfe32a1c3efe80b15e37e4cebe2a7abe03eb17f4bbad2231cb5255e29cd03cb961332cf5e55e6053cd4065c3785606b2185f18c4a3a22623d259cd20dbe154c4af6b2bdcf3b9c0ff13ce27e39505a9f1b82f6fceeac095384717e8970ee029de964e804abd32d4da93bb8dc2b6bd6044d8dfd79df7207ea03bdf036fa6263f21bc1bfd4a6d9cb5f2d8bb6e74b6dd047ae1aab8c1a723b4787c54e253967fa9440b736183506574350355269b2b66b748e88fe9b8d123e94b5fa5d072b8c39652e9202b16f165460e4b970f63ee7d638f48e49017ea641cd37009d44b7724182544db92bd0c4a7e9d9393d46fcb7bddf96f02cbf47fa0f52804098ecbbb7a13f3a2a5f1638e77f8a82f6c3decb7607f0951c57c7f2776042214f90a3b6e00ed16059dff4555bd471d78afe7aa3dc79141a0872d19c81c351caf54a2fc6de8fd7686c4f2c534efac77515e30f2507ba0b23b1ee37ca908943dfef3809a7e9becf1b9691049f7876a592ee7ed64740f1be7e3066ef76f81470f4354331aa1bc49579699697782bb075cbf82d3a8c0",
+			"6fce27bf1a62f078e7e3cb5d8bf24ca7e4a5821d455f0fa82cd544ecb42091fa",
+			"882458302c0aba55ed8d6718ca26d8c28a127a01e77c2ae5bf15c69673918177f92477a218a7f6cf12178022c9ddc7185c18d0876c3c296583e0bc54793bf1e26a854a41ab617f205271ba6c1429bdf4",
+		},
+		{
+			"8bf4390e28ddc95b8302c381d5810b84ba8e6096e5a76822774fd49f491e8f49",
+			"471c24a3dc8730e75036c0a95f3e2f7dd1be6fb93ad29592203def3041954505",
+			"7599f0bf9b57cd2dc299b6",
+			"66141739514b28f05def8a18eeee5eed4d44c6225c3c65d88dd9907708012f5a",
+			300000000,
+			"147cf2b51b4c7c63cb77b99e8b783e5b5111db0a7ca04d6c014a1d7da83bae0a",
+			"5cc9ea168e79ff0d083af421d32d27fba1c8a638c0c352cf59dcb1ca84c3fb1b",
+			"b3b4e7ab080b9b0fe473cfc5a3105e9a062a4ee49edd7095a671637e0057242b",
+			"99aa10c05788081c0da7d879cd9543ec1892157292402e960b06995a08964c03",
+			"6a92026043fa930eeb2b28fd7bbdc5a70500beb84c671136238e5efdb017d99c",
+			"5078287ff17b1d929b6a99b5e28268a192939573dac4e84d511b5393d72a6d68",
+			"a43caad62530de86df57e9de0347a2d80640530a4ca97b8292a5a5250f1bf240",
+			"4cace52f2da82a34e30de8fb2e256befd92dd30ef78685a508e4410c79336f0af1b264848233592478d22df791ab8d4c7d323cd84d6b2e4dcf66495b46c531a3216766fc8b6f65fe576c44ef88c444fa957fbd87af7a30f52bd3f2338cbb0b7ee6685c51ecefb5fd17d7530bb6145228bb976a56a1c9b2c8d2864c43d3cd640bd7e01f08aac416d2250df7f4b1b9ebd9bd103fd417fdbe57132eabfc52c3798e98c37c1af34d28912c1d1164b5277107c47d6bd5f3c0b30f4efab7ef04158e119d7c40794ab0d4231949e7f80f43d7636456fee2e1272ea1e2ec3e8ff30698b8326471eba940950d5583624dfdabe87d7c52a4d00ef20042381c9e6f03d329bbf42043f2f3b4fd77541632402e0611b244b0c2803cd51250814cffdd7eeb1735beba8ea8a58ebcc323f424fcd5a73dcca2f506fca403199f0cc7b1e97b920ba27235cd39e527382bad3a483b9f1ebbf29177ae94d8fa63beeb456d1278b9d2285944319904dde42adc7062b550b1ff47b70d3c78c24c55069f720fea6023f2194a7291ffb811f68a16d6c115f4d8c685e09a44da8411e1b9b53f39d51846147ddb620898e080b7a65fe8e2e1312b0b8152138a8ba9e086679057179ff09f7b3cbf58bf59e33f83de2c70350ab57c82be9ec95ccc95e2be294ec5383fa3bbd7a759315cc25dea3853e7b5366baae05aca8bc956f1d5bddcbda295a5ca7c2e26fb4e26f7ebdf6244b78a591efaa3a6f48cc4105978c968dd8588795a9a65711793f19804f8814b4a9db0bfa157769aafda2db0eef02b9a81163b7c235697620c72d824e32b",
+			"2411a0f931a8d3516cdb7193c941cf0e49c366ae72c979c49049c94bd3c75cf4",
+			"9dcfab0d2054d2bdf406c31b4178465de6505db3be9b6936f78d2e2937579b582e832861929a75178804b657126add742e06cb84368642db9bf47ac6e4dc1af178198b22d6262345373b0f562ef27bb0",
+		},
+		{
+			"147678e0553b97829347647c5bc7dab4cc2202b54ec29fd31a3de6be0825fc5e",
+			"636aa964bfc23ce4b1fcf7dfc99179ddc406ff55400c9295acfc14f031c72600",
+			"1b81614f1dadea0f8d0a58",
+			"25eb55fccf761fc64e85a588efe6ead7832fb1f0f7a83165895bdff942925f5c",
+			400000000,
+			"34a4b2a9144ff5ea54efee87cf901b5bed5e35d21fbbd788d5bd9d833e112804",
+			"6d6ef8ce979274094f191aef643f3fcbd1ac9d98d607e2bcfef6fd51ba4bb4b9",
+			"51fddd708cd151d3ca4717e3c99eeb8f64f104495f26de057b681063b9c9782d",
+			"bdde1381ec9ff421cafd1e31cc5de25559881f6b21b2175d0dce9408597ea103",
+			"04a10a3ea0e4b1a1d13a67bcb27de634e194b2080162619fbca7662d42b8a55f",
+			"dd88059fd9059013f2b9faa23a6ba149b2ff0e37793a3e8d9270ff7167fd7a8d",
+			"aba4d4a5b51a8bf52e29d6803ab9330cf9c82b1eb1fee6a1a5544a82c7b31682",
+			"9db8b24a056f996d392d4d963ea38976d0f35e85d8aa847a0896164e39d8697ae180c4dcc17061d5f399e0ac4ecb5f02d4d9a3ca5b33518c58b1a073bca7ee6741010305dbb8c7383835b9c780a942785c57a3098a81aef5d7061fdabacf52721530ef32dffc011019ebd36097e84df20363cf1822b1150c2473582b01f8d86799c173f7fef8ca938e4cde7185a19d70ad3861479e7d43810dc5642471033349286baf714f7fdc22b381d9e3adf3bc1049878e186d532d8c9870f60180d65472455d22d25924b992c02f94ea6eaf75b9dc883de7376da6018e55451e23f238e109a6400789f93052579bbb184019f309b3d06d0767a107e4b79a2bfc8425d8b070627f2d55c9a26b22823a21e1caf6fbc2a57dce784b2530345a5f8b0cea3fce3b7ff4f5bb884f68b7d136069233ade4d6bddaf340dee14372332ec376f5935d6279c374911d9540facc75115b20c553329b43ee57a8bb58a3f74606a7f3fa87e46aaf72adae9048b943e4648985adaa990d7820fbb2b12465a1617d01caf41436a4946ea09596239640dc95e586819e6c0069eee07a7242b94afd69ce3543b8877b3194cdb9e707c0838b15434603575046352c1bf4cfc27f4edf6191d8ecf552b8f698702d3a8f6fda58b5cf161fed6e6fdb149a79db0a6b02c327e9629c948f665d13283f65e54be55ac1ae827535ff7ac143cc72d92bc4f46ef4ad88c766ab4bff1e1d115c851e598541105d6ebb367ce0549320a230835311478bdd9f6c538503f362e5f6c27d15b56c4143d45769c2546e53fb4501f9ba5ed455d24986b4dff7cd",
+			"f6bd5d1080fca64600ee9217b09ef1984c9a8b98e06ee5d836ce0e6c89ab56fd",
+			"254f122cfe9498add757cf0b610da8cbaeda053e26cb72306f362308552853ff023c23c26f3ab441b81ea25ce0ae57d1a94983bb45ab8a86da68ef63f15816c143327a1e460c510c631cc69f3960fb5a",
+		},
+		{
+			"1b6e75ece3ace8dba6a5410d9ad4755668e4b39585d635ec1da7c8dcfd5fc4ed",
+			"67fa2bf7c67d4658243c317c0cb41fd32064dfd3709fe0dcb724f14bb01a1d04",
+			"fcfb68a40d4bc6a04b09c4",
+			"8b2a337f03622c24ff381d4c546f6977f90522e92fde44c9d1bb099714b9db2b",
+			500000000,
+			"e557851355747c09ac59013cbde85980964ec1844d9c6967ca0c029c8457bb04",
+			"ce42f9d089ba9d9e62e3f6563362f0fdc7cede8ab359439e214e2652dbf05a0c",
+			"c2b5f357117a4003629e05ca6f56a623a3c48aa5eb797cdd322d4857a0fba44e",
+			"3dc166d56a1d62f5a8d7551db5fd9313e8c7203d996af7d477083756d59af80d",
+			"5b54e5d413a807df36426d5c8c09810ac24595b152cd8941a2343c96303d246b",
+			"4064c2b7c182d1805250d359fba1a5325456b012944d7d929f409c6de5705dc5",
+			"c5fcf813b1bbef20a62ace7a47f37f261fbb2dfad88866b432ff0dfaeec5b2cf",
+			"d7e706317c7895062d89ab5f1052155ac3d2a1e343973e5aab1cce5359c6bc111b9a7bb668b6c7d021b1233577e82baf33005cd034a9754b1e12df036b7bc7829879ca8c6b54378fcd5f182f65160ea7243b7dfcacfb6dacee022634149d8fb2f0ca51a82672a563d536baf1af881a7a8d25c5cf78618953032ef565b0f398e34bee2c3095a7bd0b7d097a3d264d6546d00c8583044378d1489404a31eeca88f8f42ebfb8218d49fded82a9ba6232ccc47945d6f7d6e39e0e83929341acf88db5a2773dc558a9dc11dcda1bab3cb21bf5c295183bf9a93ee025eb460f7d7412042ce5a843a790c3a94da2db7f612032fbf564efcf204afed0ff2ab2bc1b377ca410f127faf9876627fbdb2262ae6562308844800b5cd52743e7f7bcae3c7b27034c5f21d4fefb59bd23bc6ea0c3939871ab434b3a5cb7103851a2478c5f6138f8fd9913fa7af5a4aa20ef95940840bcd174ca3e1065aeaee5f6c7d94342c685f13a81e7b53ad42890ba8103ac834a4eb1f10b0a70e76891dbe18f580472f5bdc3fc9550f156b3121a844d6c77b224b8d04f1fe8ea7b988d878bfc06dac332a106a6ead47f82bd8cb7c25ae9e1d75bb762afee34930f4a998f268d8763cae7b3215205e589c481113b5a4cdb209bece2f094f339f03fb39a16ef1672e008927fd97098e0012becaa00f62c6bfd945a016be8b1866d92b1d8588ae26c63570d7e2a6b2ee6ec2e6b0be2219380e4eea6af09bf585f28538d8b789326e6a3de3bf45068028ac80b192255f273364da88dc1a6f00e0cc32bb475eccbe097a69f6492bdba2adf0",
+			"f98d6e55ff783a1313140fb88b7f3a4db28186378688bec61956232e42b70aba",
+			"3bfc13673c24ac5eaf0bc2446c38a792ae42d96baf0553cee436b634b57389b3621ddbba22e684890a7b645d63c4bc8c26db54628cef4ded980f608f0020bbb5a2f65522a61f89df8218186704011e91",
+		},
+		{
+			"c6bc1f39f0d786314cb20bf9ab228540913555f970696b6d7c77bb332328372a",
+			"ea3f1d80e4307ca73b9f37801f91fba810cc41d279fc29f564235654a2178e03",
+			"eb519882ad1e5cc654cd59",
+			"6b27daccb5a8207f532d10ca238f9786648a11b5966e51a2f7d89e15d29b8fdf",
+			600000000,
+			"68f06104606b0c5449845ff4c65f73e90f45ef5a43c9d74cb2c85cf56c94c002",
+			"3027d7b74764caf72b7387289b128f439fd042c21d81364bc2ae7bd29eab5123",
+			"382c7d688bdf34b94d401c41227952a2b931c57b005c82f2c36315f61c35024e",
+			"4e418c3c543d6bf0153174a04e8544ae7c58092a2e4e5d7d9c672a3a79110903",
+			"e0c29b435daedbc98d465f389b1b60d7dfac0e459b1e628fa0184e92f26479ca",
+			"34dd1613a857752aa90726fff07d429dcb52d2ca277d84eb7a12fa9afc99a735",
+			"0325b3126358573c0990a362b8f27cd00ce0dc4b4d00cc8d8d3ba2ce6ea9c297",
+			"3f926f4c93ff125bd1fa04c91ef59e071433f57c606ee1bc912d54628d140740a1ab8a34516adefbe648007f86f131f4993b99aebd18996348f4ec85341df335422b12618f63aa804b306c6c6b237a0c044f79033d028d13cf1f3d6e38acf390f554a8d4e464948fb5a7f98d161e3a8a157af4c894ca2da4647c5322354f2619fd6ccc3cabef0371ba422f3d6d9216996e49e693871c563ffbf4c6d1d1c4739f7326da4c66976184f0136496712a7eed56ea4ca1d0784c7fa2c556d6a9640b5545d2140ad745f1fcdab6b1f9ee59356bed249338a5c6c1c637ea9b779b8311a5323a15d61f1a0ffc7b2fc9e0be58c5fcbdbe57a2e4d3bf21845b9016541c8cb44a59eca7f2b4183bfbbcda57eb5424e89dc3b06714e20edf7846d68a5f8a184a7f7c5a08fccc7984122e8c636303d03b52b51ec8cd976888976ac59fe4ebda5395538dbea3d0097be5546e1e0ab1ba4cbb47f6203dcab84b129c5299e3e99d65ebcbe40fd05b87369c30db293837dbd04e7a7108ab744b4fb3da1f8a7d2cba6a5f014f0d705ece119ae980e9993da3ddaa3bf1899a7474d60b72ed1e390dfe4a3a071acefb02ccca0ba9398c861bed45216179ee2a0853361c7dea89ac1cd7e2b4efa6ad8215f5f76ac28a731d2779c1ffebe9ab6f513d9b5ee008135ff60bb86f8e139787c6c3468d31298f25917648f072a11c0b8af40f92a8b5042cd4af4f5a2a552731546190448df1078637f42e97545a86643aa41037c534bc3e2e44a8853410a06e9125318a965655f33fed8eba356293d7ccfb97a23320bc353970aaa118e743",
+			"959a280217b9ef54ab443b8d0fea5a117586ae8add64997d02ecb8b5cbac1487",
+			"659def250834846f85eb9e395befe15e1d4d2ab4362d1aa7de84243f7445d5d28f4792924d60c760533cef051047e54d521e2b072d1330b2685eb870106c661f1f07b76fdbb514aa9b94ad4191bc0d2d",
+		},
+		{
+			"f62c05e848a873ef885e12b08c5e7ca2f32424bacc754cb69750444d355f5106",
+			"b5c5894943956933c0e5c12d311fc12cba58354b5c389edc03da55084f74c205",
+			"bebb0fb46b8aaff89040f6",
+			"d11da01f0b43bdd5288d32385b8771d223493c69802544043f77cf1d71c1cb8c",
+			700000000,
+			"49f90b47fd52fee7c1c81f0dcb5b74c3fb9b3e03976f8b7524eabad008892107",
+			"770894c7a5458b167d8518a547bc62b46ba189807eb97c08284e1b92b6da352a",
+			"0dd42d63ff38ee4c46651e4d1dd5227dc597339f7d704c518ef402f8cd6f3744",
+			"6da945d30381c2eed2b81d27086d2248e7c449fe509b38e276791189eabc4602",
+			"a52f0b5ae4a94fa88aa7cb7e5f0f343ca2fa66b39441ba662820e46a9bbba3b5",
+			"81c7c5d5ff63e9e61fe35a4b396ea7f19e48076f22090ae729a411792f08584a",
+			"b4f9a7ff9c60806ec7f55ceebec2ba5476198e291df7578c2bef87e64a716ae7",
+			"2077df432524614c076e7793024191aac9e493f5c8a98745ae65310cfcb575564a93f1272bce900777b850497e84540cb192038565882fa4f371213eb5090041ffd9247bee2bb153212283b27e36e284603c0bc40c465fc6ab8f88985ef50e2ab0eb66a634309bb902c6cdd6a555b8c371489f57c7ea3b5437f287c74e35e034cc6808e2c9f2c973fac96e849d31de76f80663a582b23afc36455ec46e238cb284daf1114a6e5bd0289aefb7469431b8b86089b9d36ffd6745bd867baa6b58fb30afa097ab9e57388f4fdfc0fd483dc67f02bc07990e1a397b112d5dbcf22f9b64f5f543102463e30f4681728539c0c5c5e00a2535aef768e3af7d47a08ddb99ea2ed00c52bf4b5eb3140585b0f90ecf7d215b4cc18af9aec8170c6db6c66998b8da0f0917f1380c87a4181b86c6cdfe6f2db22141e7984b1aacf7cec5e7d076aac5479ed71440b2d460185ba3dbea03c8fccac09aecd33a3fdda9a134ea42a1a978c4051799e6cc696f8a49400aead6652f93a258220c6338b9e73b10a01cd2ec3972861c7b62695adaa5414a787450e7a5f821e4f245dd972c0892e86fa126ba595c1225738e2f8be36f11dcc52ced4f7875df5bbbd83aec8d4313072d7ec947af86b56b65fcb1bd32f0db0cb37deaa6cde0dfe4bdb809161eda034a949a3a039af9bbe09eafb35b7cd8b5328342c393221a4f134b15a4163c053b32eba85e593606da67a11ce174b77bbefd50ef1025e94a06c5e0988db7f9da540aa3b1c03309b4b14001e2c45aa999650b01aa3bef5fb2d3380cbf33c55d4570259f1e3ed7e00ca9",
+			"54ceb11bb0e8f8548610d11ff1ab1492d18d5c853c8f2f0cd5d19d6d34cf7c2d",
+			"e772e01d6109b6f985b1772ed1550a947b35a84b3e71123331a3d61f1bf5964e97425442e5c8ef2b9d84ab3dcbab9c96fe6a89ce1d5e8a9b83b5090bb07c50450bbbfc8a7464a77c33971633b21368f0",
+		},
+		{
+			"e9e0dc1ed311daed64bd74da5d94fe88a6ea414b7312de3d2a78f64632bbe373",
+			"8716c82880e13683e1bb059dd06c80c90134a96d5afca8aac2bbf68bb05f8402",
+			"ad6e2e185a3100e3a6a8b3",
+			"32cb2806b882f1368b0d4a898f72c4c8f728132cc12456946e7f4cb0fb058da9",
+			800000000,
+			"5165aff22dd4ed56b4d81d1f171cc3d6432fed1bebf20a7beab12db142f94a0c",
+			"2954cc7f9f9dfeb14f02eebff3f848d5d0e3d2e01febc91641f4126c6034330c",
+			"0990cdb9a52e5cd1ba54d9204c26691cb036b130122126eb14129cdf0fc5183c",
+			"ab2aff0332d543fd1d8023185b8ecb5f22a29c32ef741633316eee514fc22309",
+			"d004997c79d007a53bf2fd2f6a66c0afd9f879b55fecdc158a901232b7884809",
+			"a8dea9be94dccac81575b44f4be853e8c0f7e6ba7f0bf8f2b3a1b89c6ac89239",
+			"141b550ad3c2e7dfdcd42d4aba31399742a929bb23100a7c51ed32f9cb4596c6",
+			"6d3eff728a288e3559d89606a450ce14864df90323cb2f41fba268843cec777548bcc425f5ed1e6e8c75e2dae356168456391b87b5c6cd55503f12c34f94b0d824a77ae6213ff43f12a34f2c668ea16bd1f04a91d39a7b60197c7b58629036a88fa70a8d5bf83ed4db4063b1eace1095f90662ce9f6ac02673f7b9a36ebc52f4984bd71153b3e2edca803d869026ee2ff0228afa7b61d0d38c9bccb3008b32c6a059842ee8a07ba12c6308436b648985353d7dd58b2092b5ac2ed7e72065ecada650aee6cd00fd34d58c2b58d41a48aac7bf4b45c96c53a10b04db73cc83271ba67117d642e4d819c302d7185eccbfa5405b80c5b3e4b2c552432860808178cb8fce405b73fef2b346c41bb2b2fad71a80313be3cf01ecfd888f2572edcf57e4d71e47cf8d52dba4c6440d0d4a9b193f57748d20f89ab5d6da1614362a5fb85f6ab2be35c72fd6287ae55cd277791944df24a3764671ddd4060a9b9cab014abe1435093164a69f61bf29248c359cb690ab25e993ce3972d6ee36785ef061872050f526f7db7ff198fbacff298581b73306efc02bb9d4ab32df264f14a80e7f0c76e5f14da29ab1ea04a3e3f5ba5e35055dbad276e1201cce0aec1482cbec1d3fa4a13d3e16511b0dee3558c5aeef27e3e61b9151e55a5ae157030ce597f82182893ee4d6bd4fb08729bbc301419ce0664145ba7ab8cbc06548e1f7fdf53d0605a77be6e40c540090f98c25b125be7499f176bb8501493353cf905f72250062d6cf0188148246ee94ef9b21adb7ae1ae73bb6e68fa91d7fb49828d657d8195f6e95082fad",
+			"dab426269e8d330955237a9fed8683a9277c6182a808cc53a1beddd20368b10a",
+			"af4d97fb7228f01f6d9e2f79a1a1ba45a23d609059784ea9350f1eb092b054a3268cc026d3d737ef35adc286d195eaa414493ed2a51f2f61099a3451f9555bab1a5ef3e3fbbe8ec6416bd33d50dff98f",
+		},
+		{
+			"147dd11d77eba1b1636fd6190c62b9a5d0481bee7e917fab02e21858063ab504",
+			"99c9b4b84f4b4e350f787d1cf7051d50ecc34b1a5b20d2d2139b4af1f160e001",
+			"21c90e1c658b3efe86af58",
+			"9e64174b4ab981405c323b5e12475945a46d4fedf8060828041cd20e62fd2cef",
+			900000000,
+			"8c3e56449dc86354d33b025ef2793460bcb169f3324e4a6b64baa60832315704",
+			"4a85eb3f253f3baaf6b55a994951b2ca8248cbd679f7a577e33bcd6646b21351",
+			"5690cd51a45ce89a51acbe016060f0dfee0d2fc9b897585f974a402e537fe218",
+			"a53d19f5694595d5ae630227673c80249ce124419f46df4e7b3fc1046128cd0b",
+			"4dfc8a70b210dfd44837aa52d63bd5d81a5e40d8b4c17a2dca25a5f75fe5202e",
+			"1ff75f5e7a514b3cf5b33ca31a671fc50c268cf1a316b21b98674baa450085cf",
+			"3c52d9c83207ee14f5620d162182a6b9cabefdba9e7a74f5ba2f81b871401f08",
+			"84d36109bdd21c678e8447f889e560ef6d07a827aaab789b46c3f9eb322eea214c20f7e9fa7f7aa5e044a4ed4cb15da9c56c32f37e4cbe7d1ed1f685a8748dbf781290f97ac14140aa8b50932a3f66c208226f8d8ec0deb7bb583572c9e970bcd0c6446726aa5b6a5f81cf18c67a992d6c860386abbb5b90be5864344fc8bf3ebb7541aa9b9e1e3f9625acce7f4bf15839a0817068e9151b637fa2a2ca09b9be285fea7e0a03317c298ad7fffe40c5f0f6e9fb44e8f06e192f1ac2108f3f11f7763cf21e96624d52f3e72aaf157f3bc7c5d18f1eba3d827f719c279fd966c27d94d74723c5311b8665bd29b3a100bb2111aa4216f0665b169ec0941768a9574ae50c2bc7900553f5c450ee9882af4455d1d8ce351849d78dbbe61ed1db7a2fd65775d5506dfd02a94d9d4285a23a3cab8aa3321422a4aaa549274a25f7f12ff7a5195e5155739f318c30c0248c3a219a7ade7298380a595c5c885b420669cd6deb2e5c804978cb42d20602745733607cef4e26a5c97cca1cc52b7fdc1069017018076cac62e5c4dbf9074872050a422219513bca27a835f4824f47ba337deb7440f3f2cace9e331670dd98e328ab0a16ac4ab66276d1e1018b2cf179436266a408da8ddafc44b2276b116852d4ccb35289b4213009125d2d87845d6eb78e5503153d92fbd493d19ef01f370026baf172307b3fe2c45696fbceda3b6eab05e2b0685c72790498233abbbd6e05b0f44a7298ae0a25af08d79574614cf2d83ea79c2b7953f86cf5d04927f09c0d7df812f1cf18a453a04970af0d729ce7d9c8d6a24d7eed3d",
+			"c9721e9e65a261851007cd81467ba5f35805ba785a2c92a9aa6232b0551cf3f4",
+			"bc16afa8aab2380626018ce2755867558f9d59857393a1f348b21cb50f53eabae7f6e47b45241f6b7b3d68945dd40cadc57a9ade6af969ae074ff289bcb6610ae38c8210a5cbd747b831151c56ef02c9",
+		},
+		{
+			"573467a7b30ead6ccc504744ca9e1a281a0d1a08738b06a0684feacd1e9d126d",
+			"db95ea8bd9f93d41b5ab2bebc91a38edd527083e2a6ef9f3c29702d5ff89ed00",
+			"233c4ab886a55e3ba374c0",
+			"b68e9ee0c0678d7b3036931c831a25255f7ee487385a30316e15f6482b874fda",
+			1000000000,
+			"6ebbed743619a256f9ad2e85880cfaa9098a5fdb1629990d9a7d3bb93fc90003",
+			"2a547d978c7c90a8d0a5474e29dbfff34bae81e6408ec1fe2d56a25241a8e329",
+			"f4ba4ef040f80d00080d29a6b399dc4032403361e0591ed61499bc068e41ed38",
+			"29958980694f7f67080997c2664702890cd1b503dda42d33a899ce991fe0f800",
+			"ea6b3c985f33b2a22d0dbf7cd93019fd9e57316c85b7674954629c77dfaec066",
+			"c0645825dfc44d548283f6e888253bf5c32a90debb928e896786ac0b16d5f656",
+			"33d2da8d80e0ced8b4beec943a0fc9c960ad7ccc597743744c18c9c2a562f63a",
+			"149a52f8f5342b44848891f885d3cd099abe805aa5091fe1710eb73502de387df3f9646722e8b85c377c822a7103347c8101e9ae8c3182ca36dafd758d96ceba48327a098286a4e8321d1e74fe3d6159c029483de9eef3b24d85e4d516b8704f8e7d93e74442ed007ad79a6152f2b6642fbee60435e19209d811c66c17b7df3dfd769fb5c7d006b36742bbe726929e879b116d3613571aa63ac2ccca43f8900b893e64dd0b8ff91ec5114082e6d00cf93a7cfa7518bb7fb64a7f346420b64478d71869e91d4797901fa86e70b2201afe4bd3ea5503816fac687d81252f65616e7fb26846521e39ff94be73b8aca804c65cf94e3256bd3c69ad318e6b285519487793ee29885140f0bc00845f67415f670f04ca818c5f3249d3fb70bfea10c625eb8cf2cab3f583622a21a38b8fe51a5ff2919ef4c1bd9830a9f2486abd885dd943b94edc8f88c8b78a5eb031f34b7d931c8753afd9768d0fa8d26e88c9567ad58923e7b0afbdaadf477bd1d23fc40a42c29b4d5fe1087645ddfdeba0c7d56715cd57f0d1741a3d9cb38d88d647b1c5b24addbad1acfa3a8da37a74260555ec0dea88ed2c7f46dd87b3f279a96a0e7854ec4a79ceadc74a680fc82d75aec7f2d13dfb62235057e4f7dc5b07c6baba82b32fe90b5c6e9dc6b2fb33beac880d3a60ba0848fac6619da8ca33a63294eb63d0f24cbb1e031782880ffa18356c98762ccdd3afab81f19abf3bdd2bc43cb1f2155caf6498894e068ba749c976ec23f211622614607856d87b741624f7f83495d7de4d6de208e13574c82a1b8b1cfe87e918e7b396",
+			"db5ba6b9dbb11f7ce812eb1bf3298cca5571eecc69b722a0a3b86750729299a0",
+			"e27a464d6f44cc44f617e23c9fb1b71fffd46aebf03677cf7dd24d711ba0c6ca3853097b247ab74c15bb938ed602fbcd30f4a65956430f47a0fbcbe8e08aada38630785a805753ba33b334cd2a4bfc3d",
+		},
+	}
+	for i, v := range vectors {
+		np := &NotePlaintext{LeadByte: leadBytePreZIP212, Value: v.v}
+		copy(np.D[:], fromHex(v.d))
+		copy(np.Rseed[:], fromHex(v.rcm))
+		np.Memo[0] = 0xf6
+		var ovk [32]byte
+		copy(ovk[:], fromHex(v.ovk))
+		ivk := &IncomingViewingKey{Ivk: s.toScalar(fromHex(v.ivk))}
+		pkd, err := s.curve.NewExtendedPointFromCompressed(fromHex(v.pkd))
+		if err != nil {
+			t.Fatal(err)
+		}
+		cv, err := s.DecodeValueCommitment(fromHex(v.cv))
+		if err != nil {
+			t.Fatal(err)
+		}
+		cmu, esk := fromHex(v.cmu), s.toScalar(fromHex(v.esk))
+
+		shared := s.keyAgreement(esk, pkd)
+		if out := hex.EncodeToString(s.encodePoint(shared)); out != v.sharedSecret {
+			t.Errorf("bad shared secret %d, have: %s, want: %s", i, out, v.sharedSecret)
+		}
+		if out := hex.EncodeToString(kdf(s.encodePoint(shared), fromHex(v.epk))); out != v.kEnc {
+			t.Errorf("bad encryption key %d, have: %s, want: %s", i, out, v.kEnc)
+		}
+		if out := hex.EncodeToString(prfOck(&ovk, fromHex(v.cv), cmu, fromHex(v.epk))); out != v.ock {
+			t.Errorf("bad outgoing cipher key %d, have: %s, want: %s", i, out, v.ock)
+		}
+
+		en, err := s.EncryptNote(nil, esk, np, pkd, &ovk, cv, cmu)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if out := hex.EncodeToString(en.Epk[:]); out != v.epk {
+			t.Errorf("bad ephemeral key %d, have: %s, want: %s", i, out, v.epk)
+		}
+		if out := hex.EncodeToString(en.EncCiphertext[:]); out != v.cEnc {
+			t.Errorf("bad note ciphertext %d, have: %s, want: %s", i, out, v.cEnc)
+		}
+		if out := hex.EncodeToString(en.OutCiphertext[:]); out != v.cOut {
+			t.Errorf("bad outgoing ciphertext %d, have: %s, want: %s", i, out, v.cOut)
+		}
+
+		dec, addr, err := s.DecryptNote(ivk, fromHex(v.epk), cmu, fromHex(v.cEnc))
+		if err != nil {
+			t.Fatalf("note %d is not decrypted: %v", i, err)
+		}
+		if *dec != *np || !addr.PkD.Eq(pkd) {
+			t.Errorf("bad decrypted note %d", i)
+		}
+		dec, addr, err = s.RecoverOutgoingNote(&ovk, cv, fromHex(v.epk), cmu, fromHex(v.cEnc), fromHex(v.cOut))
+		if err != nil {
+			t.Fatalf("note %d is not recovered: %v", i, err)
+		}
+		if *dec != *np || !addr.PkD.Eq(pkd) {
+			t.Errorf("bad recovered note %d", i)
+		}
+	}
+}
+
+func TestNoteEncryptionZIP212(t *testing.T) {
+	s := NewSapling()
+	var sk SpendingKey
+	fvk := s.FullViewingKey(s.ExpandSpendingKey(&sk))
+	ivk := s.IncomingViewingKey(fvk)
+	addr, err := s.DefaultAddress(&sk)
+	if err != nil {
+		t.Fatal(err)
+	}
+	np := &NotePlaintext{LeadByte: leadByteZIP212, D: addr.D, Value: 1000}
+	if _, err := rand.Read(np.Rseed[:]); err != nil {
+		t.Fatal(err)
+	}
+	copy(np.Memo[:], "memo")
+	note, err := s.PlaintextNote(np, addr.PkD)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cmu, err := s.NoteCommitmentU(note)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rcv, _ := s.RandScalar(rand.Reader)
+	cv := s.ValueCommit(int64(np.Value), rcv)
+	esk, err := s.NoteEsk(nil, np)
+	if err != nil {
+		t.Fatal(err)
+	}
+	en, err := s.EncryptNote(rand.Reader, esk, np, addr.PkD, &fvk.Ovk, cv, cmu)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dec, to, err := s.DecryptNote(ivk, en.Epk[:], cmu, en.EncCiphertext[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *dec != *np || to.D != addr.D || !to.PkD.Eq(addr.PkD) {
+		t.Errorf("bad decrypted note")
+	}
+	if _, _, err := s.RecoverOutgoingNote(&fvk.Ovk, cv, en.Epk[:], cmu, en.EncCiphertext[:], en.OutCiphertext[:]); err != nil {
+		t.Errorf("note is not recovered: %v", err)
+	}
+
+	// other viewing keys and commitments are rejected
+	sk[0] = 1
+	other := s.IncomingViewingKey(s.FullViewingKey(s.ExpandSpendingKey(&sk)))
+	if _, _, err := s.DecryptNote(other, en.Epk[:], cmu, en.EncCiphertext[:]); err == nil {
+		t.Errorf("note is decrypted with another viewing key")
+	}
+	badCmu := append([]byte{}, cmu...)
+	badCmu[0] ^= 1
+	if _, _, err := s.DecryptNote(ivk, en.Epk[:], badCmu, en.EncCiphertext[:]); err == nil {
+		t.Errorf("note is decrypted under another commitment")
+	}
+
+	// esk must be derived from rseed
+	esk, _ = s.RandScalar(rand.Reader)
+	en, err = s.EncryptNote(rand.Reader, esk, np, addr.PkD, &fvk.Ovk, cv, cmu)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := s.DecryptNote(ivk, en.Epk[:], cmu, en.EncCiphertext[:]); err == nil {
+		t.Errorf("note with unrelated ephemeral key is decrypted")
+	}
+
+	// without ovk the sender can not recover the note
+	esk, _ = s.NoteEsk(nil, np)
+	en, err = s.EncryptNote(rand.Reader, esk, np, addr.PkD, nil, cv, cmu)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := s.DecryptNote(ivk, en.Epk[:], cmu, en.EncCiphertext[:]); err != nil {
+		t.Errorf("note is not decrypted: %v", err)
+	}
+	if _, _, err := s.RecoverOutgoingNote(&fvk.Ovk, cv, en.Epk[:], cmu, en.EncCiphertext[:], en.OutCiphertext[:]); err == nil {
+		t.Errorf("note is recovered without ovk")
+	}
+	if !bytes.Equal(encodeNotePlaintext(decodeNotePlaintext(encodeNotePlaintext(np))), encodeNotePlaintext(np)) {
+		t.Errorf("bad note plaintext encoding")
+	}
+}
+
+func BenchmarkDecryptNote(t *testing.B) {
+	s := NewSapling()
+	var sk SpendingKey
+	fvk := s.FullViewingKey(s.ExpandSpendingKey(&sk))
+	ivk := s.IncomingViewingKey(fvk)
+	addr, _ := s.DefaultAddress(&sk)
+	np := &NotePlaintext{LeadByte: leadByteZIP212, D: addr.D, Value: 1000}
+	note, _ := s.PlaintextNote(np, addr.PkD)
+	cmu, _ := s.NoteCommitmentU(note)
+	esk, _ := s.NoteEsk(nil, np)
+	en, _ := s.EncryptNote(rand.Reader, esk, np, addr.PkD, nil, nil, cmu)
+	t.ResetTimer()
+	for i := 0; i < t.N; i++ {
+		s.DecryptNote(ivk, en.Epk[:], cmu, en.EncCiphertext[:])
+	}
+}
@@ -0,0 +1,128 @@
+package sapling
+
+import (
+	"encoding/hex"
+	"testing"
+)
+
+func testNote(t *testing.T, s *Sapling, d, pkd string, v uint64, rcm string) *Note {
+	n := &Note{Value: v, Rcm: s.toScalar(fromHex(rcm))}
+	copy(n.D[:], fromHex(d))
+	var err error
+	if n.PkD, err = s.curve.NewExtendedPointFromCompressed(fromHex(pkd)); err != nil {
+		t.Fatal(err)
+	}
+	return n
+}
+
+func TestNoteCommitment(t *testing.T) {
+	s := NewSapling()
+	// note encryption vectors
+	vectors := []struct {
+		d, pkd string
+		v      uint64
+		rcm    string
+		cmu    string
+	}{
+		{"f19d9b797e39f337445839", "db4cd2b0aac4f7eb8ca131f16567c445a9555126d3c29f14e3d776e841ae7415", 100000000, "39176dac39ace4980ecc8d778e89860255ec3615060000000000000000000000", "635572f572a8a1a0b7acbc0afc6d66f14a02efacde7bdf03443ed4c3e551d470"},
+		{"aef180f6e34e354b888f81", "a6b13ea336ddb7a67bb09a0e68e9d3cfb39210831ea3a296ba09a922060fd38b", 200000000, "478ba0ee6e1a75b600036f26f18b7015ab556beddf8b960238869f89dd804e06", "0c87417577480b6977ba92c55425d62b03b1e5f3c3829cac49bfe515ae722945"},
+		{"7599f0bf9b57cd2dc299b6", "66141739514b28f05def8a18eeee5eed4d44c6225c3c65d88dd9907708012f5a", 300000000, "147cf2b51b4c7c63cb77b99e8b783e5b5111db0a7ca04d6c014a1d7da83bae0a", "b3b4e7ab080b9b0fe473cfc5a3105e9a062a4ee49edd7095a671637e0057242b"},
+		{"1b81614f1dadea0f8d0a58", "25eb55fccf761fc64e85a588efe6ead7832fb1f0f7a83165895bdff942925f5c", 400000000, "34a4b2a9144ff5ea54efee87cf901b5bed5e35d21fbbd788d5bd9d833e112804", "51fddd708cd151d3ca4717e3c99eeb8f64f104495f26de057b681063b9c9782d"},
+		{"fcfb68a40d4bc6a04b09c4", "8b2a337f03622c24ff381d4c546f6977f90522e92fde44c9d1bb099714b9db2b", 500000000, "e557851355747c09ac59013cbde85980964ec1844d9c6967ca0c029c8457bb04", "c2b5f357117a4003629e05ca6f56a623a3c48aa5eb797cdd322d4857a0fba44e"},
+		{"eb519882ad1e5cc654cd59", "6b27daccb5a8207f532d10ca238f9786648a11b5966e51a2f7d89e15d29b8fdf", 600000000, "68f06104606b0c5449845ff4c65f73e90f45ef5a43c9d74cb2c85cf56c94c002", "382c7d688bdf34b94d401c41227952a2b931c57b005c82f2c36315f61c35024e"},
+		{"bebb0fb46b8aaff89040f6", "d11da01f0b43bdd5288d32385b8771d223493c69802544043f77cf1d71c1cb8c", 700000000, "49f90b47fd52fee7c1c81f0dcb5b74c3fb9b3e03976f8b7524eabad008892107", "0dd42d63ff38ee4c46651e4d1dd5227dc597339f7d704c518ef402f8cd6f3744"},
+		{"ad6e2e185a3100e3a6a8b3", "32cb2806b882f1368b0d4a898f72c4c8f728132cc12456946e7f4cb0fb058da9", 800000000, "5165aff22dd4ed56b4d81d1f171cc3d6432fed1bebf20a7beab12db142f94a0c", "0990cdb9a52e5cd1ba54d9204c26691cb036b130122126eb14129cdf0fc5183c"},
+		{"21c90e1c658b3efe86af58", "9e64174b4ab981405c323b5e12475945a46d4fedf8060828041cd20e62fd2cef", 900000000, "8c3e56449dc86354d33b025ef2793460bcb169f3324e4a6b64baa60832315704", "5690cd51a45ce89a51acbe016060f0dfee0d2fc9b897585f974a402e537fe218"},
+		{"233c4ab886a55e3ba374c0", "b68e9ee0c0678d7b3036931c831a25255f7ee487385a30316e15f6482b874fda", 1000000000, "6ebbed743619a256f9ad2e85880cfaa9098a5fdb1629990d9a7d3bb93fc90003", "f4ba4ef040f80d00080d29a6b399dc4032403361e0591ed61499bc068e41ed38"},
+	}
+	for i, v := range vectors {
+		n := testNote(t, s, v.d, v.pkd, v.v, v.rcm)
+		cmu, err := s.NoteCommitmentU(n)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if out := hex.EncodeToString(cmu); out != v.cmu {
+			t.Errorf("bad note commitment %d, have: %s, want: %s", i, out, v.cmu)
+		}
+	}
+	n := testNote(t, s, vectors[0].d, vectors[0].pkd, 0, vectors[0].rcm)
+	n.D = Diversifier{1}
+	if _, err := s.NoteCommitment(n); err == nil {
+		t.Errorf("expected error for invalid diversifier")
+	}
+}
+
+func TestNullifier(t *testing.T) {
+	s := NewSapling()
+	// key component vectors
+	vectors := []struct {
+		sk, d, pkd string
+		v          uint64
+		rcm, cmu   string
+		pos        uint64
+		nf         string
+	}{
+		{"0000000000000000000000000000000000000000000000000000000000000000", "f19d9b797e39f337445839", "db4cd2b0aac4f7eb8ca131f16567c445a9555126d3c29f14e3d776e841ae7415", 0, "39176dac39ace4980ecc8d778e89860255ec3615060000000000000000000000", "cb3cf9153270d57eb914c6c2bcc01850c9fed44fce0806278f083ef2dd076439", 0, "44fad6564ffdec9fa19c43a28f861d5ebf602346007de76267d9752747ab4063"},
+		{"0101010101010101010101010101010101010101010101010101010101010101", "aef180f6e34e354b888f81", "a6b13ea336ddb7a67bb09a0e68e9d3cfb39210831ea3a296ba09a922060fd38b", 12227227834928555328, "478ba0ee6e1a75b600036f26f18b7015ab556beddf8b960238869f89dd804e06", "b57893500bfb85df2e8b01ac452f89e10e266bcfa31c31b29a53ae72cad46950", 763714296, "679eb0c3a757e2ae83cdb42a1ab259d78388315419adc71d2e3763174c2e9d93"},
+		{"0202020202020202020202020202020202020202020202020202020202020202", "7599f0bf9b57cd2dc299b6", "66141739514b28f05def8a18eeee5eed4d44c6225c3c65d88dd9907708012f5a", 6007711596147559040, "147cf2b51b4c7c63cb77b99e8b783e5b5111db0a7ca04d6c014a1d7da83bae0a", "db85a70a98437f73167fc332d5b7b7408296661770b101b0aa87839f4e55f151", 1527428592, "e98f6a8f34ff498059b3c731b91f451108c4954d919484361cf9b48f59ae1d14"},
+		{"0303030303030303030303030303030303030303030303030303030303030303", "1b81614f1dadea0f8d0a58", "25eb55fccf761fc64e85a588efe6ead7832fb1f0f7a83165895bdff942925f5c", 18234939431076114368, "34a4b2a9144ff5ea54efee87cf901b5bed5e35d21fbbd788d5bd9d833e112804", "e08ce482b3a8fb3b35ccdbe34337bd105d8839212e0d1644b9d55caa60d19b6c", 2291142888, "5547aa12ff80a6b3304e3b058656472abd2c8183b59d0737b93cee758bec47a1"},
+		{"0404040404040404040404040404040404040404040404040404040404040404", "fcfb68a40d4bc6a04b09c4", "8b2a337f03622c24ff381d4c546f6977f90522e92fde44c9d1bb099714b9db2b", 12015423192295118080, "e557851355747c09ac59013cbde85980964ec1844d9c6967ca0c029c8457bb04", "bdc854bf3e7b00821f3b8b85238ccf1e6715bfe70b632d044b26fb2bc71b7f36", 3054857184, "8a9abda3d4ef85caf22bfaf2c48f62382a73a1624eb8eb2bd00d270301bf3d13"},
+		{"0505050505050505050505050505050505050505050505050505050505050505", "eb519882ad1e5cc654cd59", "6b27daccb5a8207f532d10ca238f9786648a11b5966e51a2f7d89e15d29b8fdf", 5795906953514121792, "68f06104606b0c5449845ff4c65f73e90f45ef5a43c9d74cb2c85cf56c94c002", "e8267d30ac11c100bc7a0fdf91f71d74c5bcf2e1ef95669044730169de1a5b4c", 3818571480, "332ad99eb9e977eb627a122dbfb2f25fe588e597753ec5580ff2be20b6c9a7e1"},
+		{"0606060606060606060606060606060606060606060606060606060606060606", "bebb0fb46b8aaff89040f6", "d11da01f0b43bdd5288d32385b8771d223493c69802544043f77cf1d71c1cb8c", 18023134788442677120, "49f90b47fd52fee7c1c81f0dcb5b74c3fb9b3e03976f8b7524eabad008892107", "572ba20525b0ac4d6dc01ac2ea1090b6e0f2f4bf4ec4a0db5bbccb5b783a1e55", 287318480, "fc74cd0e4be04957b196cf8734ae992396af4cfa8fecbb86f961e6b407d51e11"},
+		{"0707070707070707070707070707070707070707070707070707070707070707", "ad6e2e185a3100e3a6a8b3", "32cb2806b882f1368b0d4a898f72c4c8f728132cc12456946e7f4cb0fb058da9", 11803618549661680832, "5165aff22dd4ed56b4d81d1f171cc3d6432fed1bebf20a7beab12db142f94a0c", "ab7fc566873ccde671f59827678560a006f82bb7adcd75223fa85936f78c2b23", 1051032776, "d2e887bd854a802bce857053020f5d3e7c8ae5267c5b6583b3d212cc8bb69890"},
+		{"0808080808080808080808080808080808080808080808080808080808080808", "21c90e1c658b3efe86af58", "9e64174b4ab981405c323b5e12475945a46d4fedf8060828041cd20e62fd2cef", 5584102310880684544, "8c3e56449dc86354d33b025ef2793460bcb169f3324e4a6b64baa60832315704", "7b48a8375d3ebd56bc649bb5b5242336c2a05a0803239b5b88fd92078fea4d04", 1814747072, "a82f1750cc5b2bee649a365c0420ed87075b8871fda4a7f5840d6bbeb17cd620"},
+		{"0909090909090909090909090909090909090909090909090909090909090909", "233c4ab886a55e3ba374c0", "b68e9ee0c0678d7b3036931c831a25255f7ee487385a30316e15f6482b874fda", 17811330145809239872, "6ebbed743619a256f9ad2e85880cfaa9098a5fdb1629990d9a7d3bb93fc90003", "d376a7bee8ce67f4efde56aa77cf64419b0e550abbcb8e2bcbda8b63e41deb37", 2578461368, "653674873b3c670c58858473e7fe721972fb96e215b87377a17ca3710d93c9e9"},
+	}
+	for i, v := range vectors {
+		var sk SpendingKey
+		copy(sk[:], fromHex(v.sk))
+		fvk := s.FullViewingKey(s.ExpandSpendingKey(&sk))
+		n := testNote(t, s, v.d, v.pkd, v.v, v.rcm)
+		cm, err := s.NoteCommitment(n)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if out := hex.EncodeToString(s.ExtractU(cm)); out != v.cmu {
+			t.Errorf("bad note commitment %d, have: %s, want: %s", i, out, v.cmu)
+		}
+		nf := s.Nullifier(fvk.Nk, cm, v.pos)
+		if out := hex.EncodeToString(nf[:]); out != v.nf {
+			t.Errorf("bad nullifier %d, have: %s, want: %s", i, out, v.nf)
+		}
+	}
+}
+
+func TestPedersenHash(t *testing.T) {
+	s := NewSapling()
+	// computed with the reference implementation, message bits are set at multiples of 5
+	vectors := []struct {
+		personal string
+		n        int
+		out      string
+	}{
+		{"Zcash_PH", 0, "0000000000000000000000000000000000000000000000000000000000000000"},
+		{"Zcash_PH", 1, "8ee44b684487e19d787e5b77cc51d7c5e665e57157c3357d48517f7c0f45ad5d"},
+		// generators beyond the precomputed ones are derived on demand
+		{"Zcash_PH", 3 * pedersenChunks * (pedersenPrecomputed + 1), "7c3aebdae791a5d2a24f009f0e2fa7c76d409bd69dd7deeccb9ae77a5090f863"},
+		{"Test_PH_", 600, "e3a2f55ea7c01ac37e3f3e582a404a3bdff15fabe6b2490d92dc2a614b37bf24"},
+	}
+	for i, v := range vectors {
+		m := make([]bool, v.n)
+		for j := range m {
+			m[j] = j%5 == 0
+		}
+		if out := hex.EncodeToString(s.PedersenHash(v.personal, m)); out != v.out {
+			t.Errorf("bad pedersen hash %d, have: %s, want: %s", i, out, v.out)
+		}
+	}
+}
+
+func BenchmarkNoteCommitment(t *testing.B) {
+	s := NewSapling()
+	n := &Note{D: Diversifier{0xf1, 0x9d, 0x9b, 0x79, 0x7e, 0x39, 0xf3, 0x37, 0x44, 0x58, 0x39}, Value: 1, Rcm: s.scalarField.NewElementFromUint64(1)}
+	n.PkD, _ = s.curve.NewExtendedPointFromCompressed(fromHex("db4cd2b0aac4f7eb8ca131f16567c445a9555126d3c29f14e3d776e841ae7415"))
+	t.ResetTimer()
+	for i := 0; i < t.N; i++ {
+		s.NoteCommitment(n)
+	}
+}
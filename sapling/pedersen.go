@@ -0,0 +1,82 @@
+package sapling
+
+import (
+	"encoding/binary"
+	"math/big"
+
+	"github.com/kilic/go-jubjub"
+)
+
+// 5.4.1.7 Pedersen Hash Function
+
+// number of 3 bit chunks in a segment
+const pedersenChunks = 63
+
+// Generators of "Zcash_PH" for the first segments are precomputed,
+// sufficient for note commitments and Merkle tree hashes
+const pedersenPrecomputed = 4
+
+// I^D_i = FindGroupHash^J(D, I2LEOSP_32(i - 1))
+func (s *Sapling) pedersenGenerator(personal string, i int) *jubjub.ExtendedPoint {
+	if personal == "Zcash_PH" && i < len(s.pedersenGenerators) {
+		return s.pedersenGenerators[i]
+	}
+	var index [4]byte
+	binary.LittleEndian.PutUint32(index[:], uint32(i))
+	return s.findGroupHash(personal, index[:])
+}
+
+// PedersenHashToPoint(D, M) = sum_i [<M_i>] I^D_i
+// where <M_i> = sum_j enc(m_j) 2^(4(j-1)) and enc(s0, s1, s2) = (1 - 2 s2)(1 + s0 + 2 s1)
+func (s *Sapling) PedersenHashToPoint(personal string, m []bool) *jubjub.ExtendedPoint {
+	r := s.curve.NewExtendedPoint()
+	t := s.curve.NewExtendedPoint()
+	bit := func(i int) int64 {
+		if i < len(m) && m[i] {
+			return 1
+		}
+		return 0
+	}
+	chunks := (len(m) + 2) / 3
+	for seg := 0; seg*pedersenChunks < chunks; seg++ {
+		acc, e := new(big.Int), new(big.Int)
+		for j := 0; j < pedersenChunks && seg*pedersenChunks+j < chunks; j++ {
+			k := 3 * (seg*pedersenChunks + j)
+			enc := (1 + bit(k) + 2*bit(k+1)) * (1 - 2*bit(k+2))
+			e.Lsh(big.NewInt(enc), uint(4*j))
+			acc.Add(acc, e)
+		}
+		acc.Mod(acc, s.curve.Order())
+		s.curve.Mul(t, s.pedersenGenerator(personal, seg), s.scalarField.NewElementFromBig(acc))
+		s.curve.Add(r, r, t)
+	}
+	return r
+}
+
+// PedersenHash(D, M) = Extract_J(PedersenHashToPoint(D, M))
+func (s *Sapling) PedersenHash(personal string, m []bool) []byte {
+	return s.ExtractU(s.PedersenHashToPoint(personal, m))
+}
+
+// 5.4.8.4 Sapling Extract_J, little endian encoding of the u coordinate
+func (s *Sapling) ExtractU(p *jubjub.ExtendedPoint) []byte {
+	return reverse(s.curve.Field().ToBytes(p.ToAffine().X()))
+}
+
+// 5.4.1.8 Mixing Pedersen Hash Function
+// MixingPedersenHash(P, x) = P + [x] J^Sapling
+func (s *Sapling) MixingPedersenHash(p *jubjub.ExtendedPoint, x uint64) *jubjub.ExtendedPoint {
+	r := s.curve.NewExtendedPoint()
+	s.curve.Mul(r, s.notePositionGenerator, s.scalarField.NewElementFromUint64(x))
+	s.curve.Add(r, r, p)
+	return r
+}
+
+// Little endian bits of in
+func bytesToBits(in []byte) []bool {
+	out := make([]bool, 8*len(in))
+	for i := range out {
+		out[i] = (in[i/8]>>uint(i%8))&1 == 1
+	}
+	return out
+}
@@ -0,0 +1,102 @@
+package sapling
+
+import (
+	"io"
+
+	"github.com/kilic/go-jubjub"
+)
+
+// 5.4.7 RedDSA, RedJubjub
+// signatures are R || S where R = repr(R) and S is 32 bytes little endian
+
+// H*(M) = LEOS2IP(BLAKE2b-512("Zcash_RedJubjubH", M)) mod r_J
+func (s *Sapling) redJubjubHash(in ...[]byte) *jubjub.ScalarFieldElement {
+	return s.toScalar(RedJubjubDigest(in...))
+}
+
+// BLAKE2b-512("Zcash_RedJubjubH", M), the digest H* reduces
+func RedJubjubDigest(in ...[]byte) []byte {
+	return blake2b(64, "Zcash_RedJubjubH", in...)
+}
+
+// Returns a uniformly random scalar ToScalar of 64 random bytes
+func (s *Sapling) RandScalar(r io.Reader) (*jubjub.ScalarFieldElement, error) {
+	return s.scalarField.RandElement(r)
+}
+
+func (s *Sapling) redJubjubSign(rand io.Reader, g *jubjub.ExtendedPoint, sk *jubjub.ScalarFieldElement, msg []byte) ([]byte, error) {
+	// T is (l_H + 128) / 8 random bytes
+	t := make([]byte, 80)
+	if _, err := io.ReadFull(rand, t); err != nil {
+		return nil, err
+	}
+	vk := s.curve.NewExtendedPoint()
+	s.curve.Mul(vk, g, sk)
+	vkBar := s.encodePoint(vk)
+	// r = H*(T || vk || M), R = [r] P_G
+	r := s.redJubjubHash(t, vkBar, msg)
+	R := s.curve.NewExtendedPoint()
+	s.curve.Mul(R, g, r)
+	rBar := s.encodePoint(R)
+	// S = r + H*(R || vk || M) sk
+	c := s.redJubjubHash(rBar, vkBar, msg)
+	s.scalarField.Mul(c, c, sk)
+	s.scalarField.Add(c, c, r)
+	return append(rBar, s.scalarField.ToBytes(c)...), nil
+}
+
+// [h_G](-[S] P_G + R + [c] vk) = O where c = H*(R || vk || M)
+func (s *Sapling) redJubjubVerify(g, vk *jubjub.ExtendedPoint, msg, sig []byte) bool {
+	if len(sig) != 64 {
+		return false
+	}
+	R, err := s.curve.NewExtendedPointFromCompressed(sig[:32])
+	if err != nil {
+		return false
+	}
+	S, err := s.scalarField.FromBytes(sig[32:])
+	if err != nil {
+		return false
+	}
+	c := s.redJubjubHash(sig[:32], s.encodePoint(vk), msg)
+	p, q := s.curve.NewExtendedPoint(), s.curve.NewExtendedPoint()
+	s.curve.Mul(p, vk, c)
+	s.curve.Add(p, p, R)
+	s.curve.Mul(q, g, S)
+	s.curve.Sub(p, p, q)
+	s.curve.Mul(p, p, s.scalarField.NewElementFromBig(s.curve.Cofactor()))
+	return p.Eq(s.curve.NewExtendedPoint())
+}
+
+// 5.4.7.1 Spend Authorization Signature, P_G = G^Sapling
+func (s *Sapling) SpendAuthSign(rand io.Reader, rsk *jubjub.ScalarFieldElement, msg []byte) ([]byte, error) {
+	return s.redJubjubSign(rand, s.spendingKeyGenerator, rsk, msg)
+}
+
+func (s *Sapling) SpendAuthVerify(rk *jubjub.ExtendedPoint, msg, sig []byte) bool {
+	return s.redJubjubVerify(s.spendingKeyGenerator, rk, msg, sig)
+}
+
+// rsk = ask + alpha
+func (s *Sapling) RandomizePrivate(ask, alpha *jubjub.ScalarFieldElement) *jubjub.ScalarFieldElement {
+	rsk := s.scalarField.NewElement()
+	s.scalarField.Add(rsk, ask, alpha)
+	return rsk
+}
+
+// rk = ak + [alpha] G^Sapling
+func (s *Sapling) RandomizePublic(ak *jubjub.ExtendedPoint, alpha *jubjub.ScalarFieldElement) *jubjub.ExtendedPoint {
+	rk := s.curve.NewExtendedPoint()
+	s.curve.Mul(rk, s.spendingKeyGenerator, alpha)
+	s.curve.Add(rk, rk, ak)
+	return rk
+}
+
+// 5.4.7.2 Binding Signature, P_G = R^Sapling
+func (s *Sapling) BindingSign(rand io.Reader, bsk *jubjub.ScalarFieldElement, sighash []byte) ([]byte, error) {
+	return s.redJubjubSign(rand, s.valueCommitRandomnessGenerator, bsk, sighash)
+}
+
+func (s *Sapling) BindingVerify(bvk *jubjub.ExtendedPoint, sighash, sig []byte) bool {
+	return s.redJubjubVerify(s.valueCommitRandomnessGenerator, bvk, sighash, sig)
+}
@@ -0,0 +1,171 @@
+package sapling
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"testing"
+)
+
+func TestRedJubjub(t *testing.T) {
+	s := NewSapling()
+	vectors := []struct {
+		sk, vk, alpha, rsk, rvk, m, sig, rsig string
+	}{
+		{
+			"18e28dea5c11817aeeb21a19981d28368ec438afc25a8db94ebe08d7a0288e09",
+			"9b0153b03d320fe23e2834d5d61dbb1f519b3f41f8f946152bf0c3f247d11807",
+			"ffd1a1273252b187f4ed326dfc98853e2917c2b36379b175da63b9ef6dda6c08",
+			"6087383b30559b31609085b9009645ceb6a0c6612599d72880728e61244e7d03",
+			"c1babcb6eae2b994ee6d65c10b9dad5940dc735b07504daed1e46b0709b45136",
+			"0000000000000000000000000000000000000000000000000000000000000000",
+			"dca3bb2cb8f048ccab10aed77546c1dbb10cc4fb15ab02acaef944ddab8b6722545fda4c62046d69d98f922f4e8c210bc47b4fdde0a1947179804c1ace569005",
+			"70c284504e90f0008e8ed2208f4969727a415ec3102c299e398b6c16572bd9643ee1011766681e406ee6bee3d03ee8f27176e32fbabdded20b0d1786a4ee1801",
+		},
+		{
+			"059654f961273dafda3b2677b35c18af6b11adfb9ee90b48935e557c8d5d9c04",
+			"faf6c3b737e8e611aafea52f03bb2786e18353ebe0d3139e3c54498780c8c199",
+			"c30b96208da800e10af02542ce694b7ed76a28299f85998e5d610812681bf003",
+			"c8a1ea19efcf3d90e52b4cb981c6632d437cd5243e6fa5d6f0bf5d8ef5788c08",
+			"d524dce7734069758a91f007a869505dfc4aba1720594d4d74f007700e62ee00",
+			"0101010101010101010101010101010101010101010101010101010101010101",
+			"b5a1f32d3d50fc738b5c3b4e9960729ce4316ba7721a12686604feba6bd748450070cb922406fdfc5d60dea9be3a526a16cfeb877779fb782d5d41395b455f04",
+			"5a5a20d200efddd498dfae2a9ef8cf01281a8919018a824cc7a4983b9a0d4a06ff172079e013d42a2a3a88a6520c86fce3b98e1efaa325832a6a5658d8dd7c0a",
+		},
+		{
+			"ade7abb551c79d0f0e42ef7f1206b87712a84a61dea3f37b42496d7efd12520c",
+			"369ea751762f839d25701a5eeb551ec4f06c1290b3b9c3a724402dec02739221",
+			"81922529a63ee743fc4fbbac45c4988316bc9b6e428b01a8d31fc1c2a6ca6205",
+			"774dda0799f7ed828781e25fc4a9e8542829b2ce1ff48d1d6db9fadbb9283703",
+			"0d92ad6d46edacd023d4d2ef703a6ca0a792cfc4b7da11c2353bc845a27a974d",
+			"0202020202020202020202020202020202020202020202020202020202020202",
+			"1f3e8a94310c2071a70f9df5e79aa9e8485deccb178bdff9805fcbe6f7d551eee3c3542ca75c9d8d4adc54d72c3dbe28626d20785bb7f588c1a582b893dbb601",
+			"d136214c5d528ea3d4cb7b631a6bb036064973a108b733a5e3a452ab52a659e567cb55d2644e74b6e8426f2a7dd2a04d2dda4935cc3820b77a9c1ab619863c05",
+		},
+		{
+			"c9d2ae1f6d32a675d09eb0823f467fa921b3284acb35fabdfc994de549b8590d",
+			"2d2f316e5c369ae4dd2c825f3d86460058407184603b212cf3459f36c8697fd8",
+			"ebbc89031107c44f47889ed4d4375a4114cf8a75dd33b962f2d759d3f4c6df06",
+			"fd62414c1f2bd3f49416878a805d714435477fbea72e4c1a46c2735354cabb05",
+			"f0430e953be60bf438dbdcc2303f0e32a6f7ce2fbedfb13ac518f75a3fd10eb5",
+			"0303030303030303030303030303030303030303030303030303030303030303",
+			"12c78ddd20d30a61f8930c6fe0850fd112bb7be88b1238ea33d6bef881c102d104aa36544a78471c9e2842e6fd42558346cff43127032666eb116f442a28480c",
+			"01baaa26274c149acf12e1ccf5507d56790482f067e5c92b3219ad6bf91118cc3fce8d2a23198a3b290a7bf68c2ac07b5d9062b9f868662bb2524912d4856e0c",
+		},
+		{
+			"33bcd2864541b8bb7fdc77a19d970f924eaeecf4103c38c8d2b0668142f27d09",
+			"741794e62cf9320c58bac594a2b90e340a6d8a68056f6ed5c7868c5ff3e4d616",
+			"7ce725a5fef61bd4a1e9c77328e8210eb7292d954c64e99e8bedd07ab3ab0e0d",
+			"f8760155e5293dbf9eb57748325fc9f9049de5885c65ba60b5ee03970be90e08",
+			"6662ba09950accd2cea3c7a81290cd5978a62b5ac5bbc48d9f5819cdc9646f0a",
+			"0404040404040404040404040404040404040404040404040404040404040404",
+			"774ac4673f09f3ac5789b286b5eecbedb257234e8cdfd93f02890978a6bba61169ed48f9e1c9fd1319bd330d2cf5b491010d69b043f4648bff554162c6a6dc09",
+			"7c6c498de001786109b303a4c5dcb7fd075750a0b9df5e1e2a8e7547b7ed70cc0b56a5bfa9657843efd89c66a84f41d2b1b50751196b1e8c0c4498600696a404",
+		},
+		{
+			"ca3506d6af7767b5790ef0c5190fb3f3877c4aab40e0dd651abbdacb544ed005",
+			"bab6cfb5c8ea3491251b46d52aca25d9e9af69faa9b4e40b03ad0086de59b51f",
+			"bea387203f43760ad37d61de0eb59fca6cab7560df64fabb9511579f6f682606",
+			"88d98df6eebaddbf4c8c51a428c452bef427c00b2045d821b0cc316bc4b6f60b",
+			"11267d14d5e0b2bb3ce099e8ef8449471cbcfc6939a4b348dea2c17356a1e8dd",
+			"0505050505050505050505050505050505050505050505050505050505050505",
+			"9a25429f3efd9b2f7de29e45128dd7b760f0508cd9582182abaf53dd76c0342ce41b4acf8e0a4824e41108c2026573114b60beecb174012a2bdbeecbaa00b506",
+			"cff5835713be07fbe125bbf27a636add131c9081716c52fda875426d03982cd27ebd14b4227b839615fd0371bfdb8a30abddff74d795f3e27d1d47c629469b08",
+		},
+		{
+			"bc27838de2a614cfba6c3e922a8f8424d9856f6816f3bc6102313b7faf5c3a0c",
+			"d79be9ff229a2e35f5bca448e5eb4a8aa97fb418029125cfbaa78a91a382b094",
+			"21a7150e194fedfef90c5d10e420858bca4004040eb681d14e75c4471351cb02",
+			"26a2a1c49ce76afd3169d3d57a8fa109a38b3f6b236ed72ca8f6cb61d8f88700",
+			"54bf1be72e6d41208b8aec1161d3ba59519fb93da01a55e678e27520066036c9",
+			"0606060606060606060606060606060606060606060606060606060606060606",
+			"bbe0235987c6e0ec686ddb8a657266ad605f7b75955bb0e802f88164a0ffe10c3b738504abb3d10562b927b3d29fe9b0d356286aeae5a2ac9e435f20791af800",
+			"6de32b5415d77a905f0903902a117eda793c708e23a54245ba8a8d1fe0267523231565e05709aed96c221fb1f3d042043503ff338585a9bb989c9dd430d6d60b",
+		},
+		{
+			"b20859b88ee3338a64954f8a9e8e9bf3e7115acf7c6e7f01432c5f7696d2d005",
+			"a81fe6846dbe0a75c0f49b213232beadd1f9a564673d25b91ee0f17ce9caa363",
+			"44d908e1c15e6bd9380a8b235ace02fac1c08794454bcdb4a6f48cea78a74a04",
+			"f6e1619950429f639d9fdaadf85c9eeda9d2e163c2b94cb6e920ec600f7a1b0a",
+			"0b68d50f913cd1b78b59921e1656d576b0eb171ed3870d39fec69441b34b2538",
+			"0707070707070707070707070707070707070707070707070707070707070707",
+			"446d677c4cfefd024b0aeb37a598cc2eb3d29b0294fe5bb6978e8b43d32b2e4f0956acd13e7e3a63a18fca32d6ab94b94ed033e9a10fc56928bc8a0f4f8e9500",
+			"8de041e709db624ae2be1648b662239cdedf85ecd382268b0e3554bfa0f2081cd641bca04078aa89f7dd2540587ced6b458916b13e4b6a3630da697646dbbf09",
+		},
+		{
+			"3216ae47e9f53e8a52796f24b62460776bd5f205a78e1595bc8efedc519d360b",
+			"df74bf047961cc5cdac82890c76ec675bd4e89ead280c952d7c33eeaf2b5a66b",
+			"c961f2dd93682adb93f5c05a73fdbc6d43c70e1b15e8d53e3f17a82494e3f209",
+			"444ba94e1e50d294635e68b29501b53eae61cd1fbb3b84cd52f6729cfbcbab06",
+			"0afbe406a891c3b8c310c215bc68a913de7cda06af29420056468d0c08855b28",
+			"0808080808080808080808080808080808080808080808080808080808080808",
+			"993580ef93349a1c9ee960ca3e7cd04c13b4a0ec4fd18053a19cff7763620965fbee96c1647230e373cb82b81d00039223d30b393ed172c9b3c563c611792205",
+			"cc7aae1cedad2d7f6ce04c19c5a5b6b7a6a082785c540c14f6309b064d1ffa68172953fba0c2fcfb875ca7f7ea98ef55a0402fd529cfcddf996ca2b8ca89900a",
+		},
+		{
+			"85836f9832b28de7c63613e2a6ed36fb1ab44fb0c13fa8798cd9cd3030d45503",
+			"bfd5bc00c7c022aa8901ae083c12d54b82f0ddff8ed6db9a12d59a5ef6a5a2e0",
+			"a2e8b9e16d6ff3ca6c53d4e88abbb99be7af7e3659631f1eae1eff23874d8e0c",
+			"703f32a34113eae1b0791ffe9d8888f001299ae519686091914899efcc6c6601",
+			"eb9297036cf517e15e9efe3975328db48ee7c2694e946db25f528788f6a1db14",
+			"0909090909090909090909090909090909090909090909090909090909090909",
+			"ce90ddf4af21aac4d94193ea16ff35cd9379204e7d8ff4c0f54117abb16b7c85a0b197cf13ab14d7c3ba68010ab8051225913bdbc39a51f6037afc6ceecb0b06",
+			"a847742e9401cf2239213dc8813e9772e97af8d67adffeabc8e67f5d2d90d0b41bc25b05f94ace168aecc6583e18f7637492f37a9ca300202bc065abd380ec00",
+		},
+	}
+	for i, v := range vectors {
+		sk := s.toScalar(fromHex(v.sk))
+		alpha := s.toScalar(fromHex(v.alpha))
+		vk := s.curve.NewExtendedPoint()
+		s.curve.Mul(vk, s.spendingKeyGenerator, sk)
+		if out := hex.EncodeToString(s.encodePoint(vk)); out != v.vk {
+			t.Errorf("bad verification key %d, have: %s, want: %s", i, out, v.vk)
+		}
+		rsk := s.RandomizePrivate(sk, alpha)
+		if out := hex.EncodeToString(s.scalarField.ToBytes(rsk)); out != v.rsk {
+			t.Errorf("bad randomized signing key %d, have: %s, want: %s", i, out, v.rsk)
+		}
+		rvk := s.RandomizePublic(vk, alpha)
+		if out := hex.EncodeToString(s.encodePoint(rvk)); out != v.rvk {
+			t.Errorf("bad randomized verification key %d, have: %s, want: %s", i, out, v.rvk)
+		}
+		m, sig, rsig := fromHex(v.m), fromHex(v.sig), fromHex(v.rsig)
+		if !s.SpendAuthVerify(vk, m, sig) || !s.SpendAuthVerify(rvk, m, rsig) {
+			t.Errorf("signature %d is not verified", i)
+		}
+		if s.SpendAuthVerify(vk, m, rsig) || s.SpendAuthVerify(rvk, m, sig) {
+			t.Errorf("signature %d is verified under the wrong key", i)
+		}
+		// signatures are randomized
+		sig2, err := s.SpendAuthSign(rand.Reader, rsk, m)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if bytes.Equal(sig2, rsig) || !s.SpendAuthVerify(rvk, m, sig2) {
+			t.Errorf("bad fresh signature %d", i)
+		}
+	}
+	// non canonical S
+	v := vectors[0]
+	vk, _ := s.curve.NewExtendedPointFromCompressed(fromHex(v.vk))
+	sig := fromHex(v.sig)
+	S := leToBig(sig[32:])
+	S.Add(S, s.curve.Order())
+	copy(sig[32:], reverse(S.FillBytes(make([]byte, 32))))
+	if s.SpendAuthVerify(vk, fromHex(v.m), sig) {
+		t.Errorf("signature with non canonical S is verified")
+	}
+}
+
+func BenchmarkSpendAuthVerify(t *testing.B) {
+	s := NewSapling()
+	sk, _ := s.RandScalar(rand.Reader)
+	vk := s.curve.NewExtendedPoint()
+	s.curve.Mul(vk, s.spendingKeyGenerator, sk)
+	m := make([]byte, 32)
+	sig, _ := s.SpendAuthSign(rand.Reader, sk, m)
+	t.ResetTimer()
+	for i := 0; i < t.N; i++ {
+		s.SpendAuthVerify(vk, m, sig)
+	}
+}
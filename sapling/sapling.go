@@ -0,0 +1,82 @@
+// Package sapling implements the Sapling key components of the Zcash protocol over Jubjub.
+//
+// Zcash Protocol Specification, Version 2022.3.8 [NU5]
+// Hopwood, Bowe, Hornby, Wilcox
+package sapling
+
+import (
+	"math/big"
+
+	"github.com/kilic/go-jubjub"
+)
+
+type Sapling struct {
+	curve       *jubjub.Curve
+	scalarField *jubjub.ScalarField
+	// G^Sapling, base point of spend authorization signatures
+	spendingKeyGenerator *jubjub.ExtendedPoint
+	// H^Sapling, base point of the proof generation key
+	proofGenerationKeyGenerator *jubjub.ExtendedPoint
+	// J^Sapling, base point of the mixing Pedersen hash
+	notePositionGenerator *jubjub.ExtendedPoint
+	// base point of the randomness of windowed Pedersen commitments
+	noteCommitRandomnessGenerator *jubjub.ExtendedPoint
+	pedersenGenerators            []*jubjub.ExtendedPoint
+	// V^Sapling and R^Sapling, base points of value commitments
+	valueCommitValueGenerator      *jubjub.ExtendedPoint
+	valueCommitRandomnessGenerator *jubjub.ExtendedPoint
+}
+
+func NewSapling() *Sapling {
+	s := &Sapling{
+		curve:       jubjub.NewJubjub(),
+		scalarField: jubjub.NewJubjubScalarField(),
+	}
+	s.spendingKeyGenerator = s.findGroupHash("Zcash_G_", nil)
+	s.proofGenerationKeyGenerator = s.findGroupHash("Zcash_H_", nil)
+	s.notePositionGenerator = s.findGroupHash("Zcash_J_", nil)
+	s.noteCommitRandomnessGenerator = s.findGroupHash("Zcash_PH", []byte("r"))
+	s.valueCommitValueGenerator = s.findGroupHash("Zcash_cv", []byte("v"))
+	s.valueCommitRandomnessGenerator = s.findGroupHash("Zcash_cv", []byte("r"))
+	for i := 0; i < pedersenPrecomputed; i++ {
+		s.pedersenGenerators = append(s.pedersenGenerators, s.pedersenGenerator("Zcash_PH", i))
+	}
+	return s
+}
+
+func (s *Sapling) Curve() *jubjub.Curve {
+	return s.curve
+}
+
+// G^Sapling, base point of spend authorization signatures
+func (s *Sapling) SpendingKeyGenerator() *jubjub.ExtendedPoint {
+	return new(jubjub.ExtendedPoint).Set(s.spendingKeyGenerator)
+}
+
+// 5.4.2 Pseudo Random Functions
+// PRF^expand(sk, t) = BLAKE2b-512("Zcash_ExpandSeed", sk || t)
+func PRFExpand(sk []byte, t ...byte) []byte {
+	return blake2b(64, "Zcash_ExpandSeed", sk, t)
+}
+
+// ToScalar(x) = LEOS2IP(x) mod r_J
+func (s *Sapling) toScalar(in []byte) *jubjub.ScalarFieldElement {
+	return s.scalarField.FromBytesWide(in)
+}
+
+func (s *Sapling) encodePoint(p *jubjub.ExtendedPoint) []byte {
+	return s.curve.Compress(p.ToAffine())
+}
+
+func leToBig(in []byte) *big.Int {
+	return new(big.Int).SetBytes(reverse(in))
+}
+
+// Returns a reversed copy of in
+func reverse(in []byte) []byte {
+	out := make([]byte, len(in))
+	for i := range in {
+		out[len(in)-1-i] = in[i]
+	}
+	return out
+}
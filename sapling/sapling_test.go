@@ -0,0 +1,221 @@
+package sapling
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+
+	x2b "golang.org/x/crypto/blake2b"
+	x2s "golang.org/x/crypto/blake2s"
+)
+
+// test vectors are from https://github.com/zcash/zcash-test-vectors
+
+func fromHex(s string) []byte {
+	b, _ := hex.DecodeString(s)
+	return b
+}
+
+func TestBlake2(t *testing.T) {
+	// without personalization digests must agree with golang.org/x/crypto
+	in := make([]byte, 300)
+	for i := range in {
+		in[i] = byte(i)
+	}
+	for l := 0; l <= len(in); l++ {
+		e := x2b.Sum512(in[:l])
+		if !bytes.Equal(blake2b(64, "", in[:l]), e[:]) {
+			t.Fatalf("bad blake2b digest for length %d", l)
+		}
+		f := x2s.Sum256(in[:l])
+		if !bytes.Equal(blake2s(32, "", in[:l]), f[:]) {
+			t.Fatalf("bad blake2s digest for length %d", l)
+		}
+	}
+}
+
+func TestGenerators(t *testing.T) {
+	s := NewSapling()
+	vectors := []struct {
+		personal string
+		msg      []byte
+		out      string
+	}{
+		{"Zcash_G_", nil, "30b5f2aaad325630bcdddbce4d67656d05fd1cc2d037bb5375b6e96d9e01a1d7"},
+		{"Zcash_H_", nil, "e7e85de0f7f97a46d249a1f5ea51df50cc48490f8401c9de7a2adf1807d1b6d4"},
+		{"Zcash_J_", nil, "65002bc736faf7a3422effffe8b855e18fba96a0158a9efca584bf40549d36e1"},
+		{"Zcash_PH", []byte("r"), "ac776c796563fcd44cc49cfaea8bb796952c266e47779d94574c10ad01754b11"},
+		{"Zcash_cv", []byte("v"), "d7c86706f5817aa718cd1cfad03233bcd64a7789fd9422d3b17af6823a7e6ac6"},
+		{"Zcash_cv", []byte("r"), "8b6a0b38b9faae3c3b803b47b0f146ad50ab221e6e2afbe6dbde45cba9d381ed"},
+		{"Zcash_PH", []byte{0, 0, 0, 0}, "ca3c2432d4abbf7732464ec08b2e47f95edc7e836b16c979571b52d3a2879ea8"},
+		{"Zcash_PH", []byte{1, 0, 0, 0}, "9118bf4e3cc50d7be8d3fa98ebbe3a1f25d901c0421189f733fe435b7f8c5d01"},
+		{"Zcash_PH", []byte{2, 0, 0, 0}, "57d493972c50ed8098b484177f2ab28b53e88c8e6ca400e09eee4ed200152eb6"},
+		{"Zcash_PH", []byte{3, 0, 0, 0}, "e97035a3ec4b7184856a1fa1a1af0351b747d9d8cb0a0791d8ca564b0ce47e2f"},
+	}
+	for i, v := range vectors {
+		p := s.findGroupHash(v.personal, v.msg)
+		if out := hex.EncodeToString(s.encodePoint(p)); out != v.out {
+			t.Errorf("bad generator %d, have: %s, want: %s", i, out, v.out)
+		}
+	}
+	if !s.spendingKeyGenerator.Eq(s.findGroupHash("Zcash_G_", nil)) {
+		t.Errorf("bad spending key generator")
+	}
+	if !s.proofGenerationKeyGenerator.Eq(s.findGroupHash("Zcash_H_", nil)) {
+		t.Errorf("bad proof generation key generator")
+	}
+}
+
+func TestKeyComponents(t *testing.T) {
+	s := NewSapling()
+	vectors := []struct {
+		sk, ask, nsk, ovk, ak, nk, ivk string
+	}{
+		{
+			"0000000000000000000000000000000000000000000000000000000000000000",
+			"8548a14a473ea547aa2378402044f818cf1911cf5dd2054f678345f00d0e8806",
+			"30114ea0dd0bb61cf0eaeab6ec3331f581b0425e27338501262d7eac745e6e05",
+			"98d16913d99b04177caba44f6e4d224e03b5ac031d7ce45e865138e1b996d63b",
+			"f344ec380fe1273e3098c2588c5d3a791fd7ba958032760777fd0efa8ef11620",
+			"f7cf9e77f2e58683383c1519ac7b062d30040e27a725fb88fb19a978bd3fd6ba",
+			"b70b7cd0ed03cbdfd7ada9502ee245b13e569d54a5719d2daa0f5f1451479204",
+		},
+		{
+			"0101010101010101010101010101010101010101010101010101010101010101",
+			"c9435629bf8bffe55e7335ec077718ba60ba28d7ac3794b74f512c31af0a5304",
+			"11acc2ead07b5f008c1f0f090cc8ddf335236ff4b253c6495695e9d639dacd08",
+			"3b946210ce6d1b1692d7392ac84a8bc8f03b72723c7d36721b809a79c9d6e45b",
+			"82ff5effc527ae84020bf2d35201c10219131947ff4b96f881a45f2e8ae30518",
+			"c4534d848bb918cf4a7f8b98740ab3ccee586795ff4df64547a8888a6c7415d2",
+			"c518384466b26988b5109067418d192d9d6bd0d9232205d77418c240fc68a406",
+		},
+		{
+			"0202020202020202020202020202020202020202020202020202020202020202",
+			"ee1c3d7efe0a78063d6af3d9d81212af47b7c1b761f85ccb066fc11a6a421703",
+			"1d3b713755d74875e8ea38fd166e76c62a4250216e6bbfe48a5e2eabad117f0b",
+			"8bf4390e28ddc95b8302c381d5810b84ba8e6096e5a76822774fd49f491e8f49",
+			"ab83574eb5de859a0ab8629dec34c7bee8c3fc74dfa0b19a3a7468d15dca64c6",
+			"95d58053e0592e4a169cc0b7928aaac3de24ef1531aa9eb6f4ab93914da8a06e",
+			"471c24a3dc8730e75036c0a95f3e2f7dd1be6fb93ad29592203def3041954505",
+		},
+		{
+			"0303030303030303030303030303030303030303030303030303030303030303",
+			"00c3a1e1ca8f4e0480ee1ee90ca7517879d3fc5c815c0903e5eebc94bb809503",
+			"e66285a5e9b65e157ad2fcd543dad98c67a58abdf287e05506bd1c2e59b0720b",
+			"147678e0553b97829347647c5bc7dab4cc2202b54ec29fd31a3de6be0825fc5e",
+			"3c9cde7e5d0d38a8610faadbcf4c343f5d3cfa3155a5b94661a6753e96e884ea",
+			"b77d36f508941dbd61cfd0f159ee05cfaa78a26c9492903806d83b598d3c1c2a",
+			"636aa964bfc23ce4b1fcf7dfc99179ddc406ff55400c9295acfc14f031c72600",
+		},
+		{
+			"0404040404040404040404040404040404040404040404040404040404040404",
+			"8236d19d3205d85543a06811343f827b6563770a49aa4d0ca0081805d4c8ea0d",
+			"7ec1ef0bed82718272f0f44f017c484174513d661dd168af02d2092a1d8a0507",
+			"1b6e75ece3ace8dba6a5410d9ad4755668e4b39585d635ec1da7c8dcfd5fc4ed",
+			"55e88389bb7e41de130cfa51a8715fde01ff9c6876647f0175ad34f058dde01a",
+			"725d4ad6a15021cd1c48c5ee19de6c1e768a2cc0a9a730a01bb21c95e3d9e43c",
+			"67fa2bf7c67d4658243c317c0cb41fd32064dfd3709fe0dcb724f14bb01a1d04",
+		},
+		{
+			"0505050505050505050505050505050505050505050505050505050505050505",
+			"eae6884d764a054061a8f1c0076c624dcb738789f7ad1e7408e31f24dfc82607",
+			"fbe610f42a41749f9b6e6e4a54b5a32ebfe8f43800881ba6cd13ed0b05294601",
+			"c6bc1f39f0d786314cb20bf9ab228540913555f970696b6d7c77bb332328372a",
+			"e682765914e3864c339e5782b855c0fdf40e0dfcedb9e7b47bc94b90b3a4c988",
+			"82256b95623c67024b4424d91400a370e7ac8e4d15482a3759e00d219749daee",
+			"ea3f1d80e4307ca73b9f37801f91fba810cc41d279fc29f564235654a2178e03",
+		},
+		{
+			"0606060606060606060606060606060606060606060606060606060606060606",
+			"e8f816b4bc08a7e566750cc28afe82a4cea9c2bef244fa4b13c4739b28074c0d",
+			"32615b137f2801ed446e48781ab0634572e18cfb0693721b8803c05b8227d107",
+			"f62c05e848a873ef885e12b08c5e7ca2f32424bacc754cb69750444d355f5106",
+			"ff27db0751945d3ee4be9cf15c2ea211b24b164d5f2d7ddff5e4a0708f10b95e",
+			"943885959d4ef8a9cfca07c457f09ec74b96f993d8e0fa32b19c03e3b07a420f",
+			"b5c5894943956933c0e5c12d311fc12cba58354b5c389edc03da55084f74c205",
+		},
+		{
+			"0707070707070707070707070707070707070707070707070707070707070707",
+			"74b44a37f15023c060427e1daea3f64312dd8feb7b2cedf0dd5544493f872c06",
+			"075c35db8b1b25754223ecee34ab730dddd1f14a6a54f4c6f468453c3c6ed60b",
+			"e9e0dc1ed311daed64bd74da5d94fe88a6ea414b7312de3d2a78f64632bbe373",
+			"283f9aafa9bcb3e6ce17e63212634cb3ee550c476b676bd356a6df8adf51d25e",
+			"dc4c67b10d4b0a218dc6e1487066740a409317866c32e664b50e397aa80389d4",
+			"8716c82880e13683e1bb059dd06c80c90134a96d5afca8aac2bbf68bb05f8402",
+		},
+		{
+			"0808080808080808080808080808080808080808080808080808080808080808",
+			"039dd93df311ff8fbab3fe230219cd42ac879484f30b903a3c1e67ccca5a7b0d",
+			"049fa14f486c75b9fad7e3b673a443dd074eaa96edcb2a53eaaabdaf70ffbb08",
+			"147dd11d77eba1b1636fd6190c62b9a5d0481bee7e917fab02e21858063ab504",
+			"364048eedbe8ca205eb7e7ba0a9012166c7c7bd9eb228e08481448c488aa21d2",
+			"ed60af1ce7df38070d3851432a96480db0b417c3682a1d68e3e89334235c0bdf",
+			"99c9b4b84f4b4e350f787d1cf7051d50ecc34b1a5b20d2d2139b4af1f160e001",
+		},
+		{
+			"0909090909090909090909090909090909090909090909090909090909090909",
+			"ebbb40a980ba3b8860948d011e1bfb4affe16c652e90e98258302f4464c91e0c",
+			"68431b199104215200b95ee5cb71bf8b883a3e95b7989cad197063141ebbfd00",
+			"573467a7b30ead6ccc504744ca9e1a281a0d1a08738b06a0684feacd1e9d126d",
+			"71c3523eeca35311fbd5d7e7d70b709d6c35a24f262b34bf64059bf2c02e0ba8",
+			"624400103b6569b7358fe80f6f6cad4325defda9d9499c2b8f886a6269a2aa52",
+			"db95ea8bd9f93d41b5ab2bebc91a38edd527083e2a6ef9f3c29702d5ff89ed00",
+		},
+	}
+	for i, v := range vectors {
+		var sk SpendingKey
+		copy(sk[:], fromHex(v.sk))
+		esk := s.ExpandSpendingKey(&sk)
+		if hex.EncodeToString(s.scalarField.ToBytes(esk.Ask)) != v.ask {
+			t.Errorf("bad ask %d", i)
+		}
+		if hex.EncodeToString(s.scalarField.ToBytes(esk.Nsk)) != v.nsk {
+			t.Errorf("bad nsk %d", i)
+		}
+		if hex.EncodeToString(esk.Ovk[:]) != v.ovk {
+			t.Errorf("bad ovk %d", i)
+		}
+		fvk := s.FullViewingKey(esk)
+		if hex.EncodeToString(s.encodePoint(fvk.Ak)) != v.ak {
+			t.Errorf("bad ak %d", i)
+		}
+		if hex.EncodeToString(s.encodePoint(fvk.Nk)) != v.nk {
+			t.Errorf("bad nk %d", i)
+		}
+		ivk := s.IncomingViewingKey(fvk)
+		if hex.EncodeToString(ivk.Bytes()) != v.ivk {
+			t.Errorf("bad ivk %d", i)
+		}
+	}
+}
+
+func TestKeyEncoding(t *testing.T) {
+	s := NewSapling()
+	var sk SpendingKey
+	copy(sk[:], fromHex("0101010101010101010101010101010101010101010101010101010101010101"))
+	esk := s.ExpandSpendingKey(&sk)
+	esk2, err := s.DecodeExpandedSpendingKey(s.EncodeExpandedSpendingKey(esk))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(s.EncodeExpandedSpendingKey(esk2), s.EncodeExpandedSpendingKey(esk)) {
+		t.Errorf("bad expanded spending key encoding")
+	}
+	fvk := s.FullViewingKey(esk)
+	in := s.EncodeFullViewingKey(fvk)
+	fvk2, err := s.DecodeFullViewingKey(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !fvk2.Ak.Eq(fvk.Ak) || !fvk2.Nk.Eq(fvk.Nk) || fvk2.Ovk != fvk.Ovk {
+		t.Errorf("bad full viewing key encoding")
+	}
+	// identity ak
+	copy(in, s.encodePoint(s.curve.NewExtendedPoint()))
+	if _, err := s.DecodeFullViewingKey(in); err == nil {
+		t.Errorf("identity ak is not expected to be accepted")
+	}
+	if _, err := s.DecodeFullViewingKey(in[:95]); err == nil {
+		t.Errorf("short input is not expected to be accepted")
+	}
+}
@@ -0,0 +1,133 @@
+package sapling
+
+import (
+	"context"
+	"runtime"
+	"sync"
+
+	"github.com/kilic/go-jubjub"
+)
+
+// Outputs are trial decrypted in batches of this size,
+// affine normalization of shared secrets costs one inversion per batch and viewing key
+const scanBatchSize = 128
+
+// Fields of a Sapling output description used in trial decryption
+type Output struct {
+	Cmu           [32]byte
+	Epk           [32]byte
+	EncCiphertext []byte
+}
+
+type ScannedNote struct {
+	// index of the output in the scanned outputs
+	OutputIndex int
+	// index of the viewing key that decrypts the output
+	KeyIndex int
+	Note     *NotePlaintext
+	Address  *PaymentAddress
+}
+
+// Trial decrypts every output with every incoming viewing key across workers goroutines,
+// or runtime.NumCPU() of them if workers is not positive.
+// Notes are returned ordered by output index and then key index.
+// Outputs with undecodable epk or ciphertexts of another size are skipped.
+func (s *Sapling) ScanOutputs(ctx context.Context, workers int, ivks []*IncomingViewingKey, outputs []*Output) ([]*ScannedNote, error) {
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+	// [h_J] ivk is not reduced, so KA^Sapling is a single multiplication
+	// that agrees with [h_J] [ivk] epk for epk outside of the prime order subgroup
+	keys := make([]*jubjub.ScalarFieldElement, len(ivks))
+	for i, ivk := range ivks {
+		n := ivk.Ivk.Big()
+		keys[i] = s.scalarField.NewElementFromBig(n.Mul(n, s.curve.Cofactor()))
+	}
+	batches := (len(outputs) + scanBatchSize - 1) / scanBatchSize
+	results := make([][]*ScannedNote, batches)
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for b := range jobs {
+				results[b] = s.scanBatch(ivks, keys, outputs, b*scanBatchSize)
+			}
+		}()
+	}
+	var err error
+feed:
+	for b := 0; b < batches; b++ {
+		select {
+		case <-ctx.Done():
+			err = ctx.Err()
+			break feed
+		case jobs <- b:
+		}
+	}
+	close(jobs)
+	wg.Wait()
+	if err != nil {
+		return nil, err
+	}
+	var notes []*ScannedNote
+	for _, r := range results {
+		notes = append(notes, r...)
+	}
+	return notes, nil
+}
+
+func (s *Sapling) scanBatch(ivks []*IncomingViewingKey, keys []*jubjub.ScalarFieldElement, outputs []*Output, offset int) []*ScannedNote {
+	end := offset + scanBatchSize
+	if end > len(outputs) {
+		end = len(outputs)
+	}
+	var index []int
+	var epks []*jubjub.ExtendedPoint
+	for i := offset; i < end; i++ {
+		if len(outputs[i].EncCiphertext) != encCiphertextSize {
+			continue
+		}
+		epk, err := s.curve.NewExtendedPointFromCompressed(outputs[i].Epk[:])
+		if err != nil {
+			continue
+		}
+		index = append(index, i)
+		epks = append(epks, epk)
+	}
+	if len(epks) == 0 {
+		return nil
+	}
+	found := make([][]*ScannedNote, len(epks))
+	shared := make([]*jubjub.ExtendedPoint, len(epks))
+	for i := range shared {
+		shared[i] = s.curve.NewExtendedPoint()
+	}
+	for k, key := range keys {
+		for i, epk := range epks {
+			s.curve.Mul(shared[i], epk, key)
+		}
+		for i, p := range s.curve.BatchToAffine(shared) {
+			o := outputs[index[i]]
+			plaintext, err := symDecrypt(kdf(s.curve.Compress(p), o.Epk[:]), o.EncCiphertext)
+			if err != nil {
+				continue
+			}
+			np := decodeNotePlaintext(plaintext)
+			addr, err := s.Address(ivks[k], &np.D)
+			if err != nil {
+				continue
+			}
+			if err := s.checkNotePlaintext(np, addr.PkD, epks[i], nil, o.Cmu[:]); err != nil {
+				continue
+			}
+			found[i] = append(found[i], &ScannedNote{OutputIndex: index[i], KeyIndex: k, Note: np, Address: addr})
+		}
+	}
+	var notes []*ScannedNote
+	for _, f := range found {
+		notes = append(notes, f...)
+	}
+	return notes
+}
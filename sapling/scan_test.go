@@ -0,0 +1,115 @@
+package sapling
+
+import (
+	"context"
+	"crypto/rand"
+	"testing"
+)
+
+func testOutput(t testing.TB, s *Sapling, sk *SpendingKey, value uint64) *Output {
+	addr, err := s.DefaultAddress(sk)
+	if err != nil {
+		t.Fatal(err)
+	}
+	np := &NotePlaintext{LeadByte: leadByteZIP212, D: addr.D, Value: value}
+	if _, err := rand.Read(np.Rseed[:]); err != nil {
+		t.Fatal(err)
+	}
+	note, err := s.PlaintextNote(np, addr.PkD)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cmu, err := s.NoteCommitmentU(note)
+	if err != nil {
+		t.Fatal(err)
+	}
+	esk, err := s.NoteEsk(nil, np)
+	if err != nil {
+		t.Fatal(err)
+	}
+	en, err := s.EncryptNote(rand.Reader, esk, np, addr.PkD, nil, nil, cmu)
+	if err != nil {
+		t.Fatal(err)
+	}
+	o := &Output{Epk: en.Epk, EncCiphertext: en.EncCiphertext[:]}
+	copy(o.Cmu[:], cmu)
+	return o
+}
+
+func TestScanOutputs(t *testing.T) {
+	s := NewSapling()
+	sks := []SpendingKey{{1}, {2}, {3}}
+	var ivks []*IncomingViewingKey
+	for i := range sks {
+		ivks = append(ivks, s.IncomingViewingKey(s.FullViewingKey(s.ExpandSpendingKey(&sks[i]))))
+	}
+	// outputs span several batches, the third key receives nothing
+	// and every fourth output is sent to a key that is not scanned
+	other := SpendingKey{4}
+	recipients := []*SpendingKey{&sks[0], &sks[1], &other, &sks[1]}
+	n := 2*scanBatchSize + 10
+	outputs := make([]*Output, n)
+	for i := range outputs {
+		outputs[i] = testOutput(t, s, recipients[i%4], uint64(i))
+	}
+	outputs[5].Epk = [32]byte{0xff}
+	outputs[9].EncCiphertext = outputs[9].EncCiphertext[:10]
+	outputs[13].Cmu[0] ^= 1
+
+	notes, err := s.ScanOutputs(context.Background(), 4, ivks, outputs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var expected []*ScannedNote
+	for i, o := range outputs {
+		for k, ivk := range ivks {
+			if np, addr, err := s.DecryptNote(ivk, o.Epk[:], o.Cmu[:], o.EncCiphertext); err == nil {
+				expected = append(expected, &ScannedNote{OutputIndex: i, KeyIndex: k, Note: np, Address: addr})
+			}
+		}
+	}
+	// outputs 5, 9 and 13 are malformed
+	if len(expected) != n-n/4-3 {
+		t.Fatalf("bad number of sequentially decrypted notes %d", len(expected))
+	}
+	if len(notes) != len(expected) {
+		t.Fatalf("bad number of scanned notes, have: %d, want: %d", len(notes), len(expected))
+	}
+	for i, note := range notes {
+		e := expected[i]
+		if note.OutputIndex != e.OutputIndex || note.KeyIndex != e.KeyIndex || *note.Note != *e.Note || !note.Address.PkD.Eq(e.Address.PkD) {
+			t.Errorf("bad scanned note %d", i)
+		}
+		if note.Note.Value != uint64(note.OutputIndex) {
+			t.Errorf("bad scanned note value %d", i)
+		}
+	}
+
+	// no keys or outputs
+	if notes, err := s.ScanOutputs(context.Background(), 0, nil, outputs); err != nil || len(notes) != 0 {
+		t.Errorf("expected no notes without viewing keys")
+	}
+	if notes, err := s.ScanOutputs(context.Background(), 0, ivks, nil); err != nil || len(notes) != 0 {
+		t.Errorf("expected no notes without outputs")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := s.ScanOutputs(ctx, 2, ivks, outputs); err != context.Canceled {
+		t.Errorf("expected cancellation error, have: %v", err)
+	}
+}
+
+func BenchmarkScanOutputs(t *testing.B) {
+	s := NewSapling()
+	sk := SpendingKey{1}
+	outputs := make([]*Output, scanBatchSize)
+	for i := range outputs {
+		outputs[i] = testOutput(t, s, &sk, 1)
+	}
+	ivks := []*IncomingViewingKey{s.IncomingViewingKey(s.FullViewingKey(s.ExpandSpendingKey(&SpendingKey{2})))}
+	t.ResetTimer()
+	for i := 0; i < t.N; i++ {
+		s.ScanOutputs(context.Background(), 0, ivks, outputs)
+	}
+}
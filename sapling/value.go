@@ -0,0 +1,104 @@
+package sapling
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/kilic/go-jubjub"
+)
+
+// 5.4.8.3 Homomorphic Pedersen commitments
+// ValueCommit_rcv(v) = [v] V^Sapling + [rcv] R^Sapling
+// V^Sapling = FindGroupHash^J("Zcash_cv", "v")
+// R^Sapling = FindGroupHash^J("Zcash_cv", "r")
+
+type ValueCommitment struct {
+	Cv *jubjub.ExtendedPoint
+}
+
+// v is signed to commit to value balances
+func (s *Sapling) ValueCommit(v int64, rcv *jubjub.ScalarFieldElement) *ValueCommitment {
+	cv := s.curve.NewExtendedPoint()
+	r := s.curve.NewExtendedPoint()
+	s.curve.Mul(cv, s.valueCommitValueGenerator, s.signedScalar(v))
+	s.curve.Mul(r, s.valueCommitRandomnessGenerator, rcv)
+	s.curve.Add(cv, cv, r)
+	return &ValueCommitment{Cv: cv}
+}
+
+// c = a + b commits to the sum of values under the sum of trapdoors
+func (s *Sapling) AddValueCommitment(c, a, b *ValueCommitment) {
+	if c.Cv == nil {
+		c.Cv = s.curve.NewExtendedPoint()
+	}
+	s.curve.Add(c.Cv, a.Cv, b.Cv)
+}
+
+// c = a - b commits to the difference of values under the difference of trapdoors
+func (s *Sapling) SubValueCommitment(c, a, b *ValueCommitment) {
+	if c.Cv == nil {
+		c.Cv = s.curve.NewExtendedPoint()
+	}
+	s.curve.Sub(c.Cv, a.Cv, b.Cv)
+}
+
+// Returns 32 bytes repr(cv)
+func (s *Sapling) EncodeValueCommitment(cv *ValueCommitment) []byte {
+	return s.encodePoint(cv.Cv)
+}
+
+func (s *Sapling) DecodeValueCommitment(in []byte) (*ValueCommitment, error) {
+	if len(in) != 32 {
+		return nil, fmt.Errorf("bad value commitment input size")
+	}
+	cv, err := s.curve.NewExtendedPointFromCompressed(in)
+	if err != nil {
+		return nil, err
+	}
+	// cv is only required to be on the curve and not of small order
+	small := s.curve.NewExtendedPoint()
+	s.curve.Mul(small, cv, s.scalarField.NewElementFromBig(s.curve.Cofactor()))
+	if small.Eq(s.curve.NewExtendedPoint()) {
+		return nil, fmt.Errorf("value commitment is of small order")
+	}
+	return &ValueCommitment{Cv: cv}, nil
+}
+
+// 4.13 Balance and Binding Signature
+// bsk = sum rcv_spend - sum rcv_output
+func (s *Sapling) BindingSigningKey(spendRcv, outputRcv []*jubjub.ScalarFieldElement) *jubjub.ScalarFieldElement {
+	bsk := s.scalarField.NewElement()
+	for _, rcv := range spendRcv {
+		s.scalarField.Add(bsk, bsk, rcv)
+	}
+	for _, rcv := range outputRcv {
+		s.scalarField.Sub(bsk, bsk, rcv)
+	}
+	return bsk
+}
+
+// bvk = sum cv_spend - sum cv_output - ValueCommit_0(v_balance)
+func (s *Sapling) BindingVerificationKey(spendCv, outputCv []*ValueCommitment, valueBalance int64) *jubjub.ExtendedPoint {
+	bvk := s.curve.NewExtendedPoint()
+	for _, cv := range spendCv {
+		s.curve.Add(bvk, bvk, cv.Cv)
+	}
+	for _, cv := range outputCv {
+		s.curve.Sub(bvk, bvk, cv.Cv)
+	}
+	s.curve.Sub(bvk, bvk, s.ValueCommit(valueBalance, s.scalarField.NewElement()).Cv)
+	return bvk
+}
+
+// Reports whether bvk = [bsk] R^Sapling, that is committed values balance to v_balance
+func (s *Sapling) CheckBalance(bsk *jubjub.ScalarFieldElement, bvk *jubjub.ExtendedPoint) bool {
+	p := s.curve.NewExtendedPoint()
+	s.curve.Mul(p, s.valueCommitRandomnessGenerator, bsk)
+	return p.Eq(bvk)
+}
+
+// v mod r_J for signed values
+func (s *Sapling) signedScalar(v int64) *jubjub.ScalarFieldElement {
+	n := big.NewInt(v)
+	return s.scalarField.NewElementFromBig(n.Mod(n, s.curve.Order()))
+}
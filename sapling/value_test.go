@@ -0,0 +1,115 @@
+package sapling
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"testing"
+
+	"github.com/kilic/go-jubjub"
+)
+
+func TestValueCommitment(t *testing.T) {
+	s := NewSapling()
+	// note encryption vectors where rcv = rcm
+	vectors := []struct {
+		v       int64
+		rcv, cv string
+	}{
+		{100000000, "39176dac39ace4980ecc8d778e89860255ec3615060000000000000000000000", "a9cb0d137232ff8448d0f078b6814c66cb331b0f2d3d8a085bedba815f00a8db"},
+		{200000000, "478ba0ee6e1a75b600036f26f18b7015ab556beddf8b960238869f89dd804e06", "fc54319a39be49c0480c4df33b8f77ca673a42bfdedfb80ee46b8f70fc0dcd3d"},
+		{300000000, "147cf2b51b4c7c63cb77b99e8b783e5b5111db0a7ca04d6c014a1d7da83bae0a", "5cc9ea168e79ff0d083af421d32d27fba1c8a638c0c352cf59dcb1ca84c3fb1b"},
+		{400000000, "34a4b2a9144ff5ea54efee87cf901b5bed5e35d21fbbd788d5bd9d833e112804", "6d6ef8ce979274094f191aef643f3fcbd1ac9d98d607e2bcfef6fd51ba4bb4b9"},
+		{500000000, "e557851355747c09ac59013cbde85980964ec1844d9c6967ca0c029c8457bb04", "ce42f9d089ba9d9e62e3f6563362f0fdc7cede8ab359439e214e2652dbf05a0c"},
+		{600000000, "68f06104606b0c5449845ff4c65f73e90f45ef5a43c9d74cb2c85cf56c94c002", "3027d7b74764caf72b7387289b128f439fd042c21d81364bc2ae7bd29eab5123"},
+		{700000000, "49f90b47fd52fee7c1c81f0dcb5b74c3fb9b3e03976f8b7524eabad008892107", "770894c7a5458b167d8518a547bc62b46ba189807eb97c08284e1b92b6da352a"},
+		{800000000, "5165aff22dd4ed56b4d81d1f171cc3d6432fed1bebf20a7beab12db142f94a0c", "2954cc7f9f9dfeb14f02eebff3f848d5d0e3d2e01febc91641f4126c6034330c"},
+		{900000000, "8c3e56449dc86354d33b025ef2793460bcb169f3324e4a6b64baa60832315704", "4a85eb3f253f3baaf6b55a994951b2ca8248cbd679f7a577e33bcd6646b21351"},
+		{1000000000, "6ebbed743619a256f9ad2e85880cfaa9098a5fdb1629990d9a7d3bb93fc90003", "2a547d978c7c90a8d0a5474e29dbfff34bae81e6408ec1fe2d56a25241a8e329"},
+	}
+	for i, v := range vectors {
+		cv := s.ValueCommit(v.v, s.toScalar(fromHex(v.rcv)))
+		if out := hex.EncodeToString(s.EncodeValueCommitment(cv)); out != v.cv {
+			t.Errorf("bad value commitment %d, have: %s, want: %s", i, out, v.cv)
+		}
+		dec, err := s.DecodeValueCommitment(fromHex(v.cv))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !dec.Cv.Eq(cv.Cv) {
+			t.Errorf("bad decoded value commitment %d", i)
+		}
+	}
+	// homomorphism
+	r1, r2 := s.toScalar(fromHex(vectors[0].rcv)), s.toScalar(fromHex(vectors[1].rcv))
+	a, b := s.ValueCommit(5, r1), s.ValueCommit(7, r2)
+	sum, diff := new(ValueCommitment), new(ValueCommitment)
+	s.AddValueCommitment(sum, a, b)
+	s.SubValueCommitment(diff, a, b)
+	r := s.scalarField.NewElement()
+	s.scalarField.Add(r, r1, r2)
+	if !sum.Cv.Eq(s.ValueCommit(12, r).Cv) {
+		t.Errorf("bad value commitment sum")
+	}
+	s.scalarField.Sub(r, r1, r2)
+	if !diff.Cv.Eq(s.ValueCommit(-2, r).Cv) {
+		t.Errorf("bad value commitment difference")
+	}
+	if _, err := s.DecodeValueCommitment(make([]byte, 32)); err == nil {
+		t.Errorf("expected error for small order value commitment")
+	}
+}
+
+func TestBindingSignature(t *testing.T) {
+	s := NewSapling()
+	rcv := func() *jubjub.ScalarFieldElement {
+		r, err := s.RandScalar(rand.Reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return r
+	}
+	spendValues, outputValues := []int64{100, 250}, []int64{120, 200}
+	valueBalance := int64(30)
+	var spendRcv, outputRcv []*jubjub.ScalarFieldElement
+	var spendCv, outputCv []*ValueCommitment
+	for _, v := range spendValues {
+		spendRcv = append(spendRcv, rcv())
+		spendCv = append(spendCv, s.ValueCommit(v, spendRcv[len(spendRcv)-1]))
+	}
+	for _, v := range outputValues {
+		outputRcv = append(outputRcv, rcv())
+		outputCv = append(outputCv, s.ValueCommit(v, outputRcv[len(outputRcv)-1]))
+	}
+	bsk := s.BindingSigningKey(spendRcv, outputRcv)
+	bvk := s.BindingVerificationKey(spendCv, outputCv, valueBalance)
+	if !s.CheckBalance(bsk, bvk) {
+		t.Fatalf("values are expected to balance")
+	}
+	sighash := make([]byte, 32)
+	sig, err := s.BindingSign(rand.Reader, bsk, sighash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !s.BindingVerify(bvk, sighash, sig) {
+		t.Errorf("binding signature is not verified")
+	}
+	if s.SpendAuthVerify(bvk, sighash, sig) {
+		t.Errorf("binding signature is verified as spend authorization")
+	}
+	sighash[0] = 1
+	if s.BindingVerify(bvk, sighash, sig) {
+		t.Errorf("binding signature is verified for another sighash")
+	}
+	// unbalanced
+	bvk = s.BindingVerificationKey(spendCv, outputCv, valueBalance+1)
+	if s.CheckBalance(bsk, bvk) {
+		t.Errorf("values are not expected to balance")
+	}
+	sig, err = s.BindingSign(rand.Reader, bsk, sighash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.BindingVerify(bvk, sighash, sig) {
+		t.Errorf("binding signature of unbalanced values is verified")
+	}
+}
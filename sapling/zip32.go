@@ -0,0 +1,296 @@
+package sapling
+
+import (
+	"encoding/binary"
+	"fmt"
+)
+
+// ZIP-32 Shielded Hierarchical Deterministic Wallets
+// Sapling extended keys
+
+// Child indices at and above this value are hardened
+const HardenedIndex uint32 = 1 << 31
+
+type ExtendedSpendingKey struct {
+	Depth     uint8
+	ParentTag [4]byte
+	Index     uint32
+	ChainCode [32]byte
+	ExpandedSpendingKey
+	Dk [32]byte
+}
+
+type ExtendedFullViewingKey struct {
+	Depth     uint8
+	ParentTag [4]byte
+	Index     uint32
+	ChainCode [32]byte
+	FullViewingKey
+	Dk [32]byte
+}
+
+// 88 bits little endian diversifier index
+type DiversifierIndex [11]byte
+
+var extendedSpendingKeyHRP = map[Network]string{
+	Mainnet: "secret-extended-key-main",
+	Testnet: "secret-extended-key-test",
+	Regtest: "secret-extended-key-regtest",
+}
+
+var extendedFullViewingKeyHRP = map[Network]string{
+	Mainnet: "zxviews",
+	Testnet: "zxviewtestsapling",
+	Regtest: "zxviewregtestsapling",
+}
+
+// I = BLAKE2b-512("ZcashIP32Sapling", seed)
+// sk_m = I_L, c_m = I_R
+func (s *Sapling) MasterKey(seed []byte) *ExtendedSpendingKey {
+	i := blake2b(64, "ZcashIP32Sapling", seed)
+	xsk := &ExtendedSpendingKey{
+		ExpandedSpendingKey: *s.ExpandSpendingKey((*SpendingKey)(i[:32])),
+	}
+	copy(xsk.ChainCode[:], i[32:])
+	copy(xsk.Dk[:], PRFExpand(i[:32], 0x10))
+	return xsk
+}
+
+// Derives both hardened and non hardened children of an extended spending key
+func (s *Sapling) DeriveChild(xsk *ExtendedSpendingKey, i uint32) *ExtendedSpendingKey {
+	fvk := s.FullViewingKey(&xsk.ExpandedSpendingKey)
+	var prefix []byte
+	if i >= HardenedIndex {
+		prefix = append([]byte{0x11}, s.EncodeExpandedSpendingKey(&xsk.ExpandedSpendingKey)...)
+	} else {
+		prefix = append([]byte{0x12}, s.EncodeFullViewingKey(fvk)...)
+	}
+	prefix = append(prefix, xsk.Dk[:]...)
+	il, ir := childPRF(xsk.ChainCode[:], prefix, i)
+	child := &ExtendedSpendingKey{
+		Depth:     xsk.Depth + 1,
+		ParentTag: s.Tag(fvk),
+		Index:     i,
+		ExpandedSpendingKey: ExpandedSpendingKey{
+			Ask: s.scalarField.NewElement(),
+			Nsk: s.scalarField.NewElement(),
+		},
+	}
+	// ask_i = ToScalar(PRF^expand(I_L, [0x13])) + ask
+	// nsk_i = ToScalar(PRF^expand(I_L, [0x14])) + nsk
+	s.scalarField.Add(child.Ask, s.toScalar(PRFExpand(il, 0x13)), xsk.Ask)
+	s.scalarField.Add(child.Nsk, s.toScalar(PRFExpand(il, 0x14)), xsk.Nsk)
+	child.Ovk, child.Dk = deriveOvkDk(il, &xsk.Ovk, &xsk.Dk)
+	copy(child.ChainCode[:], ir)
+	return child
+}
+
+// Derives a non hardened child of an extended full viewing key
+func (s *Sapling) DeriveChildFullViewingKey(xfvk *ExtendedFullViewingKey, i uint32) (*ExtendedFullViewingKey, error) {
+	if i >= HardenedIndex {
+		return nil, fmt.Errorf("hardened child of a full viewing key")
+	}
+	prefix := append([]byte{0x12}, s.EncodeFullViewingKey(&xfvk.FullViewingKey)...)
+	prefix = append(prefix, xfvk.Dk[:]...)
+	il, ir := childPRF(xfvk.ChainCode[:], prefix, i)
+	child := &ExtendedFullViewingKey{
+		Depth:     xfvk.Depth + 1,
+		ParentTag: s.Tag(&xfvk.FullViewingKey),
+		Index:     i,
+		FullViewingKey: FullViewingKey{
+			Ak: s.curve.NewExtendedPoint(),
+			Nk: s.curve.NewExtendedPoint(),
+		},
+	}
+	// ak_i = ToScalar(PRF^expand(I_L, [0x13])).G^Sapling + ak
+	// nk_i = ToScalar(PRF^expand(I_L, [0x14])).H^Sapling + nk
+	s.curve.Mul(child.Ak, s.spendingKeyGenerator, s.toScalar(PRFExpand(il, 0x13)))
+	s.curve.Add(child.Ak, child.Ak, xfvk.Ak)
+	s.curve.Mul(child.Nk, s.proofGenerationKeyGenerator, s.toScalar(PRFExpand(il, 0x14)))
+	s.curve.Add(child.Nk, child.Nk, xfvk.Nk)
+	child.Ovk, child.Dk = deriveOvkDk(il, &xfvk.Ovk, &xfvk.Dk)
+	copy(child.ChainCode[:], ir)
+	return child, nil
+}
+
+// Derives the key at path from the master key, use HardenedIndex + i for hardened steps
+func (s *Sapling) DerivePath(seed []byte, path ...uint32) *ExtendedSpendingKey {
+	xsk := s.MasterKey(seed)
+	for _, i := range path {
+		xsk = s.DeriveChild(xsk, i)
+	}
+	return xsk
+}
+
+// I = PRF^expand(c, prefix || I2LEOSP_32(i))
+func childPRF(c, prefix []byte, i uint32) ([]byte, []byte) {
+	var index [4]byte
+	binary.LittleEndian.PutUint32(index[:], i)
+	out := PRFExpand(c, append(prefix, index[:]...)...)
+	return out[:32], out[32:]
+}
+
+// ovk_i = truncate_32(PRF^expand(I_L, [0x15] || ovk))
+// dk_i = truncate_32(PRF^expand(I_L, [0x16] || dk))
+func deriveOvkDk(il []byte, ovk, dk *[32]byte) (ovkI, dkI [32]byte) {
+	copy(ovkI[:], PRFExpand(il, append([]byte{0x15}, ovk[:]...)...))
+	copy(dkI[:], PRFExpand(il, append([]byte{0x16}, dk[:]...)...))
+	return
+}
+
+func (s *Sapling) ExtendedFullViewingKey(xsk *ExtendedSpendingKey) *ExtendedFullViewingKey {
+	return &ExtendedFullViewingKey{
+		Depth:          xsk.Depth,
+		ParentTag:      xsk.ParentTag,
+		Index:          xsk.Index,
+		ChainCode:      xsk.ChainCode,
+		FullViewingKey: *s.FullViewingKey(&xsk.ExpandedSpendingKey),
+		Dk:             xsk.Dk,
+	}
+}
+
+// FVK_Fingerprint = BLAKE2b-256("ZcashSaplingFVFP", repr(ak) || repr(nk) || ovk)
+func (s *Sapling) Fingerprint(fvk *FullViewingKey) [32]byte {
+	var fp [32]byte
+	copy(fp[:], blake2b(32, "ZcashSaplingFVFP", s.EncodeFullViewingKey(fvk)))
+	return fp
+}
+
+// FVK_Tag is the first 4 bytes of the fingerprint
+func (s *Sapling) Tag(fvk *FullViewingKey) [4]byte {
+	var tag [4]byte
+	fp := s.Fingerprint(fvk)
+	copy(tag[:], fp[:])
+	return tag
+}
+
+// d_j = FF1-AES256(dk, "", I2LEBSP_88(j))
+func DeriveDiversifier(dk *[32]byte, j *DiversifierIndex) Diversifier {
+	x := make([]byte, ff1Bits)
+	for i := range x {
+		x[i] = (j[i/8] >> uint(i%8)) & 1
+	}
+	// can not fail with valid key and input sizes
+	y, _ := ff1AES256Encrypt(dk[:], nil, x)
+	var d Diversifier
+	for i, v := range y {
+		d[i/8] |= v << uint(i%8)
+	}
+	return d
+}
+
+// Returns the address at the first valid diversifier index starting from j
+func (s *Sapling) FindAddress(xfvk *ExtendedFullViewingKey, j *DiversifierIndex) (*PaymentAddress, DiversifierIndex, error) {
+	ivk := s.IncomingViewingKey(&xfvk.FullViewingKey)
+	next := *j
+	for {
+		d := DeriveDiversifier(&xfvk.Dk, &next)
+		if addr, err := s.Address(ivk, &d); err == nil {
+			return addr, next, nil
+		}
+		if !incrementDiversifier((*Diversifier)(&next)) {
+			return nil, next, fmt.Errorf("diversifier space is exhausted")
+		}
+	}
+}
+
+// Returns depth || parent tag || i || c || ask || nsk || ovk || dk
+func (s *Sapling) EncodeExtendedSpendingKey(xsk *ExtendedSpendingKey) []byte {
+	out := encodeExtendedHeader(xsk.Depth, &xsk.ParentTag, xsk.Index, &xsk.ChainCode)
+	out = append(out, s.EncodeExpandedSpendingKey(&xsk.ExpandedSpendingKey)...)
+	return append(out, xsk.Dk[:]...)
+}
+
+func (s *Sapling) DecodeExtendedSpendingKey(in []byte) (*ExtendedSpendingKey, error) {
+	if len(in) != 169 {
+		return nil, fmt.Errorf("bad extended spending key input size")
+	}
+	esk, err := s.DecodeExpandedSpendingKey(in[41:137])
+	if err != nil {
+		return nil, err
+	}
+	xsk := &ExtendedSpendingKey{ExpandedSpendingKey: *esk}
+	decodeExtendedHeader(in, &xsk.Depth, &xsk.ParentTag, &xsk.Index, &xsk.ChainCode)
+	copy(xsk.Dk[:], in[137:])
+	return xsk, nil
+}
+
+// Returns depth || parent tag || i || c || repr(ak) || repr(nk) || ovk || dk
+func (s *Sapling) EncodeExtendedFullViewingKey(xfvk *ExtendedFullViewingKey) []byte {
+	out := encodeExtendedHeader(xfvk.Depth, &xfvk.ParentTag, xfvk.Index, &xfvk.ChainCode)
+	out = append(out, s.EncodeFullViewingKey(&xfvk.FullViewingKey)...)
+	return append(out, xfvk.Dk[:]...)
+}
+
+func (s *Sapling) DecodeExtendedFullViewingKey(in []byte) (*ExtendedFullViewingKey, error) {
+	if len(in) != 169 {
+		return nil, fmt.Errorf("bad extended full viewing key input size")
+	}
+	fvk, err := s.DecodeFullViewingKey(in[41:137])
+	if err != nil {
+		return nil, err
+	}
+	xfvk := &ExtendedFullViewingKey{FullViewingKey: *fvk}
+	decodeExtendedHeader(in, &xfvk.Depth, &xfvk.ParentTag, &xfvk.Index, &xfvk.ChainCode)
+	copy(xfvk.Dk[:], in[137:])
+	return xfvk, nil
+}
+
+func encodeExtendedHeader(depth uint8, tag *[4]byte, i uint32, c *[32]byte) []byte {
+	out := make([]byte, 9, 169)
+	out[0] = depth
+	copy(out[1:5], tag[:])
+	binary.LittleEndian.PutUint32(out[5:9], i)
+	return append(out, c[:]...)
+}
+
+func decodeExtendedHeader(in []byte, depth *uint8, tag *[4]byte, i *uint32, c *[32]byte) {
+	*depth = in[0]
+	copy(tag[:], in[1:5])
+	*i = binary.LittleEndian.Uint32(in[5:9])
+	copy(c[:], in[9:41])
+}
+
+func (s *Sapling) EncodeExtendedSpendingKeyBech32(xsk *ExtendedSpendingKey, network Network) (string, error) {
+	hrp, ok := extendedSpendingKeyHRP[network]
+	if !ok {
+		return "", fmt.Errorf("unknown network")
+	}
+	return bech32Encode(hrp, s.EncodeExtendedSpendingKey(xsk)), nil
+}
+
+func (s *Sapling) DecodeExtendedSpendingKeyBech32(in string) (*ExtendedSpendingKey, Network, error) {
+	hrp, data, err := bech32Decode(in)
+	if err != nil {
+		return nil, 0, err
+	}
+	for network, h := range extendedSpendingKeyHRP {
+		if h == hrp {
+			xsk, err := s.DecodeExtendedSpendingKey(data)
+			return xsk, network, err
+		}
+	}
+	return nil, 0, fmt.Errorf("unknown human readable part %s", hrp)
+}
+
+func (s *Sapling) EncodeExtendedFullViewingKeyBech32(xfvk *ExtendedFullViewingKey, network Network) (string, error) {
+	hrp, ok := extendedFullViewingKeyHRP[network]
+	if !ok {
+		return "", fmt.Errorf("unknown network")
+	}
+	return bech32Encode(hrp, s.EncodeExtendedFullViewingKey(xfvk)), nil
+}
+
+func (s *Sapling) DecodeExtendedFullViewingKeyBech32(in string) (*ExtendedFullViewingKey, Network, error) {
+	hrp, data, err := bech32Decode(in)
+	if err != nil {
+		return nil, 0, err
+	}
+	for network, h := range extendedFullViewingKeyHRP {
+		if h == hrp {
+			xfvk, err := s.DecodeExtendedFullViewingKey(data)
+			return xfvk, network, err
+		}
+	}
+	return nil, 0, fmt.Errorf("unknown human readable part %s", hrp)
+}
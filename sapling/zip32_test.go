@@ -0,0 +1,266 @@
+package sapling
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+)
+
+func TestFF1(t *testing.T) {
+	// NIST SP 800-38G, consistent with the reference implementation vectors
+	key := fromHex("2b7e151628aed2a6abf7158809cf4f3cef4359d8d580aa4f7f036d6f04fc6a94")
+	toBits := func(s string) []byte {
+		out := make([]byte, len(s))
+		for i := range s {
+			out[i] = s[i] - '0'
+		}
+		return out
+	}
+	alternating := "0101010101010101010101010101010101010101010101010101010101010101010101010101010101010101"
+	tweak := make([]byte, 255)
+	for i := range tweak {
+		tweak[i] = byte(i)
+	}
+	vectors := []struct {
+		tweak  []byte
+		in, ct string
+	}{
+		{
+			nil,
+			"0000000000000000000000000000000000000000000000000000000000000000000000000000000000000000",
+			"0000100100110101011101111111110011000001101100111110011101110101011010100100010011001111",
+		},
+		{
+			nil,
+			"0000100100110101011101111111110011000001101100111110011101110101011010100100010011001111",
+			"1101101011010001100011110000010011001111110110011101010110100001111001000101011111011000",
+		},
+		{
+			nil,
+			alternating,
+			"0000111101000001111011010111011111110001100101000000001101101110100010010111001100100110",
+		},
+		{
+			tweak,
+			alternating,
+			"0111110110001000000111010110000100010101101000000011100111100100100010101101111010100011",
+		},
+	}
+	for i, v := range vectors {
+		ct, err := ff1AES256Encrypt(key, v.tweak, toBits(v.in))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(ct, toBits(v.ct)) {
+			t.Errorf("bad ff1 ciphertext %d", i)
+		}
+		pt, err := ff1AES256Decrypt(key, v.tweak, ct)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(pt, toBits(v.in)) {
+			t.Errorf("bad ff1 plaintext %d", i)
+		}
+	}
+}
+
+func TestZIP32(t *testing.T) {
+	s := NewSapling()
+	seed := make([]byte, 32)
+	for i := range seed {
+		seed[i] = byte(i)
+	}
+	// m, m/1, m/1/2', m/1/2' as full viewing key, m/1/2'/3 from the full viewing key
+	vectors := []struct {
+		xsk, xfvk string
+		fp, ivk   string
+		d         [4]string
+	}{
+		{
+			"000000000000000000d0947c4b03bf72a37ab44f72276d1cf3fdcd7ebf3e73348b7e550d752018668eb6c00c93d36032b9a268e99e86a860776560bf0e83c1a10b51f607c9547425068204ede83b2f1fbd84f9b45d7f996e2ebd0a030ad243b48ed39f748a8821ea06395884890323b9d4933c021db89bcf767df21977b2ff0683848321a4df4afb2177c17cb75b7796afb39f0f3e91c924607da56fa9a20e283509bc8a3ef996a172",
+			"000000000000000000d0947c4b03bf72a37ab44f72276d1cf3fdcd7ebf3e73348b7e550d752018668e93442e5feffbff16e7217202dc7306729ffffe85af5683bce2642e3eeb5d3871dce8e7edece04b8950417f85ba57691b783c45b1a27422db1693dceb67b10106395884890323b9d4933c021db89bcf767df21977b2ff0683848321a4df4afb2177c17cb75b7796afb39f0f3e91c924607da56fa9a20e283509bc8a3ef996a172",
+			"14c2713adce93a830ea83a051908b7447783f5d106c0985e02550e426f27597c",
+			"4847a130e799d3dbea36a1c16467d621fb2d80e30b3b1d1a426893415dad6601",
+			[4]string{"d8621b981cf300e9d4cc89", "48ea17a199c84bd1baa5d4", "", ""},
+		},
+		{
+			"0114c2713a010000000147110c691a03b9d9f0ba9005c5e790a595b7f04e3329d2fa438a6705dabce6282bc197a516287c8ea8f68c424abad302b45cdf95407961d7b8b455267a350ce7a32988fdca1efcd6d1c4c562e629c2e96b2c3f7eda04ac4efd1810ff6bba015f1381fc8886da6a02dffeefcf503c40fa8f5a36f7a7142fd81b5518c5a47474e04de832a2d791ec129ab9002b91c9e9cdeed79241a7c4960e5178d870c1b4dc",
+			"0114c2713a010000000147110c691a03b9d9f0ba9005c5e790a595b7f04e3329d2fa438a6705dabce6dc14b514d3a92594c21925af2f7765a547b30e73fa7b700ea1bff2e5efaaa88b6152eb7fdb252779ddcb95d217ea4b6fd34036e9adadb3b5c9cbeceb41ba452a5f1381fc8886da6a02dffeefcf503c40fa8f5a36f7a7142fd81b5518c5a47474e04de832a2d791ec129ab9002b91c9e9cdeed79241a7c4960e5178d870c1b4dc",
+			"db999e071dcb58dd93029ae697053e90edb359d1a1b7a125167efbe928068423",
+			"155a8ee205d3872d12f8a3e639914633c23cde1f30ed5051e52130b1d0104c06",
+			[4]string{"8b4138320dfafd7b399781", "", "5749a13352bc223e308078", "6389574cde0fbbc6368131"},
+		},
+		{
+			"02db999e070200008097ce15f4ed1b9739b0262a463bcb3dc9b3bd2323a9baa441ca42777383a8d4358be8113cee3413a71f82c41fc8da517be134049832e6825c92da6b84fee4c60d3778059dc569e7d0d32391573f951bbde92fc6b9cf614773661c5c273aa6990ccf81182e96223c028ce3d6eb4794d3113b95069d14c57588e193b65efc2813bca3eda19f9eff46ca12dfa1bf10371b48d1b4a40c4d05a0d8dce0e7dc62b07b37",
+			"02db999e070200008097ce15f4ed1b9739b0262a463bcb3dc9b3bd2323a9baa441ca42777383a8d435a6c5925a0f85fa4f1e405e3a4970d0c4a4b4814438f4e9d4520e20f7fdcf3841304e305916216beb7b654d8aae50ecd188fcb384bc36c00c664f307725e2ee11cf81182e96223c028ce3d6eb4794d3113b95069d14c57588e193b65efc2813bca3eda19f9eff46ca12dfa1bf10371b48d1b4a40c4d05a0d8dce0e7dc62b07b37",
+			"48c183757b5da6612a81b30e40b4acaa2d9e739512e1d2d0010e92a7f7f2fcdf",
+			"a2a13c1e38b45984445803e430a683c90bb2e14d4c8692ff253a6484dd9bb504",
+			[4]string{"e8d03793cdd2bacc9c7041", "020a7a6b0bf84d3e899f68", "", ""},
+		},
+		{
+			"",
+			"0348c18375030000008d937bcf81ba430d5b49afc0a403367b1fd99879ecba41be051c5a4aa7d6e7e8b185c57b509c2536c4f2d326d766c8fab25447de5375a9328d649ddabd97a6a3db88049e02d207568afc42e07db2abed500b2701c01bbff36399764b81c0664f69b9e0fa1c4b3deb91d53beee871156121474b8b62ef24134478dc3499691af6becb50c363bb2ed9da5c3043ceb0f1a0527bf836b29a35f7c0c9f261123be56e",
+			"2e08156df8dfa25b5055fc063c671535a6a65a60437d96e7930815d090f62d67",
+			"b0a5f337232f2c3dac70c2a410fa561fc45d8cc59cda246d31c8b1715a57d900",
+			[4]string{"", "030ffb263a939e230e96dd", "7bbf63934c7e92670cdb55", "1a730feb0059cf1f5bdea8"},
+		},
+	}
+	m := s.MasterKey(seed)
+	m1 := s.DeriveChild(m, 1)
+	m12h := s.DeriveChild(m1, HardenedIndex+2)
+	m12h3, err := s.DeriveChildFullViewingKey(s.ExtendedFullViewingKey(m12h), 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	xsks := []*ExtendedSpendingKey{m, m1, m12h, nil}
+	xfvks := []*ExtendedFullViewingKey{
+		s.ExtendedFullViewingKey(m),
+		s.ExtendedFullViewingKey(m1),
+		s.ExtendedFullViewingKey(m12h),
+		m12h3,
+	}
+	var dmax DiversifierIndex
+	for i := range dmax {
+		dmax[i] = 0xff
+	}
+	indices := []DiversifierIndex{{0}, {1}, {2}, dmax}
+	for i, v := range vectors {
+		if xsk := xsks[i]; xsk != nil {
+			if out := hex.EncodeToString(s.EncodeExtendedSpendingKey(xsk)); out != v.xsk {
+				t.Errorf("bad extended spending key %d, have: %s, want: %s", i, out, v.xsk)
+			}
+		}
+		xfvk := xfvks[i]
+		if out := hex.EncodeToString(s.EncodeExtendedFullViewingKey(xfvk)); out != v.xfvk {
+			t.Errorf("bad extended full viewing key %d, have: %s, want: %s", i, out, v.xfvk)
+		}
+		fp := s.Fingerprint(&xfvk.FullViewingKey)
+		if out := hex.EncodeToString(fp[:]); out != v.fp {
+			t.Errorf("bad fingerprint %d, have: %s, want: %s", i, out, v.fp)
+		}
+		ivk := s.IncomingViewingKey(&xfvk.FullViewingKey)
+		if out := hex.EncodeToString(ivk.Bytes()); out != v.ivk {
+			t.Errorf("bad incoming viewing key %d, have: %s, want: %s", i, out, v.ivk)
+		}
+		for j, e := range v.d {
+			d := DeriveDiversifier(&xfvk.Dk, &indices[j])
+			_, ok := s.DiversifyHash(&d)
+			if ok != (e != "") {
+				t.Errorf("bad diversifier validity %d %d", i, j)
+			}
+			if out := hex.EncodeToString(d[:]); ok && out != e {
+				t.Errorf("bad diversifier %d %d, have: %s, want: %s", i, j, out, e)
+			}
+		}
+	}
+	// m/1/2'/3 from the spending key agrees with the full viewing key path
+	if !bytes.Equal(s.EncodeExtendedFullViewingKey(s.ExtendedFullViewingKey(s.DeriveChild(m12h, 3))), s.EncodeExtendedFullViewingKey(m12h3)) {
+		t.Errorf("non hardened derivation paths do not agree")
+	}
+	if _, err := s.DeriveChildFullViewingKey(xfvks[0], HardenedIndex); err == nil {
+		t.Errorf("expected error for hardened derivation of a full viewing key")
+	}
+	// first valid diversifier index of m/1 is 0 and of m/1/2'/3 is 1
+	for _, v := range []struct {
+		key int
+		j   byte
+	}{{1, 0}, {3, 1}} {
+		addr, j, err := s.FindAddress(xfvks[v.key], &DiversifierIndex{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if j[0] != v.j {
+			t.Errorf("bad first diversifier index %d, have: %d, want: %d", v.key, j[0], v.j)
+		}
+		if out := hex.EncodeToString(addr.D[:]); out != vectors[v.key].d[v.j] {
+			t.Errorf("bad first address diversifier %d, have: %s, want: %s", v.key, out, vectors[v.key].d[v.j])
+		}
+	}
+}
+
+func TestZIP32Hardened(t *testing.T) {
+	s := NewSapling()
+	seed := make([]byte, 32)
+	for i := range seed {
+		seed[i] = byte(i)
+	}
+	// m, m/1', m/1'/2', m/1'/2'/3'
+	vectors := []string{
+		"000000000000000000d0947c4b03bf72a37ab44f72276d1cf3fdcd7ebf3e73348b7e550d752018668eb6c00c93d36032b9a268e99e86a860776560bf0e83c1a10b51f607c9547425068204ede83b2f1fbd84f9b45d7f996e2ebd0a030ad243b48ed39f748a8821ea06395884890323b9d4933c021db89bcf767df21977b2ff0683848321a4df4afb2177c17cb75b7796afb39f0f3e91c924607da56fa9a20e283509bc8a3ef996a172",
+		"0114c2713a010000806fccaa45a8206b063ebb68c610e05927aa94d61be93ec25eb4f82efd68caaedbd5f7e92efb7abe04dc8c148b0b3b0fc23e0429f00208ff93b68d21a6e131bd04372a7c6822cbe603f3465c4b9b6558f3a3512decd434012e67bffcf657e5750a2530761933348c1fcf14355433a8d291167fbb37b2ce37ca97160a47ec331c69f288400fd65f9adfe3a7c3720aceee0dae050d0a819d619f92e9e2cb4434d526",
+		"02768423cb020000804479086c75d080796020f500c1e30a54cfe29dda36f2144fb33a50806fbef7da7ff35db69e13c36f59ad9c08d32d5227378da0cff971fd424baef9a6332f5106779c6ee4a03944eba28bc9bdc1329a391407f48c410d5ae0a364f59959bfde00d9fc7101bf907f41886a7330a5d6a7bd23535e305eb7679bc23d7605936185ace4699e9a86e031c54b21cdd0960ac18ddd61ec9f7ae98d5582a6faf65f3248d1",
+		"030bdc2d2b0300008033dc012d7690ced2cd2bcb2cc3e463e28d8c29ef3b01be59b2bdfc385bbdc74b4593d24d21e35937f152cf90461c332f69503c104581d683e0ac29f84decaf071ac87ec2123f5057e3c0f858e80dfa0ee4553ded27b7b5abfbb6fa6effa7bb0b1e36ea0cf2be2e9d6ce380a8af18e75da9225551fbef8b98311b5c9c1b4b9ee357fc6c59a4f3ad5a6f609db671d28cbf703f0d14dc363aaaed70729c107bbb6a",
+	}
+	for i, v := range vectors {
+		path := make([]uint32, i)
+		for j := range path {
+			path[j] = HardenedIndex + uint32(j+1)
+		}
+		xsk := s.DerivePath(seed, path...)
+		if out := hex.EncodeToString(s.EncodeExtendedSpendingKey(xsk)); out != v {
+			t.Errorf("bad extended spending key %d, have: %s, want: %s", i, out, v)
+		}
+	}
+}
+
+func TestZIP32Encoding(t *testing.T) {
+	s := NewSapling()
+	seed := make([]byte, 32)
+	for i := range seed {
+		seed[i] = byte(i)
+	}
+	m := s.MasterKey(seed)
+	xsk, err := s.EncodeExtendedSpendingKeyBech32(m, Mainnet)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "secret-extended-key-main1qqqqqqqqqqqqqqxsj37ykqalw23h4dz0wgnk688nlhxha0e7wv6gklj4p46jqxrx36mvqryn6dsr9wdzdr5eap4gvpmk2c9lp6purggt28mq0j25wsjsdqsyah5rktclhkz0ndza07vkut4apgps45jrkj8d88m532yzr6sx89vgfzgrywuafyeuqgwm3x70we7lyxthktlsdquysvs6fh62lvsh0stukadh0940kw0s7053eyjxqld9d756yr3gx5ymez37lxt2zuscfzd9h"
+	if xsk != expected {
+		t.Errorf("bad bech32 extended spending key, have: %s, want: %s", xsk, expected)
+	}
+	xfvk, err := s.EncodeExtendedFullViewingKeyBech32(s.ExtendedFullViewingKey(m), Mainnet)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected = "zxviews1qqqqqqqqqqqqqqxsj37ykqalw23h4dz0wgnk688nlhxha0e7wv6gklj4p46jqxrx36f5gtjlalal79h8y9eq9hrnqeeflll7skh4dqauufjzu0htt5u8rh8gulk7eczt39gyzlu9hftkjxmc83zmrgn5ytd3dy7uadnmzqgx89vgfzgrywuafyeuqgwm3x70we7lyxthktlsdquysvs6fh62lvsh0stukadh0940kw0s7053eyjxqld9d756yr3gx5ymez37lxt2zuscwhlr7"
+	if xfvk != expected {
+		t.Errorf("bad bech32 extended full viewing key, have: %s, want: %s", xfvk, expected)
+	}
+	for _, network := range []Network{Mainnet, Testnet, Regtest} {
+		k := s.DerivePath(seed, HardenedIndex+32, HardenedIndex+133, HardenedIndex)
+		enc, err := s.EncodeExtendedSpendingKeyBech32(k, network)
+		if err != nil {
+			t.Fatal(err)
+		}
+		dec, net, err := s.DecodeExtendedSpendingKeyBech32(enc)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if net != network || !bytes.Equal(s.EncodeExtendedSpendingKey(dec), s.EncodeExtendedSpendingKey(k)) {
+			t.Errorf("extended spending key round trip failed")
+		}
+		enc, err = s.EncodeExtendedFullViewingKeyBech32(s.ExtendedFullViewingKey(k), network)
+		if err != nil {
+			t.Fatal(err)
+		}
+		decFVK, net, err := s.DecodeExtendedFullViewingKeyBech32(enc)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if net != network || !bytes.Equal(s.EncodeExtendedFullViewingKey(decFVK), s.EncodeExtendedFullViewingKey(s.ExtendedFullViewingKey(k))) {
+			t.Errorf("extended full viewing key round trip failed")
+		}
+	}
+	if _, _, err := s.DecodeExtendedSpendingKeyBech32(xfvk); err == nil {
+		t.Errorf("expected error for mismatching human readable part")
+	}
+}
+
+func BenchmarkDeriveChild(t *testing.B) {
+	s := NewSapling()
+	m := s.MasterKey(make([]byte, 32))
+	t.ResetTimer()
+	for i := 0; i < t.N; i++ {
+		s.DeriveChild(m, HardenedIndex)
+	}
+}
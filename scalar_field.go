@@ -1,7 +1,8 @@
 package jubjub
 
 import (
-	"crypto/rand"
+	"fmt"
+	"io"
 	"math/big"
 )
 
@@ -9,16 +10,58 @@ type ScalarField struct {
 	q *big.Int
 }
 
+func NewScalarField(q *big.Int) *ScalarField {
+	return &ScalarField{
+		q: new(big.Int).Set(q),
+	}
+}
+
 func (field *ScalarField) NewElement() *ScalarFieldElement {
 	return &ScalarFieldElement{
 		n: new(big.Int),
 	}
 }
 
-func (field *ScalarField) NewRandElement() *ScalarFieldElement {
-	e := field.NewElement()
-	e.n, _ = rand.Int(rand.Reader, field.q)
-	return e
+// Returns a uniform element from 64 bytes of r with wide reduction
+func (field *ScalarField) RandElement(r io.Reader) (*ScalarFieldElement, error) {
+	in := make([]byte, 64)
+	if _, err := io.ReadFull(r, in); err != nil {
+		return nil, err
+	}
+	return field.FromBytesWide(in), nil
+}
+
+// Returns the canonical little endian encoding of a
+func (field *ScalarField) ToBytes(a *ScalarFieldElement) []byte {
+	out := make([]byte, (field.q.BitLen()+7)/8)
+	new(big.Int).Mod(a.n, field.q).FillBytes(out)
+	return reverseBytes(out)
+}
+
+// Decodes a canonical little endian encoding, values not less than the modulus are rejected
+func (field *ScalarField) FromBytes(in []byte) (*ScalarFieldElement, error) {
+	if len(in) != (field.q.BitLen()+7)/8 {
+		return nil, fmt.Errorf("bad scalar length")
+	}
+	n := new(big.Int).SetBytes(reverseBytes(in))
+	if n.Cmp(field.q) >= 0 {
+		return nil, fmt.Errorf("non canonical scalar")
+	}
+	return new(ScalarFieldElement).set(n), nil
+}
+
+// Returns little endian input of any length reduced modulo the modulus
+func (field *ScalarField) FromBytesWide(in []byte) *ScalarFieldElement {
+	n := new(big.Int).SetBytes(reverseBytes(in))
+	return new(ScalarFieldElement).set(n.Mod(n, field.q))
+}
+
+func reverseBytes(in []byte) []byte {
+	out := make([]byte, len(in))
+	for i := range in {
+		out[len(in)-1-i] = in[i]
+	}
+	return out
 }
 
 func (field *ScalarField) NewElementFromUint64(a uint64) *ScalarFieldElement {
@@ -46,6 +89,11 @@ func (e *ScalarFieldElement) set(a *big.Int) *ScalarFieldElement {
 	return e
 }
 
+// Returns the value as big.Int
+func (e *ScalarFieldElement) Big() *big.Int {
+	return new(big.Int).Set(e.n)
+}
+
 func (e *ScalarFieldElement) bit(i int) uint {
 	return e.n.Bit(i)
 }
@@ -59,6 +107,16 @@ func (field *ScalarField) Add(c *ScalarFieldElement, a *ScalarFieldElement, b *S
 	c.n.Mod(c.n, field.q)
 }
 
+func (field *ScalarField) Sub(c *ScalarFieldElement, a *ScalarFieldElement, b *ScalarFieldElement) {
+	c.n.Sub(a.n, b.n)
+	c.n.Mod(c.n, field.q)
+}
+
+func (field *ScalarField) Neg(c *ScalarFieldElement, a *ScalarFieldElement) {
+	c.n.Neg(a.n)
+	c.n.Mod(c.n, field.q)
+}
+
 func (field *ScalarField) Mul(c *ScalarFieldElement, a *ScalarFieldElement, b *ScalarFieldElement) {
 	c.n.Mul(a.n, b.n)
 	c.n.Mod(c.n, field.q)
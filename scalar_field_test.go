@@ -0,0 +1,76 @@
+package jubjub
+
+import (
+	"bytes"
+	"crypto/rand"
+	"math/big"
+	"testing"
+)
+
+func randScalar(field *ScalarField) *ScalarFieldElement {
+	e, err := field.RandElement(rand.Reader)
+	if err != nil {
+		panic(err)
+	}
+	return e
+}
+
+func TestScalarFieldToBytes(t *testing.T) {
+	field := NewJubjubScalarField()
+	one := field.ToBytes(field.NewElementFromUint64(1))
+	want := make([]byte, 32)
+	want[0] = 1
+	if !bytes.Equal(one, want) {
+		t.Errorf("bad encoding, have: %x, want: %x", one, want)
+	}
+	for i := 0; i < 100; i++ {
+		a := randScalar(field)
+		b, err := field.FromBytes(field.ToBytes(a))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if a.Big().Cmp(b.Big()) != 0 {
+			t.Errorf("bad encoding, have: %s, want: %s", b.Big(), a.Big())
+		}
+	}
+}
+
+func TestScalarFieldFromBytesNonCanonical(t *testing.T) {
+	field := NewJubjubScalarField()
+	q := field.q.FillBytes(make([]byte, 32))
+	if _, err := field.FromBytes(reverseBytes(q)); err == nil {
+		t.Errorf("expected error for modulus")
+	}
+	qMinus1 := new(big.Int).Sub(field.q, big.NewInt(1)).FillBytes(make([]byte, 32))
+	if _, err := field.FromBytes(reverseBytes(qMinus1)); err != nil {
+		t.Errorf("expected q - 1 to be accepted")
+	}
+	if _, err := field.FromBytes(make([]byte, 31)); err == nil {
+		t.Errorf("expected error for bad input size")
+	}
+}
+
+func TestScalarFieldFromBytesWide(t *testing.T) {
+	field := NewJubjubScalarField()
+	in := bytes.Repeat([]byte{0xff}, 64)
+	want := new(big.Int).Lsh(big.NewInt(1), 512)
+	want.Sub(want, big.NewInt(1))
+	want.Mod(want, field.q)
+	if have := field.FromBytesWide(in).Big(); have.Cmp(want) != 0 {
+		t.Errorf("bad wide reduction, have: %s, want: %s", have, want)
+	}
+}
+
+func TestScalarFieldRandElement(t *testing.T) {
+	field := NewJubjubScalarField()
+	a, err := field.RandElement(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a.Big().Cmp(field.q) >= 0 {
+		t.Errorf("random element is not reduced")
+	}
+	if _, err := field.RandElement(bytes.NewReader(make([]byte, 63))); err == nil {
+		t.Errorf("expected error for short reader")
+	}
+}
@@ -0,0 +1,164 @@
+package schnorr
+
+import (
+	"fmt"
+	"io"
+	"math/big"
+
+	"github.com/kilic/go-jubjub"
+	"github.com/kilic/go-jubjub/poseidon"
+)
+
+// Schnorr signatures with a Poseidon challenge for verification in circuits
+// over the base field of the curve
+//
+// A = [a]G
+// k = H_nonce(0x02 || a || msg) mod r, R = [k]G
+// e = Poseidon(R.x, R.y, A.x, A.y, msg), c = e mod r
+// S = k + c.a mod r
+//
+// Verification equation is [S]G = R + [c]A where R and A are in the prime order subgroup,
+// so [c]A = [e]A and a circuit may multiply by the bits of e without reducing it
+type PoseidonSchnorr struct {
+	curve       *jubjub.Curve
+	field       *jubjub.Field
+	scalarField *jubjub.ScalarField
+	hasher      *poseidon.Poseidon
+}
+
+type PoseidonSignature struct {
+	R *jubjub.AffinePoint
+	S *big.Int
+}
+
+// Intermediate values of the verification of a signature,
+// coordinates and scalars are base field elements
+type Witness struct {
+	Ax, Ay *jubjub.FieldElement
+	Rx, Ry *jubjub.FieldElement
+	Msg    *jubjub.FieldElement
+	// Poseidon output e and the challenge c = e mod r
+	E, C *jubjub.FieldElement
+	S    *jubjub.FieldElement
+	// [S]G
+	SGx, SGy *jubjub.FieldElement
+	// [c]A
+	CAx, CAy *jubjub.FieldElement
+	// R + [c]A
+	RCAx, RCAy *jubjub.FieldElement
+}
+
+// The challenge is Poseidon over the base field of the curve,
+// the BLS12-381 scalar field for Jubjub, of width 6 with the x^5 S-box, 8 full and 60 partial rounds.
+// Round constants and the Cauchy MDS matrix are sampled from Grain LFSR for that field as in poseidon.New,
+// so the instance hashes 5 inputs like circomlib's but with different constants.
+// The scalar field of the curve is expected to be smaller than its base field.
+func NewPoseidonSchnorr(curve *jubjub.Curve) *PoseidonSchnorr {
+	h, _ := poseidon.New(curve.Field(), 6, 8, 60)
+	return &PoseidonSchnorr{
+		curve:       curve,
+		field:       curve.Field(),
+		scalarField: jubjub.NewScalarField(curve.Order()),
+		hasher:      h,
+	}
+}
+
+// Returns a uniformly random secret key and its public key
+func (p *PoseidonSchnorr) GenerateKey(rand io.Reader) (*jubjub.ScalarFieldElement, *jubjub.AffinePoint, error) {
+	a, err := p.scalarField.RandElement(rand)
+	if err != nil {
+		return nil, nil, err
+	}
+	return a, p.PublicKey(a), nil
+}
+
+func (p *PoseidonSchnorr) PublicKey(a *jubjub.ScalarFieldElement) *jubjub.AffinePoint {
+	A := p.curve.NewExtendedPoint()
+	p.curve.MulBase(A, a)
+	return A.ToAffine()
+}
+
+func (p *PoseidonSchnorr) Sign(a *jubjub.ScalarFieldElement, msg *jubjub.FieldElement) *PoseidonSignature {
+	order := p.curve.Order()
+	aBytes := make([]byte, 32)
+	a.Big().FillBytes(aBytes)
+	k := p.scalarField.FromBytesWide(hash(nonceDomain, []byte{0x02}, aBytes, p.field.ToBytes(msg)))
+	R := p.curve.NewExtendedPoint()
+	p.curve.MulBase(R, k)
+	sig := &PoseidonSignature{R: R.ToAffine()}
+	S := p.challenge(sig.R, p.PublicKey(a), msg)
+	S.Mul(S, a.Big())
+	S.Add(S, k.Big())
+	sig.S = S.Mod(S, order)
+	return sig
+}
+
+func (p *PoseidonSchnorr) Verify(A *jubjub.AffinePoint, msg *jubjub.FieldElement, sig *PoseidonSignature) bool {
+	_, ok := p.verify(A, msg, sig)
+	return ok
+}
+
+// Returns the witness of a valid signature for the verification circuit
+func (p *PoseidonSchnorr) Witness(A *jubjub.AffinePoint, msg *jubjub.FieldElement, sig *PoseidonSignature) (*Witness, error) {
+	w, ok := p.verify(A, msg, sig)
+	if !ok {
+		return nil, fmt.Errorf("invalid signature")
+	}
+	return w, nil
+}
+
+func (p *PoseidonSchnorr) verify(A *jubjub.AffinePoint, msg *jubjub.FieldElement, sig *PoseidonSignature) (*Witness, bool) {
+	if sig == nil || sig.R == nil || sig.S == nil {
+		return nil, false
+	}
+	if sig.S.Sign() < 0 || sig.S.Cmp(p.curve.Order()) >= 0 {
+		return nil, false
+	}
+	if !p.curve.AffinePointIsOnCurve(sig.R) || !p.curve.AffinePointIsOnCurve(A) {
+		return nil, false
+	}
+	Ae, Re := A.ToExtended(), sig.R.ToExtended()
+	if !p.curve.IsInSubgroup(Ae) || !p.curve.IsInSubgroup(Re) {
+		return nil, false
+	}
+	e := p.hash(sig.R, A, msg)
+	c := new(big.Int).SetBytes(p.field.ToBytes(e))
+	c.Mod(c, p.curve.Order())
+	left, right := p.curve.NewExtendedPoint(), p.curve.NewExtendedPoint()
+	p.curve.MulBase(left, p.scalarField.NewElementFromBig(sig.S))
+	p.curve.Mul(right, Ae, p.scalarField.NewElementFromBig(c))
+	ca := right.ToAffine()
+	p.curve.Add(right, right, Re)
+	if !left.Eq(right) {
+		return nil, false
+	}
+	sg, rca := left.ToAffine(), right.ToAffine()
+	return &Witness{
+		Ax:   new(jubjub.FieldElement).Set(A.X()),
+		Ay:   new(jubjub.FieldElement).Set(A.Y()),
+		Rx:   new(jubjub.FieldElement).Set(sig.R.X()),
+		Ry:   new(jubjub.FieldElement).Set(sig.R.Y()),
+		Msg:  new(jubjub.FieldElement).Set(msg),
+		E:    e,
+		C:    p.field.NewElement(c.Bytes()),
+		S:    p.field.NewElement(sig.S.Bytes()),
+		SGx:  sg.X(),
+		SGy:  sg.Y(),
+		CAx:  ca.X(),
+		CAy:  ca.Y(),
+		RCAx: rca.X(),
+		RCAy: rca.Y(),
+	}, true
+}
+
+// e = Poseidon(R.x, R.y, A.x, A.y, msg)
+func (p *PoseidonSchnorr) hash(R, A *jubjub.AffinePoint, msg *jubjub.FieldElement) *jubjub.FieldElement {
+	e, _ := p.hasher.Hash([]*jubjub.FieldElement{R.X(), R.Y(), A.X(), A.Y(), msg})
+	return e
+}
+
+// c = e mod r as an integer
+func (p *PoseidonSchnorr) challenge(R, A *jubjub.AffinePoint, msg *jubjub.FieldElement) *big.Int {
+	c := new(big.Int).SetBytes(p.field.ToBytes(p.hash(R, A, msg)))
+	return c.Mod(c, p.curve.Order())
+}
@@ -0,0 +1,110 @@
+package schnorr
+
+import (
+	"crypto/rand"
+	"math/big"
+	"testing"
+
+	"github.com/kilic/go-jubjub"
+)
+
+func TestPoseidonSchnorr(t *testing.T) {
+	for _, curve := range []*jubjub.Curve{jubjub.NewJubjub(), jubjub.NewBandersnatch()} {
+		p := NewPoseidonSchnorr(curve)
+		field := curve.Field()
+		a, A, err := p.GenerateKey(rand.Reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		msg := field.NewElement([]byte{1, 2, 3})
+		sig := p.Sign(a, msg)
+		if !p.Verify(A, msg, sig) {
+			t.Fatalf("signature verification failed")
+		}
+		if again := p.Sign(a, msg); !again.R.Eq(sig.R) || again.S.Cmp(sig.S) != 0 {
+			t.Errorf("signing expected to be deterministic")
+		}
+		if p.Verify(A, field.NewElement([]byte{1, 2, 4}), sig) {
+			t.Errorf("expected verification to fail for another message")
+		}
+		_, B, _ := p.GenerateKey(rand.Reader)
+		if p.Verify(B, msg, sig) {
+			t.Errorf("expected verification to fail for another key")
+		}
+		bad := &PoseidonSignature{R: sig.R, S: new(big.Int).Add(sig.S, curve.Order())}
+		if p.Verify(A, msg, bad) {
+			t.Errorf("expected verification to fail for non canonical s")
+		}
+		// R + (0, -1) is out of the prime order subgroup
+		negOne := curve.NewAffinePoint().Y()
+		field.Neg(negOne, negOne)
+		T := curve.NewAffinePoint().SetCoordinates(new(jubjub.FieldElement), negOne)
+		R := curve.NewExtendedPoint()
+		curve.Add(R, sig.R.ToExtended(), T.ToExtended())
+		if p.Verify(A, msg, &PoseidonSignature{R: R.ToAffine(), S: sig.S}) {
+			t.Errorf("expected verification to fail for r out of subgroup")
+		}
+		if p.Verify(A, msg, nil) {
+			t.Errorf("expected verification to fail for nil signature")
+		}
+	}
+}
+
+func TestPoseidonSchnorrWitness(t *testing.T) {
+	curve := jubjub.NewJubjub()
+	p := NewPoseidonSchnorr(curve)
+	field := curve.Field()
+	a, A, _ := p.GenerateKey(rand.Reader)
+	msg := field.NewElement([]byte{42})
+	sig := p.Sign(a, msg)
+	w, err := p.Witness(A, msg, sig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !w.Ax.Eq(A.X()) || !w.Ay.Eq(A.Y()) || !w.Rx.Eq(sig.R.X()) || !w.Ry.Eq(sig.R.Y()) || !w.Msg.Eq(msg) {
+		t.Errorf("bad public inputs of witness")
+	}
+	e, _ := p.hasher.Hash([]*jubjub.FieldElement{w.Rx, w.Ry, w.Ax, w.Ay, w.Msg})
+	if !w.E.Eq(e) {
+		t.Errorf("bad poseidon output of witness")
+	}
+	toBig := func(a *jubjub.FieldElement) *big.Int {
+		return new(big.Int).SetBytes(field.ToBytes(a))
+	}
+	c := toBig(w.E)
+	if c.Mod(c, curve.Order()).Cmp(toBig(w.C)) != 0 {
+		t.Errorf("bad challenge of witness")
+	}
+	if toBig(w.S).Cmp(sig.S) != 0 {
+		t.Errorf("bad s of witness")
+	}
+	scalarField := jubjub.NewJubjubScalarField()
+	sg, ca, ea := curve.NewExtendedPoint(), curve.NewExtendedPoint(), curve.NewExtendedPoint()
+	curve.MulBase(sg, scalarField.NewElementFromBig(toBig(w.S)))
+	curve.Mul(ca, A.ToExtended(), scalarField.NewElementFromBig(toBig(w.C)))
+	// the unreduced Poseidon output gives the same point
+	curve.Mul(ea, A.ToExtended(), scalarField.NewElementFromBig(toBig(w.E)))
+	if !sg.ToAffine().X().Eq(w.SGx) || !sg.ToAffine().Y().Eq(w.SGy) {
+		t.Errorf("bad [S]G of witness")
+	}
+	if !ca.ToAffine().X().Eq(w.CAx) || !ca.ToAffine().Y().Eq(w.CAy) || !ea.Eq(ca) {
+		t.Errorf("bad [c]A of witness")
+	}
+	if !w.RCAx.Eq(w.SGx) || !w.RCAy.Eq(w.SGy) {
+		t.Errorf("bad R + [c]A of witness")
+	}
+	if _, err := p.Witness(A, field.NewElement([]byte{43}), sig); err == nil {
+		t.Errorf("expected error for invalid signature")
+	}
+}
+
+func BenchmarkPoseidonSchnorrVerify(t *testing.B) {
+	p := NewPoseidonSchnorr(jubjub.NewJubjub())
+	a, A, _ := p.GenerateKey(rand.Reader)
+	msg := p.field.NewElement([]byte{1})
+	sig := p.Sign(a, msg)
+	t.ResetTimer()
+	for i := 0; i < t.N; i++ {
+		p.Verify(A, msg, sig)
+	}
+}
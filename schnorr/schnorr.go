@@ -0,0 +1,153 @@
+// Package schnorr implements Ed25519 style Schnorr signatures over Jubjub.
+//
+// The scheme follows RFC 8032 with Jubjub in place of edwards25519
+// and SHA-512 prefixed with a domain separator as the hash function,
+// H_tag(x) = SHA-512(tag || x) with separate tags for keys, nonces and challenges.
+//
+// a = H_key(0x00 || seed) mod r, prefix = H_key(0x01 || seed)[0:32]
+// A = [a]G
+// k = H_nonce(prefix || M) mod r, R = [k]G
+// S = k + H_challenge(R || A || M).a mod r
+//
+// Signatures are R || S where S is 32 bytes little endian and S < r is enforced.
+// Verification is cofactored, [8]([S]G - R - [H_challenge(R || A || M)]A) = O,
+// so signatures don't depend on small order components of R and A.
+// Public keys of small order, [8]A = O, are rejected.
+package schnorr
+
+import (
+	"crypto/sha512"
+	"fmt"
+	"io"
+
+	"github.com/kilic/go-jubjub"
+)
+
+const (
+	PublicKeySize  = 32
+	PrivateKeySize = 64
+	SignatureSize  = 64
+	SeedSize       = 32
+)
+
+const (
+	keyDomain       = "Jubjub_Schnorr_Key"
+	nonceDomain     = "Jubjub_Schnorr_Nonce"
+	challengeDomain = "Jubjub_Schnorr_Challenge"
+)
+
+var (
+	curve       = jubjub.NewJubjub()
+	scalarField = jubjub.NewJubjubScalarField()
+)
+
+// Compressed point A
+type PublicKey []byte
+
+// Seed followed by the public key
+type PrivateKey []byte
+
+func (priv PrivateKey) Public() PublicKey {
+	pub := make([]byte, PublicKeySize)
+	copy(pub, priv[SeedSize:])
+	return pub
+}
+
+func (priv PrivateKey) Seed() []byte {
+	seed := make([]byte, SeedSize)
+	copy(seed, priv[:SeedSize])
+	return seed
+}
+
+func GenerateKey(rand io.Reader) (PublicKey, PrivateKey, error) {
+	seed := make([]byte, SeedSize)
+	if _, err := io.ReadFull(rand, seed); err != nil {
+		return nil, nil, err
+	}
+	priv, err := NewKeyFromSeed(seed)
+	if err != nil {
+		return nil, nil, err
+	}
+	return priv.Public(), priv, nil
+}
+
+func NewKeyFromSeed(seed []byte) (PrivateKey, error) {
+	if len(seed) != SeedSize {
+		return nil, fmt.Errorf("bad seed size")
+	}
+	A := curve.NewExtendedPoint()
+	curve.MulBase(A, secretScalar(seed))
+	priv := make([]byte, 0, PrivateKeySize)
+	priv = append(priv, seed...)
+	return append(priv, curve.Compress(A.ToAffine())...), nil
+}
+
+// H_tag(x) = SHA-512(tag || x)
+func hash(tag string, in ...[]byte) []byte {
+	h := sha512.New()
+	h.Write([]byte(tag))
+	for _, x := range in {
+		h.Write(x)
+	}
+	return h.Sum(nil)
+}
+
+// LEOS2IP(H_tag(x)) mod r
+func hashToScalar(tag string, in ...[]byte) *jubjub.ScalarFieldElement {
+	return scalarField.FromBytesWide(hash(tag, in...))
+}
+
+func secretScalar(seed []byte) *jubjub.ScalarFieldElement {
+	return hashToScalar(keyDomain, []byte{0x00}, seed)
+}
+
+// Signs msg deterministically, panics if the private key is malformed
+func Sign(priv PrivateKey, msg []byte) []byte {
+	if len(priv) != PrivateKeySize {
+		panic("schnorr: bad private key size")
+	}
+	seed, pub := priv[:SeedSize], priv[SeedSize:]
+	a := secretScalar(seed)
+	prefix := hash(keyDomain, []byte{0x01}, seed)[:32]
+	k := hashToScalar(nonceDomain, prefix, msg)
+	R := curve.NewExtendedPoint()
+	curve.MulBase(R, k)
+	rBar := curve.Compress(R.ToAffine())
+	S := hashToScalar(challengeDomain, rBar, pub, msg)
+	scalarField.Mul(S, S, a)
+	scalarField.Add(S, S, k)
+	return append(rBar, scalarField.ToBytes(S)...)
+}
+
+// Reports whether sig is a valid signature of msg under pub
+func Verify(pub PublicKey, msg, sig []byte) bool {
+	if len(pub) != PublicKeySize || len(sig) != SignatureSize {
+		return false
+	}
+	A, err := curve.NewExtendedPointFromCompressed(pub)
+	if err != nil {
+		return false
+	}
+	cofactor := scalarField.NewElementFromBig(curve.Cofactor())
+	eightA := curve.NewExtendedPoint()
+	curve.Mul(eightA, A, cofactor)
+	if eightA.Eq(curve.NewExtendedPoint()) {
+		return false
+	}
+	R, err := curve.NewExtendedPointFromCompressed(sig[:32])
+	if err != nil {
+		return false
+	}
+	S, err := scalarField.FromBytes(sig[32:])
+	if err != nil {
+		return false
+	}
+	c := hashToScalar(challengeDomain, sig[:32], pub, msg)
+	p, q := curve.NewExtendedPoint(), curve.NewExtendedPoint()
+	curve.MulBase(p, S)
+	curve.Mul(q, A, c)
+	curve.Sub(p, p, q)
+	curve.Sub(p, p, R)
+	curve.Mul(p, p, cofactor)
+	return p.Eq(curve.NewExtendedPoint())
+}
@@ -0,0 +1,160 @@
+package schnorr
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"math/big"
+	"testing"
+
+	"github.com/kilic/go-jubjub"
+)
+
+func fromHex(s string) []byte {
+	out, _ := hex.DecodeString(s)
+	return out
+}
+
+func TestSchnorr(t *testing.T) {
+	pub, priv, err := GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(priv.Public(), pub) {
+		t.Errorf("bad public key")
+	}
+	again, err := NewKeyFromSeed(priv.Seed())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(again, priv) {
+		t.Errorf("bad private key from seed")
+	}
+	msg := []byte("attestation")
+	sig := Sign(priv, msg)
+	if len(sig) != SignatureSize {
+		t.Fatalf("bad signature size, have: %d, want: %d", len(sig), SignatureSize)
+	}
+	if !bytes.Equal(sig, Sign(priv, msg)) {
+		t.Errorf("signing expected to be deterministic")
+	}
+	if !Verify(pub, msg, sig) {
+		t.Errorf("signature verification failed")
+	}
+	if Verify(pub, []byte("attestation!"), sig) {
+		t.Errorf("expected verification to fail for another message")
+	}
+	other, _, _ := GenerateKey(rand.Reader)
+	if Verify(other, msg, sig) {
+		t.Errorf("expected verification to fail for another key")
+	}
+	for i := 0; i < SignatureSize; i++ {
+		bad := append([]byte{}, sig...)
+		bad[i] ^= 1
+		if Verify(pub, msg, bad) {
+			t.Errorf("expected verification to fail for modified byte %d", i)
+		}
+	}
+	if Verify(pub, msg, sig[:63]) {
+		t.Errorf("expected verification to fail for short signature")
+	}
+	if _, err := NewKeyFromSeed(make([]byte, 31)); err == nil {
+		t.Errorf("expected error for bad seed size")
+	}
+}
+
+func TestSchnorrVector(t *testing.T) {
+	// computed with an independent Python implementation following the package doc
+	priv, err := NewKeyFromSeed(make([]byte, SeedSize))
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantPub := "008d98d15873a20f64fd08edf58e42e0d7674ac99c9acde6ed9f78d52a6d6c3b"
+	wantSig := "da9c4faa37d4303511c5bff288cc55df1ec957f1fa490d8f61abb61d1f5f0763ea17c707c114a9e43baf39db4bcf6b2a61cd7d09143963085d22f30b918ff407"
+	if out := hex.EncodeToString(priv.Public()); out != wantPub {
+		t.Errorf("bad public key, have: %s, want: %s", out, wantPub)
+	}
+	if out := hex.EncodeToString(Sign(priv, []byte("abc"))); out != wantSig {
+		t.Errorf("bad signature, have: %s, want: %s", out, wantSig)
+	}
+}
+
+func TestSchnorrCanonical(t *testing.T) {
+	_, priv, _ := GenerateKey(rand.Reader)
+	pub := priv.Public()
+	msg := []byte("canonical")
+	sig := Sign(priv, msg)
+	// S + r is an equivalent but non canonical scalar
+	S, err := scalarField.FromBytes(sig[32:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	be := new(big.Int).Add(S.Big(), curve.Order()).FillBytes(make([]byte, 32))
+	bad := append([]byte{}, sig[:32]...)
+	for i := range be {
+		bad = append(bad, be[31-i])
+	}
+	if Verify(pub, msg, bad) {
+		t.Errorf("expected verification to fail for non canonical s")
+	}
+}
+
+func TestSchnorrCofactor(t *testing.T) {
+	// point of order 8
+	T, err := curve.NewExtendedPointFromUncompressed(fromHex(
+		"71d4df38ba9e7973eaaae086a16618d17aa41ac43dae8582d92e6a7927200d43" +
+			"4958bdb21966982e16a13035ad4d72669106ee90f384a4a1ff0d2068eff496dd"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	seed := make([]byte, SeedSize)
+	a := secretScalar(seed)
+	// A' = [a]G + T has a small order component
+	A := curve.NewExtendedPoint()
+	curve.MulBase(A, a)
+	curve.Add(A, A, T)
+	pub := curve.Compress(A.ToAffine())
+	msg := []byte("cofactor")
+	k := scalarField.NewElementFromBig(big.NewInt(12345))
+	R := curve.NewExtendedPoint()
+	curve.MulBase(R, k)
+	curve.Add(R, R, T)
+	rBar := curve.Compress(R.ToAffine())
+	S := hashToScalar(challengeDomain, rBar, pub, msg)
+	scalarField.Mul(S, S, a)
+	scalarField.Add(S, S, k)
+	sig := append(rBar, scalarField.ToBytes(S)...)
+	if !Verify(pub, msg, sig) {
+		t.Errorf("cofactored verification expected to accept small order components")
+	}
+}
+
+func TestSchnorrSmallOrderKey(t *testing.T) {
+	// point of order 8
+	T, err := curve.NewExtendedPointFromUncompressed(fromHex(
+		"71d4df38ba9e7973eaaae086a16618d17aa41ac43dae8582d92e6a7927200d43" +
+			"4958bdb21966982e16a13035ad4d72669106ee90f384a4a1ff0d2068eff496dd"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// R = [S]G passes the cofactored equation for any message under A of small order
+	S := scalarField.NewElementFromUint64(5)
+	R := curve.NewExtendedPoint()
+	curve.MulBase(R, S)
+	sig := append(curve.Compress(R.ToAffine()), scalarField.ToBytes(S)...)
+	for i, A := range []*jubjub.ExtendedPoint{curve.NewExtendedPoint(), T} {
+		if Verify(curve.Compress(A.ToAffine()), []byte("any"), sig) {
+			t.Errorf("expected small order public key %d to be rejected", i)
+		}
+	}
+}
+
+func BenchmarkSchnorrVerify(t *testing.B) {
+	pub, priv, _ := GenerateKey(rand.Reader)
+	msg := []byte("benchmark")
+	sig := Sign(priv, msg)
+	t.ResetTimer()
+	for i := 0; i < t.N; i++ {
+		Verify(pub, msg, sig)
+	}
+}
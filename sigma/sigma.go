@@ -0,0 +1,386 @@
+// Package sigma implements non-interactive sigma protocols for linear relations
+// over the prime order subgroup of a twisted Edwards curve.
+//
+// A statement is a system of equations P_i = sum_j [w_j] G_ij over secret witness scalars w_j.
+// Schnorr proof of knowledge of discrete logarithm, Chaum-Pedersen proof of equality
+// of discrete logarithms and their AND compositions are statements of this form.
+//
+// Proof of knowledge, the prover commits with T_i = sum_j [k_j] G_ij for random k_j,
+// the challenge c is derived from a Fiat-Shamir transcript of the statement and the commitments
+// and responses are z_j = k_j + c w_j. The verifier checks [h](sum_j [z_j] G_ij - [c] P_i - T_i) = O
+// for the cofactor h, so single and batch verification agree on points with small order components.
+//
+// OR composition follows Proofs of Partial Knowledge, Cramer, Damgard, Schoenmakers.
+// Challenges of the other branches are chosen at random and their transcripts are simulated,
+// the challenge of the known branch is c - sum of the others.
+//
+// Points of statements and proofs are expected to be in the prime order subgroup.
+package sigma
+
+import (
+	"fmt"
+	"io"
+	"math/big"
+
+	"github.com/kilic/go-jubjub"
+	"github.com/kilic/go-jubjub/transcript"
+)
+
+const domain = "go-jubjub_Sigma"
+
+type Sigma struct {
+	curve       *jubjub.Curve
+	scalarField *jubjub.ScalarField
+	// separates challenges of these proofs from other transcripts
+	domain string
+}
+
+// Images_i = sum_j [w_j] Bases_ij, rows of Bases have the length of the witness
+type Statement struct {
+	Bases  [][]*jubjub.ExtendedPoint
+	Images []*jubjub.ExtendedPoint
+}
+
+type Proof struct {
+	// T_i for each equation
+	Commitments []*jubjub.ExtendedPoint
+	// z_j for each witness scalar
+	Responses []*jubjub.ScalarFieldElement
+}
+
+// Proof of knowledge of a witness of one of the statements
+type OrProof struct {
+	Commitments [][]*jubjub.ExtendedPoint
+	Challenges  []*jubjub.ScalarFieldElement
+	Responses   [][]*jubjub.ScalarFieldElement
+}
+
+func New(curve *jubjub.Curve) *Sigma {
+	return &Sigma{
+		curve:       curve,
+		scalarField: jubjub.NewScalarField(curve.Order()),
+		domain:      domain,
+	}
+}
+
+// Knowledge of x such that Y = [x]G
+func (s *Sigma) Schnorr(g, y *jubjub.ExtendedPoint) *Statement {
+	return &Statement{
+		Bases:  [][]*jubjub.ExtendedPoint{{g}},
+		Images: []*jubjub.ExtendedPoint{y},
+	}
+}
+
+// Knowledge of x such that A = [x]G and B = [x]H
+func (s *Sigma) DLEQ(g, a, h, b *jubjub.ExtendedPoint) *Statement {
+	return &Statement{
+		Bases:  [][]*jubjub.ExtendedPoint{{g}, {h}},
+		Images: []*jubjub.ExtendedPoint{a, b},
+	}
+}
+
+// Knowledge of witnesses of all statements, the witness is the concatenation of their witnesses
+func (s *Sigma) And(statements ...*Statement) *Statement {
+	width := 0
+	for _, st := range statements {
+		width += st.width()
+	}
+	r := new(Statement)
+	offset := 0
+	for _, st := range statements {
+		for i, row := range st.Bases {
+			bases := make([]*jubjub.ExtendedPoint, width)
+			for j := range bases {
+				if j >= offset && j < offset+len(row) {
+					bases[j] = row[j-offset]
+				} else {
+					bases[j] = s.curve.NewExtendedPoint()
+				}
+			}
+			r.Bases = append(r.Bases, bases)
+			r.Images = append(r.Images, st.Images[i])
+		}
+		offset += st.width()
+	}
+	return r
+}
+
+func (st *Statement) width() int {
+	if len(st.Bases) == 0 {
+		return 0
+	}
+	return len(st.Bases[0])
+}
+
+func (st *Statement) check() error {
+	if len(st.Bases) == 0 || len(st.Bases) != len(st.Images) || st.width() == 0 {
+		return fmt.Errorf("bad statement size")
+	}
+	for i, row := range st.Bases {
+		if len(row) != st.width() {
+			return fmt.Errorf("bad statement size")
+		}
+		if st.Images[i] == nil {
+			return fmt.Errorf("missing image")
+		}
+		for _, b := range row {
+			if b == nil {
+				return fmt.Errorf("missing base")
+			}
+		}
+	}
+	return nil
+}
+
+func appendStatement(t *transcript.Transcript, st *Statement) {
+	t.AppendUint64("rows", uint64(len(st.Bases)))
+	t.AppendUint64("columns", uint64(st.width()))
+	for i, row := range st.Bases {
+		for _, b := range row {
+			t.AppendPoint("base", b)
+		}
+		t.AppendPoint("image", st.Images[i])
+	}
+}
+
+func (s *Sigma) challenge(label string, statements []*Statement, commitments [][]*jubjub.ExtendedPoint) *jubjub.ScalarFieldElement {
+	t := transcript.New(s.curve, s.domain)
+	t.AppendMessage("label", []byte(label))
+	t.AppendUint64("statements", uint64(len(statements)))
+	for _, st := range statements {
+		appendStatement(t, st)
+	}
+	for _, c := range commitments {
+		for _, p := range c {
+			t.AppendPoint("commitment", p)
+		}
+	}
+	return t.ChallengeScalar("challenge")
+}
+
+// sum_j [a_j] G_ij for each row i
+func (s *Sigma) eval(st *Statement, a []*jubjub.ScalarFieldElement) []*jubjub.ExtendedPoint {
+	r := make([]*jubjub.ExtendedPoint, len(st.Bases))
+	for i, row := range st.Bases {
+		r[i] = s.curve.NewExtendedPoint()
+		s.curve.MultiExp(r[i], row, a)
+	}
+	return r
+}
+
+// sum_j [z_j] G_ij - [c] P_i for each row i, commitments that verify with challenge c
+func (s *Sigma) simulate(st *Statement, c *jubjub.ScalarFieldElement, z []*jubjub.ScalarFieldElement) []*jubjub.ExtendedPoint {
+	negC := s.scalarField.NewElement()
+	s.scalarField.Neg(negC, c)
+	r := make([]*jubjub.ExtendedPoint, len(st.Bases))
+	for i, row := range st.Bases {
+		r[i] = s.curve.NewExtendedPoint()
+		s.curve.MultiExp(r[i], append(append([]*jubjub.ExtendedPoint{}, row...), st.Images[i]), append(append([]*jubjub.ScalarFieldElement{}, z...), negC))
+	}
+	return r
+}
+
+func (s *Sigma) verify(st *Statement, commitments []*jubjub.ExtendedPoint, c *jubjub.ScalarFieldElement, z []*jubjub.ScalarFieldElement) bool {
+	if len(commitments) != len(st.Bases) || len(z) != st.width() || !wellFormed(commitments, z) {
+		return false
+	}
+	cofactor := s.scalarField.NewElementFromBig(s.curve.Cofactor())
+	for i, t := range s.simulate(st, c, z) {
+		s.curve.Sub(t, t, commitments[i])
+		s.curve.Mul(t, t, cofactor)
+		if !t.Eq(s.curve.NewExtendedPoint()) {
+			return false
+		}
+	}
+	return true
+}
+
+func wellFormed(commitments []*jubjub.ExtendedPoint, responses []*jubjub.ScalarFieldElement) bool {
+	for _, t := range commitments {
+		if t == nil {
+			return false
+		}
+	}
+	for _, z := range responses {
+		if z == nil {
+			return false
+		}
+	}
+	return true
+}
+
+func (s *Sigma) checkWitness(st *Statement, witness []*jubjub.ScalarFieldElement) error {
+	if err := st.check(); err != nil {
+		return err
+	}
+	if len(witness) != st.width() {
+		return fmt.Errorf("bad witness size")
+	}
+	for i, p := range s.eval(st, witness) {
+		if !p.Eq(st.Images[i]) {
+			return fmt.Errorf("witness doesn't satisfy the statement")
+		}
+	}
+	return nil
+}
+
+// Proves knowledge of witness of st, label binds the proof to a context
+func (s *Sigma) Prove(rand io.Reader, label string, st *Statement, witness []*jubjub.ScalarFieldElement) (*Proof, error) {
+	if err := s.checkWitness(st, witness); err != nil {
+		return nil, err
+	}
+	k := make([]*jubjub.ScalarFieldElement, len(witness))
+	for j := range k {
+		var err error
+		if k[j], err = s.scalarField.RandElement(rand); err != nil {
+			return nil, err
+		}
+	}
+	proof := &Proof{Commitments: s.eval(st, k)}
+	c := s.challenge(label, []*Statement{st}, [][]*jubjub.ExtendedPoint{proof.Commitments})
+	for j, w := range witness {
+		z := s.scalarField.NewElement()
+		s.scalarField.Mul(z, c, w)
+		s.scalarField.Add(z, z, k[j])
+		proof.Responses = append(proof.Responses, z)
+	}
+	return proof, nil
+}
+
+func (s *Sigma) Verify(label string, st *Statement, proof *Proof) bool {
+	if st.check() != nil || len(proof.Commitments) != len(st.Bases) || !wellFormed(proof.Commitments, proof.Responses) {
+		return false
+	}
+	c := s.challenge(label, []*Statement{st}, [][]*jubjub.ExtendedPoint{proof.Commitments})
+	return s.verify(st, proof.Commitments, c, proof.Responses)
+}
+
+// Verifies many proofs at once checking a random linear combination of all equations
+// sum_k rho_k (sum_j [z_j] G_kj - [c] P_k - T_k) = O in a single multi scalar multiplication.
+// The sum is multiplied by the cofactor as in Verify.
+func (s *Sigma) BatchVerify(rand io.Reader, labels []string, statements []*Statement, proofs []*Proof) bool {
+	if len(labels) != len(statements) || len(statements) != len(proofs) {
+		return false
+	}
+	var points []*jubjub.ExtendedPoint
+	var scalars []*jubjub.ScalarFieldElement
+	var rho [16]byte
+	for k, st := range statements {
+		proof := proofs[k]
+		if st.check() != nil || len(proof.Commitments) != len(st.Bases) || len(proof.Responses) != st.width() {
+			return false
+		}
+		if !wellFormed(proof.Commitments, proof.Responses) {
+			return false
+		}
+		c := s.challenge(labels[k], []*Statement{st}, [][]*jubjub.ExtendedPoint{proof.Commitments})
+		for i, row := range st.Bases {
+			if _, err := io.ReadFull(rand, rho[:]); err != nil {
+				return false
+			}
+			r := s.scalarField.NewElementFromBig(new(big.Int).SetBytes(rho[:]))
+			for j, b := range row {
+				a := s.scalarField.NewElement()
+				s.scalarField.Mul(a, r, proof.Responses[j])
+				points, scalars = append(points, b), append(scalars, a)
+			}
+			a := s.scalarField.NewElement()
+			s.scalarField.Mul(a, r, c)
+			s.scalarField.Neg(a, a)
+			points, scalars = append(points, st.Images[i]), append(scalars, a)
+			a = s.scalarField.NewElement()
+			s.scalarField.Neg(a, r)
+			points, scalars = append(points, proof.Commitments[i]), append(scalars, a)
+		}
+	}
+	r := s.curve.NewExtendedPoint()
+	if err := s.curve.MultiExp(r, points, scalars); err != nil {
+		return false
+	}
+	s.curve.Mul(r, r, s.scalarField.NewElementFromBig(s.curve.Cofactor()))
+	return r.Eq(s.curve.NewExtendedPoint())
+}
+
+// Proves knowledge of witness of statements[index]
+func (s *Sigma) ProveOr(rand io.Reader, label string, statements []*Statement, index int, witness []*jubjub.ScalarFieldElement) (*OrProof, error) {
+	if index < 0 || index >= len(statements) {
+		return nil, fmt.Errorf("bad statement index")
+	}
+	for _, st := range statements {
+		if err := st.check(); err != nil {
+			return nil, err
+		}
+	}
+	if err := s.checkWitness(statements[index], witness); err != nil {
+		return nil, err
+	}
+	n := len(statements)
+	proof := &OrProof{
+		Commitments: make([][]*jubjub.ExtendedPoint, n),
+		Challenges:  make([]*jubjub.ScalarFieldElement, n),
+		Responses:   make([][]*jubjub.ScalarFieldElement, n),
+	}
+	randScalars := func(n int) ([]*jubjub.ScalarFieldElement, error) {
+		r := make([]*jubjub.ScalarFieldElement, n)
+		for j := range r {
+			var err error
+			if r[j], err = s.scalarField.RandElement(rand); err != nil {
+				return nil, err
+			}
+		}
+		return r, nil
+	}
+	sum := s.scalarField.NewElement()
+	for i, st := range statements {
+		if i == index {
+			continue
+		}
+		var err error
+		if proof.Challenges[i], err = s.scalarField.RandElement(rand); err != nil {
+			return nil, err
+		}
+		if proof.Responses[i], err = randScalars(st.width()); err != nil {
+			return nil, err
+		}
+		proof.Commitments[i] = s.simulate(st, proof.Challenges[i], proof.Responses[i])
+		s.scalarField.Add(sum, sum, proof.Challenges[i])
+	}
+	k, err := randScalars(len(witness))
+	if err != nil {
+		return nil, err
+	}
+	proof.Commitments[index] = s.eval(statements[index], k)
+	c := s.challenge(label, statements, proof.Commitments)
+	s.scalarField.Sub(c, c, sum)
+	proof.Challenges[index] = c
+	for j, w := range witness {
+		z := s.scalarField.NewElement()
+		s.scalarField.Mul(z, c, w)
+		s.scalarField.Add(z, z, k[j])
+		proof.Responses[index] = append(proof.Responses[index], z)
+	}
+	return proof, nil
+}
+
+func (s *Sigma) VerifyOr(label string, statements []*Statement, proof *OrProof) bool {
+	n := len(statements)
+	if n == 0 || len(proof.Commitments) != n || len(proof.Challenges) != n || len(proof.Responses) != n {
+		return false
+	}
+	for i, st := range statements {
+		if st.check() != nil || len(proof.Commitments[i]) != len(st.Bases) {
+			return false
+		}
+		if proof.Challenges[i] == nil || !wellFormed(proof.Commitments[i], proof.Responses[i]) {
+			return false
+		}
+	}
+	c := s.challenge(label, statements, proof.Commitments)
+	for i, st := range statements {
+		s.scalarField.Sub(c, c, proof.Challenges[i])
+		if !s.verify(st, proof.Commitments[i], proof.Challenges[i], proof.Responses[i]) {
+			return false
+		}
+	}
+	return c.Big().Sign() == 0
+}
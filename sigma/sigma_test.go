@@ -0,0 +1,257 @@
+package sigma
+
+import (
+	"crypto/rand"
+	"testing"
+
+	"github.com/kilic/go-jubjub"
+)
+
+func randPoint(t *testing.T, s *Sigma) (*jubjub.ScalarFieldElement, *jubjub.ExtendedPoint) {
+	x, err := s.scalarField.RandElement(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := s.curve.NewExtendedPoint()
+	s.curve.MulBase(p, x)
+	return x, p
+}
+
+func mul(s *Sigma, p *jubjub.ExtendedPoint, x *jubjub.ScalarFieldElement) *jubjub.ExtendedPoint {
+	r := s.curve.NewExtendedPoint()
+	s.curve.Mul(r, p, x)
+	return r
+}
+
+func TestSchnorr(t *testing.T) {
+	for _, curve := range []*jubjub.Curve{jubjub.NewJubjub(), jubjub.NewBandersnatch()} {
+		s := New(curve)
+		g := curve.Generator()
+		x, y := randPoint(t, s)
+		st := s.Schnorr(g, y)
+		proof, err := s.Prove(rand.Reader, "schnorr", st, []*jubjub.ScalarFieldElement{x})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !s.Verify("schnorr", st, proof) {
+			t.Errorf("bad proof")
+		}
+		if s.Verify("another", st, proof) {
+			t.Errorf("expected verification to fail for another label")
+		}
+		other := New(curve)
+		other.domain = "another"
+		if other.Verify("schnorr", st, proof) {
+			t.Errorf("expected verification to fail for another domain")
+		}
+		_, y2 := randPoint(t, s)
+		if s.Verify("schnorr", s.Schnorr(g, y2), proof) {
+			t.Errorf("expected verification to fail for another statement")
+		}
+		if _, err := s.Prove(rand.Reader, "schnorr", s.Schnorr(g, y2), []*jubjub.ScalarFieldElement{x}); err == nil {
+			t.Errorf("expected error for bad witness")
+		}
+		if _, err := s.Prove(rand.Reader, "schnorr", st, nil); err == nil {
+			t.Errorf("expected error for bad witness size")
+		}
+	}
+}
+
+func TestDLEQ(t *testing.T) {
+	s := New(jubjub.NewJubjub())
+	g := s.curve.Generator()
+	x, a := randPoint(t, s)
+	_, h := randPoint(t, s)
+	b := mul(s, h, x)
+	st := s.DLEQ(g, a, h, b)
+	proof, err := s.Prove(rand.Reader, "dleq", st, []*jubjub.ScalarFieldElement{x})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(proof.Commitments) != 2 || len(proof.Responses) != 1 {
+		t.Fatalf("bad proof size")
+	}
+	if !s.Verify("dleq", st, proof) {
+		t.Errorf("bad proof")
+	}
+	// log_G(A) != log_H(B')
+	x2, _ := randPoint(t, s)
+	b2 := mul(s, h, x2)
+	if s.Verify("dleq", s.DLEQ(g, a, h, b2), proof) {
+		t.Errorf("expected verification to fail for unequal logarithms")
+	}
+	if _, err := s.Prove(rand.Reader, "dleq", s.DLEQ(g, a, h, b2), []*jubjub.ScalarFieldElement{x}); err == nil {
+		t.Errorf("expected error for unequal logarithms")
+	}
+	bad := &Proof{Commitments: proof.Commitments, Responses: []*jubjub.ScalarFieldElement{x}}
+	if s.Verify("dleq", st, bad) {
+		t.Errorf("expected verification to fail for bad response")
+	}
+}
+
+func TestAnd(t *testing.T) {
+	s := New(jubjub.NewJubjub())
+	g := s.curve.Generator()
+	x1, y1 := randPoint(t, s)
+	x2, a := randPoint(t, s)
+	_, h := randPoint(t, s)
+	st := s.And(s.Schnorr(g, y1), s.DLEQ(g, a, h, mul(s, h, x2)))
+	if len(st.Bases) != 3 || st.width() != 2 {
+		t.Fatalf("bad statement size")
+	}
+	witness := []*jubjub.ScalarFieldElement{x1, x2}
+	proof, err := s.Prove(rand.Reader, "and", st, witness)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !s.Verify("and", st, proof) {
+		t.Errorf("bad proof")
+	}
+	if _, err := s.Prove(rand.Reader, "and", st, []*jubjub.ScalarFieldElement{x2, x1}); err == nil {
+		t.Errorf("expected error for bad witness")
+	}
+	// a statement with witnesses w_0, w_1 such that P = [w_0]G + [w_1]H
+	p := s.curve.NewExtendedPoint()
+	s.curve.Add(p, y1, mul(s, h, x2))
+	pedersen := &Statement{Bases: [][]*jubjub.ExtendedPoint{{g, h}}, Images: []*jubjub.ExtendedPoint{p}}
+	proof, err = s.Prove(rand.Reader, "pedersen", pedersen, witness)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !s.Verify("pedersen", pedersen, proof) {
+		t.Errorf("bad proof of opening")
+	}
+}
+
+func TestOr(t *testing.T) {
+	s := New(jubjub.NewJubjub())
+	g := s.curve.Generator()
+	x, y := randPoint(t, s)
+	_, h := randPoint(t, s)
+	_, other := randPoint(t, s)
+	statements := []*Statement{
+		s.Schnorr(g, other),
+		s.DLEQ(g, y, h, mul(s, h, x)),
+		s.Schnorr(h, other),
+	}
+	proof, err := s.ProveOr(rand.Reader, "or", statements, 1, []*jubjub.ScalarFieldElement{x})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !s.VerifyOr("or", statements, proof) {
+		t.Errorf("bad proof")
+	}
+	if s.VerifyOr("another", statements, proof) {
+		t.Errorf("expected verification to fail for another label")
+	}
+	if s.VerifyOr("or", statements[:2], proof) {
+		t.Errorf("expected verification to fail for another statement")
+	}
+	// challenges don't sum to the challenge
+	c := s.scalarField.NewElement()
+	s.scalarField.Add(c, proof.Challenges[0], s.scalarField.NewElementFromUint64(1))
+	bad := &OrProof{
+		Commitments: proof.Commitments,
+		Challenges:  []*jubjub.ScalarFieldElement{c, proof.Challenges[1], proof.Challenges[2]},
+		Responses:   proof.Responses,
+	}
+	if s.VerifyOr("or", statements, bad) {
+		t.Errorf("expected verification to fail for bad challenges")
+	}
+	if _, err := s.ProveOr(rand.Reader, "or", statements, 0, []*jubjub.ScalarFieldElement{x}); err == nil {
+		t.Errorf("expected error for bad witness")
+	}
+	if _, err := s.ProveOr(rand.Reader, "or", statements, 3, []*jubjub.ScalarFieldElement{x}); err == nil {
+		t.Errorf("expected error for bad index")
+	}
+}
+
+func TestBatchVerify(t *testing.T) {
+	s := New(jubjub.NewJubjub())
+	g := s.curve.Generator()
+	var labels []string
+	var statements []*Statement
+	var proofs []*Proof
+	for i := 0; i < 8; i++ {
+		x, a := randPoint(t, s)
+		_, h := randPoint(t, s)
+		st := s.DLEQ(g, a, h, mul(s, h, x))
+		if i%2 == 0 {
+			st = s.Schnorr(g, a)
+		}
+		proof, err := s.Prove(rand.Reader, "batch", st, []*jubjub.ScalarFieldElement{x})
+		if err != nil {
+			t.Fatal(err)
+		}
+		labels, statements, proofs = append(labels, "batch"), append(statements, st), append(proofs, proof)
+	}
+	if !s.BatchVerify(rand.Reader, labels, statements, proofs) {
+		t.Errorf("bad batch")
+	}
+	labels[3] = "another"
+	if s.BatchVerify(rand.Reader, labels, statements, proofs) {
+		t.Errorf("expected batch verification to fail for another label")
+	}
+	labels[3] = "batch"
+	proofs[5] = &Proof{Commitments: proofs[5].Commitments, Responses: proofs[4].Responses}
+	if s.BatchVerify(rand.Reader, labels, statements, proofs) {
+		t.Errorf("expected batch verification to fail for bad proof")
+	}
+	if s.BatchVerify(rand.Reader, labels[1:], statements, proofs) {
+		t.Errorf("expected batch verification to fail for bad input size")
+	}
+}
+
+func TestTorsion(t *testing.T) {
+	s := New(jubjub.NewJubjub())
+	g := s.curve.Generator()
+	// (0, -1) of order 2
+	negOne := s.curve.NewAffinePoint().Y()
+	s.curve.Field().Neg(negOne, negOne)
+	t2 := s.curve.NewAffinePoint().SetCoordinates(new(jubjub.FieldElement), negOne).ToExtended()
+	for i := 0; i < 8; i++ {
+		// Y = [x]G + T2 with a proof made from x, it verifies depending on the parity of c
+		// unless verification is cofactored
+		x, y := randPoint(t, s)
+		s.curve.Add(y, y, t2)
+		st := s.Schnorr(g, y)
+		k, _ := randPoint(t, s)
+		T := s.curve.NewExtendedPoint()
+		s.curve.MulBase(T, k)
+		c := s.challenge("torsion", []*Statement{st}, [][]*jubjub.ExtendedPoint{{T}})
+		z := s.scalarField.NewElement()
+		s.scalarField.Mul(z, c, x)
+		s.scalarField.Add(z, z, k)
+		proof := &Proof{Commitments: []*jubjub.ExtendedPoint{T}, Responses: []*jubjub.ScalarFieldElement{z}}
+		single := s.Verify("torsion", st, proof)
+		batch := s.BatchVerify(rand.Reader, []string{"torsion"}, []*Statement{st}, []*Proof{proof})
+		if single != batch {
+			t.Fatalf("single and batch verification disagree, single: %v, batch: %v", single, batch)
+		}
+	}
+	if s.Verify("torsion", s.Schnorr(g, g), &Proof{Commitments: []*jubjub.ExtendedPoint{nil}, Responses: []*jubjub.ScalarFieldElement{nil}}) {
+		t.Errorf("expected verification to fail for malformed proof")
+	}
+}
+
+func BenchmarkBatchVerify(t *testing.B) {
+	s := New(jubjub.NewJubjub())
+	g := s.curve.Generator()
+	const n = 64
+	labels := make([]string, n)
+	statements := make([]*Statement, n)
+	proofs := make([]*Proof, n)
+	for i := range proofs {
+		x, _ := s.scalarField.RandElement(rand.Reader)
+		y := s.curve.NewExtendedPoint()
+		s.curve.MulBase(y, x)
+		labels[i], statements[i] = "benchmark", s.Schnorr(g, y)
+		proofs[i], _ = s.Prove(rand.Reader, labels[i], statements[i], []*jubjub.ScalarFieldElement{x})
+	}
+	t.ResetTimer()
+	for i := 0; i < t.N; i++ {
+		if !s.BatchVerify(rand.Reader, labels, statements, proofs) {
+			t.Fatal("bad batch")
+		}
+	}
+}
@@ -0,0 +1,134 @@
+package smt
+
+import (
+	"fmt"
+
+	"github.com/kilic/go-jubjub"
+)
+
+// Siblings of the path from the leaf of a key up to the root,
+// proves inclusion of the key with its value or that its leaf is empty
+type Proof struct {
+	Siblings []*jubjub.FieldElement
+}
+
+// Proof where empty siblings are omitted,
+// bit i of Bitmap is set when the sibling at height i is empty
+type CompressedProof struct {
+	Bitmap   []byte
+	Siblings []*jubjub.FieldElement
+}
+
+// Returns the proof of key against the current root,
+// it is an inclusion proof if the key is in the tree and a non inclusion proof otherwise
+func (t *Tree) Prove(key *jubjub.FieldElement) (*Proof, error) {
+	bits, err := t.keyBits(key)
+	if err != nil {
+		return nil, err
+	}
+	siblings := make([]*jubjub.FieldElement, t.depth)
+	node := t.root
+	for h := t.depth; h > 0; h-- {
+		left, right, err := t.children(node, h)
+		if err != nil {
+			return nil, err
+		}
+		if bit(bits, h-1) == 0 {
+			node, siblings[h-1] = left, right
+		} else {
+			node, siblings[h-1] = right, left
+		}
+	}
+	for i := range siblings {
+		siblings[i] = new(jubjub.FieldElement).Set(siblings[i])
+	}
+	return &Proof{Siblings: siblings}, nil
+}
+
+// Reports whether proof shows that key has value under root
+func (t *Tree) VerifyInclusion(root, key, value *jubjub.FieldElement, proof *Proof) bool {
+	if key == nil || value == nil {
+		return false
+	}
+	return t.verify(root, key, t.hash(key, value), proof)
+}
+
+// Reports whether proof shows that key is not in the tree of root
+func (t *Tree) VerifyNonInclusion(root, key *jubjub.FieldElement, proof *Proof) bool {
+	return t.verify(root, key, t.empty[0], proof)
+}
+
+func (t *Tree) verify(root, key, leaf *jubjub.FieldElement, proof *Proof) bool {
+	if root == nil || key == nil || leaf == nil || !t.wellFormed(proof) {
+		return false
+	}
+	bits, err := t.keyBits(key)
+	if err != nil {
+		return false
+	}
+	node := leaf
+	for h := 0; h < t.depth; h++ {
+		if bit(bits, h) == 0 {
+			node = t.hash(node, proof.Siblings[h])
+		} else {
+			node = t.hash(proof.Siblings[h], node)
+		}
+	}
+	return node.Eq(root)
+}
+
+// Reports whether proof has a sibling for each height
+func (t *Tree) wellFormed(proof *Proof) bool {
+	if proof == nil || len(proof.Siblings) != t.depth {
+		return false
+	}
+	for _, sibling := range proof.Siblings {
+		if sibling == nil {
+			return false
+		}
+	}
+	return true
+}
+
+func (t *Tree) Compress(proof *Proof) *CompressedProof {
+	c := &CompressedProof{Bitmap: make([]byte, (t.depth+7)/8)}
+	for h, sibling := range proof.Siblings {
+		if sibling.Eq(t.empty[h]) {
+			c.Bitmap[h/8] |= 1 << uint(h%8)
+		} else {
+			c.Siblings = append(c.Siblings, new(jubjub.FieldElement).Set(sibling))
+		}
+	}
+	return c
+}
+
+func (t *Tree) Decompress(c *CompressedProof) (*Proof, error) {
+	if len(c.Bitmap) != (t.depth+7)/8 {
+		return nil, fmt.Errorf("bad bitmap size")
+	}
+	for h := t.depth; h < 8*len(c.Bitmap); h++ {
+		if (c.Bitmap[h/8]>>uint(h%8))&1 == 1 {
+			return nil, fmt.Errorf("bad bitmap padding")
+		}
+	}
+	proof := &Proof{Siblings: make([]*jubjub.FieldElement, t.depth)}
+	j := 0
+	for h := range proof.Siblings {
+		if (c.Bitmap[h/8]>>uint(h%8))&1 == 1 {
+			proof.Siblings[h] = new(jubjub.FieldElement).Set(t.empty[h])
+			continue
+		}
+		if j == len(c.Siblings) {
+			return nil, fmt.Errorf("too few siblings")
+		}
+		if c.Siblings[j] == nil {
+			return nil, fmt.Errorf("missing sibling")
+		}
+		proof.Siblings[h] = new(jubjub.FieldElement).Set(c.Siblings[j])
+		j++
+	}
+	if j != len(c.Siblings) {
+		return nil, fmt.Errorf("too many siblings")
+	}
+	return proof, nil
+}
@@ -0,0 +1,224 @@
+// Package smt implements a sparse Merkle tree over jubjub.Field
+// keyed by field elements with Poseidon as the node hash.
+//
+// The tree has a fixed depth, the leaf of a key is at the position given by its
+// low depth bits, so a key either has its own leaf or its position is empty.
+// Leaves are h(key, value), internal nodes are h(left, right)
+// and empty subtrees are e_0 = 0, e_{i+1} = h(e_i, e_i).
+// Field elements are kept in Montgomery representation throughout.
+package smt
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+
+	"github.com/kilic/go-jubjub"
+	"github.com/kilic/go-jubjub/poseidon"
+)
+
+type Tree struct {
+	hasher  *poseidon.Poseidon
+	field   *jubjub.Field
+	storage Storage
+	depth   int
+	// empty[i] is the root of an empty subtree of height i
+	empty []*jubjub.FieldElement
+	root  *jubjub.FieldElement
+}
+
+// Key value pair of a batched update, nil Value deletes the key
+type Entry struct {
+	Key   *jubjub.FieldElement
+	Value *jubjub.FieldElement
+}
+
+type entry struct {
+	// big endian bytes of the key out of Montgomery domain
+	bits  []byte
+	key   *jubjub.FieldElement
+	value *jubjub.FieldElement
+}
+
+// Returns an empty tree, hasher is expected to be of width 3
+// and keys of the tree must be less than 2^depth
+func New(hasher *poseidon.Poseidon, storage Storage, depth int) (*Tree, error) {
+	if hasher.Width() != 3 {
+		return nil, fmt.Errorf("hasher must be of width 3")
+	}
+	if depth < 1 || depth > hasher.Field().Modulus().BitLen() {
+		return nil, fmt.Errorf("bad tree depth")
+	}
+	t := &Tree{
+		hasher:  hasher,
+		field:   hasher.Field(),
+		storage: storage,
+		depth:   depth,
+		empty:   make([]*jubjub.FieldElement, depth+1),
+	}
+	t.empty[0] = new(jubjub.FieldElement)
+	for i := 0; i < depth; i++ {
+		t.empty[i+1] = t.hash(t.empty[i], t.empty[i])
+	}
+	t.root = t.empty[depth]
+	return t, nil
+}
+
+func (t *Tree) hash(a, b *jubjub.FieldElement) *jubjub.FieldElement {
+	out, _ := t.hasher.Hash([]*jubjub.FieldElement{a, b})
+	return out
+}
+
+func (t *Tree) Depth() int {
+	return t.depth
+}
+
+func (t *Tree) Root() *jubjub.FieldElement {
+	return new(jubjub.FieldElement).Set(t.root)
+}
+
+// Switches to a root of this tree stored earlier, nodes are read from the storage on demand
+func (t *Tree) SetRoot(root *jubjub.FieldElement) {
+	t.root = new(jubjub.FieldElement).Set(root)
+}
+
+// Returns root of an empty subtree of the given height
+func (t *Tree) EmptyRoot(height int) *jubjub.FieldElement {
+	return new(jubjub.FieldElement).Set(t.empty[height])
+}
+
+func (t *Tree) keyBits(key *jubjub.FieldElement) ([]byte, error) {
+	bits := t.field.ToBytes(key)
+	for i := t.depth; i < 8*len(bits); i++ {
+		if bit(bits, i) == 1 {
+			return nil, fmt.Errorf("key is out of range")
+		}
+	}
+	return bits, nil
+}
+
+// i-th least significant bit of big endian bytes
+func bit(bits []byte, i int) byte {
+	return (bits[len(bits)-1-i/8] >> uint(i%8)) & 1
+}
+
+// Returns children of a node at height, children of empty subtrees are empty
+func (t *Tree) children(node *jubjub.FieldElement, height int) (*jubjub.FieldElement, *jubjub.FieldElement, error) {
+	if node.Eq(t.empty[height]) {
+		return t.empty[height-1], t.empty[height-1], nil
+	}
+	n, err := t.storage.Get(node)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &n.Left, &n.Right, nil
+}
+
+// Returns the value of key, found is false if key is not in the tree
+func (t *Tree) Get(key *jubjub.FieldElement) (value *jubjub.FieldElement, found bool, err error) {
+	bits, err := t.keyBits(key)
+	if err != nil {
+		return nil, false, err
+	}
+	node := t.root
+	for h := t.depth; h > 0; h-- {
+		if node.Eq(t.empty[h]) {
+			return nil, false, nil
+		}
+		left, right, err := t.children(node, h)
+		if err != nil {
+			return nil, false, err
+		}
+		if bit(bits, h-1) == 0 {
+			node = left
+		} else {
+			node = right
+		}
+	}
+	if node.Eq(t.empty[0]) {
+		return nil, false, nil
+	}
+	leaf, err := t.storage.Get(node)
+	if err != nil {
+		return nil, false, err
+	}
+	if !leaf.Left.Eq(key) {
+		return nil, false, fmt.Errorf("leaf key mismatch")
+	}
+	return new(jubjub.FieldElement).Set(&leaf.Right), true, nil
+}
+
+func (t *Tree) Set(key, value *jubjub.FieldElement) error {
+	return t.Update([]*Entry{{Key: key, Value: value}})
+}
+
+func (t *Tree) Delete(key *jubjub.FieldElement) error {
+	return t.Update([]*Entry{{Key: key}})
+}
+
+// Applies entries in a single pass where every affected node is hashed once.
+// If a key appears more than once the last entry wins.
+// The root is left unchanged if any of the keys is out of range or storage fails.
+func (t *Tree) Update(entries []*Entry) error {
+	if len(entries) == 0 {
+		return nil
+	}
+	es := make([]*entry, len(entries))
+	for i, e := range entries {
+		bits, err := t.keyBits(e.Key)
+		if err != nil {
+			return err
+		}
+		es[i] = &entry{bits: bits, key: e.Key, value: e.Value}
+	}
+	// ordering by key is the ordering of leaves
+	sort.SliceStable(es, func(i, j int) bool {
+		return bytes.Compare(es[i].bits, es[j].bits) < 0
+	})
+	root, err := t.update(t.root, t.depth, es)
+	if err != nil {
+		return err
+	}
+	t.root = root
+	return nil
+}
+
+// Returns the new root of the subtree at height of node, all entries are in this subtree
+func (t *Tree) update(node *jubjub.FieldElement, height int, entries []*entry) (*jubjub.FieldElement, error) {
+	if height == 0 {
+		e := entries[len(entries)-1]
+		if e.value == nil {
+			return t.empty[0], nil
+		}
+		leaf := t.hash(e.key, e.value)
+		if err := t.storage.Put(leaf, &Node{Left: *e.key, Right: *e.value}); err != nil {
+			return nil, err
+		}
+		return leaf, nil
+	}
+	left, right, err := t.children(node, height)
+	if err != nil {
+		return nil, err
+	}
+	split := sort.Search(len(entries), func(i int) bool {
+		return bit(entries[i].bits, height-1) == 1
+	})
+	if split > 0 {
+		if left, err = t.update(left, height-1, entries[:split]); err != nil {
+			return nil, err
+		}
+	}
+	if split < len(entries) {
+		if right, err = t.update(right, height-1, entries[split:]); err != nil {
+			return nil, err
+		}
+	}
+	if left.Eq(t.empty[height-1]) && right.Eq(t.empty[height-1]) {
+		return t.empty[height], nil
+	}
+	parent := t.hash(left, right)
+	if err := t.storage.Put(parent, &Node{Left: *left, Right: *right}); err != nil {
+		return nil, err
+	}
+	return parent, nil
+}
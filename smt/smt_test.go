@@ -0,0 +1,228 @@
+package smt
+
+import (
+	"math/big"
+	"math/rand"
+	"testing"
+
+	"github.com/kilic/go-jubjub"
+	"github.com/kilic/go-jubjub/poseidon"
+)
+
+func fromUint(field *jubjub.Field, a uint64) *jubjub.FieldElement {
+	return field.NewElement(new(big.Int).SetUint64(a).Bytes())
+}
+
+func newTestTree(t testing.TB, depth int) *Tree {
+	tree, err := New(poseidon.NewWidth3(), NewMemoryStorage(), depth)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tree
+}
+
+// root of the tree of depth 8 computed over all of its leaves
+func fullRoot(tree *Tree, values map[uint64]uint64) *jubjub.FieldElement {
+	field := tree.field
+	level := make([]*jubjub.FieldElement, 1<<8)
+	for i := range level {
+		level[i] = new(jubjub.FieldElement)
+		if v, ok := values[uint64(i)]; ok {
+			level[i] = tree.hash(fromUint(field, uint64(i)), fromUint(field, v))
+		}
+	}
+	for len(level) > 1 {
+		next := make([]*jubjub.FieldElement, len(level)/2)
+		for i := range next {
+			next[i] = tree.hash(level[2*i], level[2*i+1])
+		}
+		level = next
+	}
+	return level[0]
+}
+
+func TestSparseMerkleTree(t *testing.T) {
+	tree := newTestTree(t, 8)
+	field := tree.field
+	if !tree.Root().Eq(fullRoot(tree, nil)) {
+		t.Fatalf("bad empty root")
+	}
+	r := rand.New(rand.NewSource(1))
+	values := make(map[uint64]uint64)
+	for i := 0; i < 40; i++ {
+		k, v := uint64(r.Intn(256)), r.Uint64()
+		values[k] = v
+		if err := tree.Set(fromUint(field, k), fromUint(field, v)); err != nil {
+			t.Fatal(err)
+		}
+		if !tree.Root().Eq(fullRoot(tree, values)) {
+			t.Fatalf("bad root after set %d", i)
+		}
+	}
+	for k := uint64(0); k < 256; k++ {
+		value, found, err := tree.Get(fromUint(field, k))
+		if err != nil {
+			t.Fatal(err)
+		}
+		v, ok := values[k]
+		if found != ok || (ok && !value.Eq(fromUint(field, v))) {
+			t.Errorf("bad value %d, found: %v", k, found)
+		}
+	}
+	old := tree.Root()
+	for k := range values {
+		if r.Intn(2) == 0 {
+			delete(values, k)
+			if err := tree.Delete(fromUint(field, k)); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	if !tree.Root().Eq(fullRoot(tree, values)) {
+		t.Errorf("bad root after delete")
+	}
+	// earlier versions are still readable
+	tree.SetRoot(old)
+	for k := range values {
+		if _, found, err := tree.Get(fromUint(field, k)); err != nil || !found {
+			t.Errorf("bad value %d in earlier version", k)
+		}
+	}
+	if err := tree.Set(fromUint(field, 256), fromUint(field, 1)); err == nil {
+		t.Errorf("expected error for key out of range")
+	}
+	if _, err := New(poseidon.NewWidth5(), NewMemoryStorage(), 8); err == nil {
+		t.Errorf("expected error for hasher width")
+	}
+	if _, err := New(poseidon.NewWidth3(), NewMemoryStorage(), 256); err == nil {
+		t.Errorf("expected error for tree depth")
+	}
+}
+
+func TestSparseMerkleTreeBatch(t *testing.T) {
+	tree := newTestTree(t, 8)
+	field := tree.field
+	r := rand.New(rand.NewSource(2))
+	values := make(map[uint64]uint64)
+	for round := 0; round < 4; round++ {
+		var entries []*Entry
+		for i := 0; i < 30; i++ {
+			k := uint64(r.Intn(256))
+			if r.Intn(4) == 0 {
+				delete(values, k)
+				entries = append(entries, &Entry{Key: fromUint(field, k)})
+				continue
+			}
+			v := r.Uint64()
+			values[k] = v
+			entries = append(entries, &Entry{Key: fromUint(field, k), Value: fromUint(field, v)})
+		}
+		if err := tree.Update(entries); err != nil {
+			t.Fatal(err)
+		}
+		if !tree.Root().Eq(fullRoot(tree, values)) {
+			t.Fatalf("bad root after batch %d", round)
+		}
+	}
+	var entries []*Entry
+	for k := range values {
+		entries = append(entries, &Entry{Key: fromUint(field, k)})
+	}
+	root := tree.Root()
+	bad := append(entries, &Entry{Key: fromUint(field, 1000)})
+	if err := tree.Update(bad); err == nil {
+		t.Errorf("expected error for key out of range")
+	}
+	if !tree.Root().Eq(root) {
+		t.Errorf("root expected to be unchanged after failed update")
+	}
+	if err := tree.Update(entries); err != nil {
+		t.Fatal(err)
+	}
+	if !tree.Root().Eq(tree.EmptyRoot(8)) {
+		t.Errorf("bad root after deleting all keys")
+	}
+}
+
+func TestSparseMerkleTreeProof(t *testing.T) {
+	for _, depth := range []int{8, 255} {
+		tree := newTestTree(t, depth)
+		field := tree.field
+		var entries []*Entry
+		for _, k := range []uint64{3, 4, 200} {
+			entries = append(entries, &Entry{Key: fromUint(field, k), Value: fromUint(field, k+1)})
+		}
+		if err := tree.Update(entries); err != nil {
+			t.Fatal(err)
+		}
+		root := tree.Root()
+		for k := uint64(0); k < 8; k++ {
+			key := fromUint(field, k)
+			proof, err := tree.Prove(key)
+			if err != nil {
+				t.Fatal(err)
+			}
+			included := k == 3 || k == 4
+			if tree.VerifyInclusion(root, key, fromUint(field, k+1), proof) != included {
+				t.Errorf("bad inclusion proof %d at depth %d", k, depth)
+			}
+			if tree.VerifyNonInclusion(root, key, proof) == included {
+				t.Errorf("bad non inclusion proof %d at depth %d", k, depth)
+			}
+			if tree.VerifyInclusion(root, key, fromUint(field, k+2), proof) {
+				t.Errorf("expected inclusion proof %d with wrong value to fail", k)
+			}
+			c := tree.Compress(proof)
+			if len(c.Siblings) > 3 {
+				t.Errorf("bad number of compressed siblings %d, have: %d", k, len(c.Siblings))
+			}
+			decompressed, err := tree.Decompress(c)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for i := range proof.Siblings {
+				if !proof.Siblings[i].Eq(decompressed.Siblings[i]) {
+					t.Fatalf("bad decompressed sibling %d of proof %d", i, k)
+				}
+			}
+		}
+		proof, _ := tree.Prove(fromUint(field, 3))
+		c := tree.Compress(proof)
+		c.Siblings = c.Siblings[1:]
+		if _, err := tree.Decompress(c); err == nil {
+			t.Errorf("expected error for missing siblings")
+		}
+		c = tree.Compress(proof)
+		c.Siblings[0] = nil
+		if _, err := tree.Decompress(c); err == nil {
+			t.Errorf("expected error for nil sibling")
+		}
+		proof.Siblings[depth-1] = nil
+		if tree.VerifyInclusion(root, fromUint(field, 3), fromUint(field, 4), proof) {
+			t.Errorf("expected proof with nil sibling to fail")
+		}
+		proof.Siblings = proof.Siblings[1:]
+		if tree.VerifyInclusion(root, fromUint(field, 3), fromUint(field, 4), proof) {
+			t.Errorf("expected short proof to fail")
+		}
+		if tree.VerifyNonInclusion(root, fromUint(field, 5), nil) || tree.VerifyInclusion(root, fromUint(field, 3), nil, proof) {
+			t.Errorf("expected malformed input to fail")
+		}
+	}
+}
+
+func BenchmarkSparseMerkleTreeUpdate(t *testing.B) {
+	tree := newTestTree(t, 64)
+	field := tree.field
+	r := rand.New(rand.NewSource(3))
+	entries := make([]*Entry, 100)
+	for i := range entries {
+		entries[i] = &Entry{Key: fromUint(field, r.Uint64()), Value: fromUint(field, r.Uint64())}
+	}
+	t.ResetTimer()
+	for i := 0; i < t.N; i++ {
+		if err := tree.Update(entries); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,61 @@
+package smt
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/kilic/go-jubjub"
+)
+
+// Non empty node of the tree,
+// children of an internal node or key and value of a leaf
+type Node struct {
+	Left  jubjub.FieldElement
+	Right jubjub.FieldElement
+}
+
+// Storage maps hashes of non empty nodes to their contents.
+// Hashes and contents are in Montgomery representation.
+// Nodes are never removed by the tree so roots of earlier versions stay readable.
+type Storage interface {
+	Get(hash *jubjub.FieldElement) (*Node, error)
+	Put(hash *jubjub.FieldElement, node *Node) error
+}
+
+// In memory Storage, safe for concurrent use
+type MemoryStorage struct {
+	mu    sync.RWMutex
+	nodes map[jubjub.FieldElement]Node
+}
+
+var _ Storage = (*MemoryStorage)(nil)
+
+func NewMemoryStorage() *MemoryStorage {
+	return &MemoryStorage{
+		nodes: make(map[jubjub.FieldElement]Node),
+	}
+}
+
+func (m *MemoryStorage) Get(hash *jubjub.FieldElement) (*Node, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	node, ok := m.nodes[*hash]
+	if !ok {
+		return nil, fmt.Errorf("node not found")
+	}
+	return &node, nil
+}
+
+func (m *MemoryStorage) Put(hash *jubjub.FieldElement, node *Node) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.nodes[*hash] = *node
+	return nil
+}
+
+// Returns the number of stored nodes
+func (m *MemoryStorage) Len() int {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return len(m.nodes)
+}
@@ -0,0 +1,123 @@
+// Package transcript implements a Merlin style transcript for Fiat-Shamir transformations
+// of protocols over a twisted Edwards curve.
+//
+// The state is a BLAKE2b-512 hash chain starting from zero bytes
+// and the protocol label under "go-jubjub transcript v1", followed by the curve
+// as the compressed generator and 32 bytes little endian order under "curve".
+// Every operation absorbs
+// state || op || LE32(len(label)) || label || LE32(len(msg)) || msg
+// so messages are unambiguously framed and bound to their labels.
+// Challenges of n bytes are squeezed in blocks i = 0, 1, ... absorbing LE32(n) || LE32(i)
+// as the message and the state is then ratcheted forward with the challenge.
+//
+// Points are appended in 32 bytes compressed encoding,
+// scalars and base field elements in 32 bytes little endian.
+// Challenge scalars are 64 bytes reduced into the scalar field so they are close to uniform.
+package transcript
+
+import (
+	"encoding/binary"
+
+	"github.com/kilic/go-jubjub"
+	"golang.org/x/crypto/blake2b"
+)
+
+const (
+	opDomain byte = iota
+	opAppend
+	opChallenge
+	opRatchet
+)
+
+type Transcript struct {
+	curve       *jubjub.Curve
+	scalarField *jubjub.ScalarField
+	state       [64]byte
+}
+
+// Returns a transcript for protocols over curve, label separates protocols
+func New(curve *jubjub.Curve, label string) *Transcript {
+	t := &Transcript{
+		curve:       curve,
+		scalarField: jubjub.NewScalarField(curve.Order()),
+	}
+	t.state = t.absorb(opDomain, []byte("go-jubjub transcript v1"), []byte(label))
+	order := curve.Order().FillBytes(make([]byte, 32))
+	t.state = t.absorb(opDomain, []byte("curve"), append(curve.Compress(curve.Generator().ToAffine()), reverse(order)...))
+	return t
+}
+
+func frame(op byte, label, msg []byte) []byte {
+	out := make([]byte, 0, 9+len(label)+len(msg))
+	out = append(out, op)
+	out = binary.LittleEndian.AppendUint32(out, uint32(len(label)))
+	out = append(out, label...)
+	out = binary.LittleEndian.AppendUint32(out, uint32(len(msg)))
+	return append(out, msg...)
+}
+
+func (t *Transcript) absorb(op byte, label, msg []byte) [64]byte {
+	h, _ := blake2b.New512(nil)
+	h.Write(t.state[:])
+	h.Write(frame(op, label, msg))
+	var out [64]byte
+	copy(out[:], h.Sum(nil))
+	return out
+}
+
+// Returns an independent copy of t
+func (t *Transcript) Clone() *Transcript {
+	c := *t
+	return &c
+}
+
+func (t *Transcript) AppendMessage(label string, msg []byte) {
+	t.state = t.absorb(opAppend, []byte(label), msg)
+}
+
+func (t *Transcript) AppendUint64(label string, a uint64) {
+	var b [8]byte
+	binary.LittleEndian.PutUint64(b[:], a)
+	t.AppendMessage(label, b[:])
+}
+
+func (t *Transcript) AppendPoint(label string, p *jubjub.ExtendedPoint) {
+	t.AppendMessage(label, t.curve.Compress(p.ToAffine()))
+}
+
+func (t *Transcript) AppendScalar(label string, s *jubjub.ScalarFieldElement) {
+	t.AppendMessage(label, t.scalarField.ToBytes(s))
+}
+
+func (t *Transcript) AppendFieldElement(label string, e *jubjub.FieldElement) {
+	t.AppendMessage(label, reverse(t.curve.Field().ToBytes(e)))
+}
+
+// Returns n bytes derived from the transcript and binds them to it
+func (t *Transcript) ChallengeBytes(label string, n int) []byte {
+	out := make([]byte, 0, n+63)
+	for i := uint32(0); len(out) < n; i++ {
+		var b [8]byte
+		binary.LittleEndian.PutUint32(b[:4], uint32(n))
+		binary.LittleEndian.PutUint32(b[4:], i)
+		block := t.absorb(opChallenge, []byte(label), b[:])
+		out = append(out, block[:]...)
+	}
+	out = out[:n]
+	t.state = t.absorb(opRatchet, []byte(label), out)
+	return out
+}
+
+// Returns a scalar from 64 challenge bytes in little endian reduced modulo the group order
+func (t *Transcript) ChallengeScalar(label string) *jubjub.ScalarFieldElement {
+	return t.scalarField.FromBytesWide(t.ChallengeBytes(label, 64))
+}
+
+// Returns a reversed copy of in
+func reverse(in []byte) []byte {
+	out := make([]byte, len(in))
+	for i := range in {
+		out[len(in)-1-i] = in[i]
+	}
+	return out
+}
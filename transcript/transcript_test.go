@@ -0,0 +1,109 @@
+package transcript
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+
+	"github.com/kilic/go-jubjub"
+)
+
+// Inputs of the transcript built in TestTranscript
+type transcriptInput struct {
+	curve      *jubjub.Curve
+	protocol   string
+	msgLabel   string
+	msg        string
+	pointLabel string
+}
+
+func (in transcriptInput) build() *Transcript {
+	scalarField := jubjub.NewScalarField(in.curve.Order())
+	p := in.curve.NewExtendedPoint()
+	in.curve.MulBase(p, scalarField.NewElementFromUint64(7))
+	tr := New(in.curve, in.protocol)
+	tr.AppendMessage(in.msgLabel, []byte(in.msg))
+	tr.AppendUint64("n", 42)
+	tr.AppendPoint(in.pointLabel, p)
+	tr.AppendScalar("scalar", scalarField.NewElementFromUint64(3))
+	tr.AppendFieldElement("field", in.curve.Field().NewElement([]byte{5}))
+	return tr
+}
+
+func TestTranscript(t *testing.T) {
+	curve := jubjub.NewJubjub()
+	input := transcriptInput{curve, "test", "msg", "hello", "point"}
+	c1, c2 := input.build().ChallengeScalar("c"), input.build().ChallengeScalar("c")
+	if c1.Big().Cmp(c2.Big()) != 0 {
+		t.Fatalf("expected challenges to be deterministic")
+	}
+	if c1.Big().Cmp(curve.Order()) >= 0 {
+		t.Errorf("challenge is not reduced")
+	}
+	tr := New(curve, "vector")
+	tr.AppendMessage("msg", []byte("hello"))
+	tr.AppendUint64("n", 42)
+	// computed with hashlib.blake2b following the framing in the package doc
+	want := "46ee5ec1be4d1c5e5b6da07e7a5b2fc19d6e8c898228c3f4c81a9d4d5f8045e5b510d4511d1fa37f955eb03865206864851a535f34fa6ae575770356d6e3dd6bce1d9ec9d7a814ab682a21c8ab08632a"
+	if have := hex.EncodeToString(tr.ChallengeBytes("c", 80)); have != want {
+		t.Errorf("bad challenge bytes, have: %s, want: %s", have, want)
+	}
+	want = "fcf2a21e7e98e8cad17d6d52409c2bc2"
+	if have := hex.EncodeToString(tr.ChallengeBytes("d", 16)); have != want {
+		t.Errorf("bad challenge bytes after ratchet, have: %s, want: %s", have, want)
+	}
+
+	// each input differs from input in one field only
+	other := map[string]transcriptInput{}
+	other["protocol label"] = transcriptInput{curve, "other", "msg", "hello", "point"}
+	other["message label"] = transcriptInput{curve, "test", "msg2", "hello", "point"}
+	other["point label"] = transcriptInput{curve, "test", "msg", "hello", "point2"}
+	// framing of labels and messages is unambiguous
+	other["framing"] = transcriptInput{curve, "test", "msgh", "ello", "point"}
+	other["curve"] = transcriptInput{jubjub.NewBandersnatch(), "test", "msg", "hello", "point"}
+	for name, in := range other {
+		if in.build().ChallengeScalar("c").Big().Cmp(c1.Big()) == 0 {
+			t.Errorf("expected challenges to differ for another %s", name)
+		}
+	}
+	// the curve is bound even when nothing curve specific is appended
+	if bytes.Equal(New(curve, "test").ChallengeBytes("c", 32), New(jubjub.NewBandersnatch(), "test").ChallengeBytes("c", 32)) {
+		t.Errorf("expected challenges to differ for another curve without points")
+	}
+	// challenges with other labels and successive challenges differ
+	if input.build().ChallengeScalar("d").Big().Cmp(c1.Big()) == 0 {
+		t.Errorf("expected challenges to differ for another challenge label")
+	}
+	tr = input.build()
+	a, b := tr.ChallengeBytes("c", 32), tr.ChallengeBytes("c", 32)
+	if bytes.Equal(a, b) {
+		t.Errorf("expected successive challenges to differ")
+	}
+}
+
+func TestTranscriptClone(t *testing.T) {
+	curve := jubjub.NewJubjub()
+	tr := New(curve, "clone")
+	tr.AppendMessage("a", []byte{1})
+	c := tr.Clone()
+	tr.AppendMessage("b", []byte{2})
+	c.AppendMessage("b", []byte{2})
+	if !bytes.Equal(tr.ChallengeBytes("c", 16), c.ChallengeBytes("c", 16)) {
+		t.Errorf("bad clone")
+	}
+	c.AppendMessage("d", []byte{3})
+	tr.AppendMessage("d", []byte{4})
+	if bytes.Equal(tr.ChallengeBytes("c", 16), c.ChallengeBytes("c", 16)) {
+		t.Errorf("expected clone to be independent")
+	}
+}
+
+func BenchmarkChallengeScalar(t *testing.B) {
+	curve := jubjub.NewJubjub()
+	tr := New(curve, "benchmark")
+	p := curve.Generator()
+	for i := 0; i < t.N; i++ {
+		tr.AppendPoint("point", p)
+		tr.ChallengeScalar("c")
+	}
+}
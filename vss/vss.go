@@ -0,0 +1,376 @@
+// Package vss implements Shamir secret sharing over the scalar field of a twisted Edwards curve
+// with Feldman and Pedersen verifiable secret sharing and share refresh and resharing.
+//
+// A Fast Approach to Verifiable Secret Sharing, Feldman
+// Non-Interactive and Information-Theoretic Secure Verifiable Secret Sharing, Pedersen
+// Proactive Secret Sharing, Herzberg, Jarecki, Krawczyk, Yung
+package vss
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+
+	"github.com/kilic/go-jubjub"
+	"golang.org/x/crypto/blake2b"
+)
+
+type VSS struct {
+	curve       *jubjub.Curve
+	scalarField *jubjub.ScalarField
+	// G is the curve generator and H is the second generator of Pedersen commitments
+	// whose discrete logarithm to G is unknown
+	h *jubjub.ExtendedPoint
+}
+
+// Share of participant Index which is non zero,
+// Blinding is the share of the blinding polynomial in Pedersen sharing
+type Share struct {
+	Index    uint32
+	Value    *jubjub.ScalarFieldElement
+	Blinding *jubjub.ScalarFieldElement
+}
+
+// Coefficients of a polynomial, the first one is the shared secret
+type Polynomial []*jubjub.ScalarFieldElement
+
+// H is derived by hashing to the curve
+func New(curve *jubjub.Curve) *VSS {
+	return NewWithGenerator(curve, hashToPoint(curve, "go-jubjub_VSS_H"))
+}
+
+// H is expected to be in the prime order subgroup with an unknown discrete logarithm to G
+func NewWithGenerator(curve *jubjub.Curve, h *jubjub.ExtendedPoint) *VSS {
+	return &VSS{
+		curve:       curve,
+		scalarField: jubjub.NewScalarField(curve.Order()),
+		h:           new(jubjub.ExtendedPoint).Set(h),
+	}
+}
+
+// Try and increment, [h] P for the first P whose compressed encoding is BLAKE2b-256(tag || i)
+// such that the result is not the identity
+func hashToPoint(curve *jubjub.Curve, tag string) *jubjub.ExtendedPoint {
+	cofactor := jubjub.NewScalarField(curve.Order()).NewElementFromBig(curve.Cofactor())
+	var i [4]byte
+	for c := uint32(0); ; c++ {
+		binary.LittleEndian.PutUint32(i[:], c)
+		h := blake2b.Sum256(append([]byte(tag), i[:]...))
+		p, err := curve.NewExtendedPointFromCompressed(h[:])
+		if err != nil {
+			continue
+		}
+		curve.Mul(p, p, cofactor)
+		if !p.Eq(curve.NewExtendedPoint()) {
+			return p
+		}
+	}
+}
+
+func (v *VSS) H() *jubjub.ExtendedPoint {
+	return new(jubjub.ExtendedPoint).Set(v.h)
+}
+
+// Returns a uniformly random scalar
+func (v *VSS) RandScalar(rand io.Reader) (*jubjub.ScalarFieldElement, error) {
+	return v.scalarField.RandElement(rand)
+}
+
+// Returns a random polynomial of the given degree with secret as its constant term
+func (v *VSS) RandPolynomial(rand io.Reader, secret *jubjub.ScalarFieldElement, degree int) (Polynomial, error) {
+	p := Polynomial{v.scalarField.NewElementFromBig(secret.Big())}
+	for i := 0; i < degree; i++ {
+		a, err := v.RandScalar(rand)
+		if err != nil {
+			return nil, err
+		}
+		p = append(p, a)
+	}
+	return p, nil
+}
+
+// Evaluates p at x with Horner's rule
+func (v *VSS) Eval(p Polynomial, x uint32) *jubjub.ScalarFieldElement {
+	xs := v.scalarField.NewElementFromUint64(uint64(x))
+	r := v.scalarField.NewElement()
+	for i := len(p) - 1; i >= 0; i-- {
+		v.scalarField.Mul(r, r, xs)
+		v.scalarField.Add(r, r, p[i])
+	}
+	return r
+}
+
+// Returns [a_i]G for coefficients of p
+func (v *VSS) Commit(p Polynomial) []*jubjub.ExtendedPoint {
+	c := make([]*jubjub.ExtendedPoint, len(p))
+	for i, a := range p {
+		c[i] = v.curve.NewExtendedPoint()
+		v.curve.MulBase(c[i], a)
+	}
+	return c
+}
+
+func checkParams(threshold, n int) error {
+	if threshold < 1 || threshold > n || int64(n) > 1<<32-1 {
+		return fmt.Errorf("bad threshold or number of shares")
+	}
+	return nil
+}
+
+// Shamir secret sharing, any threshold of the n shares recover secret
+func (v *VSS) Split(rand io.Reader, secret *jubjub.ScalarFieldElement, threshold, n int) ([]*Share, Polynomial, error) {
+	if err := checkParams(threshold, n); err != nil {
+		return nil, nil, err
+	}
+	p, err := v.RandPolynomial(rand, secret, threshold-1)
+	if err != nil {
+		return nil, nil, err
+	}
+	shares := make([]*Share, n)
+	for i := range shares {
+		shares[i] = &Share{Index: uint32(i + 1), Value: v.Eval(p, uint32(i+1))}
+	}
+	return shares, p, nil
+}
+
+// Returns the Lagrange coefficient of index i at x over indices
+// lambda_i(x) = prod_{j != i} (x - x_j) / (x_i - x_j)
+func (v *VSS) LagrangeCoefficient(indices []uint32, i, x uint32) (*jubjub.ScalarFieldElement, error) {
+	f := v.scalarField
+	xi, xs := f.NewElementFromUint64(uint64(i)), f.NewElementFromUint64(uint64(x))
+	num, den := f.NewElementFromUint64(1), f.NewElementFromUint64(1)
+	t := f.NewElement()
+	found := false
+	for _, j := range indices {
+		if j == i {
+			if found {
+				return nil, fmt.Errorf("duplicate index %d", j)
+			}
+			found = true
+			continue
+		}
+		xj := f.NewElementFromUint64(uint64(j))
+		f.Sub(t, xs, xj)
+		f.Mul(num, num, t)
+		f.Sub(t, xi, xj)
+		f.Mul(den, den, t)
+	}
+	if !found {
+		return nil, fmt.Errorf("index %d is not in indices", i)
+	}
+	if den.Big().Sign() == 0 {
+		return nil, fmt.Errorf("duplicate index")
+	}
+	f.Inv(den, den)
+	f.Mul(num, num, den)
+	return num, nil
+}
+
+func shareIndices(shares []*Share) ([]uint32, error) {
+	indices := make([]uint32, len(shares))
+	for i, s := range shares {
+		if s.Index == 0 {
+			return nil, fmt.Errorf("zero share index")
+		}
+		indices[i] = s.Index
+	}
+	return indices, nil
+}
+
+// Evaluates the polynomial through shares at x
+func (v *VSS) Interpolate(shares []*Share, x uint32) (*jubjub.ScalarFieldElement, error) {
+	indices, err := shareIndices(shares)
+	if err != nil {
+		return nil, err
+	}
+	r := v.scalarField.NewElement()
+	for _, s := range shares {
+		l, err := v.LagrangeCoefficient(indices, s.Index, x)
+		if err != nil {
+			return nil, err
+		}
+		v.scalarField.Mul(l, l, s.Value)
+		v.scalarField.Add(r, r, l)
+	}
+	return r, nil
+}
+
+// Recovers the secret from at least threshold shares
+func (v *VSS) Combine(shares []*Share) (*jubjub.ScalarFieldElement, error) {
+	if len(shares) == 0 {
+		return nil, fmt.Errorf("no shares")
+	}
+	return v.Interpolate(shares, 0)
+}
+
+// Evaluates commitments to coefficients at x, sum_j [x^j] c_j
+func (v *VSS) EvalCommitment(c []*jubjub.ExtendedPoint, x uint32) *jubjub.ExtendedPoint {
+	powers := make([]*jubjub.ScalarFieldElement, len(c))
+	xs := v.scalarField.NewElementFromUint64(uint64(x))
+	for j := range powers {
+		powers[j] = v.scalarField.NewElementFromUint64(1)
+		if j > 0 {
+			v.scalarField.Mul(powers[j], powers[j-1], xs)
+		}
+	}
+	r := v.curve.NewExtendedPoint()
+	v.curve.MultiExp(r, c, powers)
+	return r
+}
+
+// Feldman VSS, commitments are [a_j]G for coefficients of the sharing polynomial
+func (v *VSS) FeldmanSplit(rand io.Reader, secret *jubjub.ScalarFieldElement, threshold, n int) ([]*Share, []*jubjub.ExtendedPoint, error) {
+	shares, p, err := v.Split(rand, secret, threshold, n)
+	if err != nil {
+		return nil, nil, err
+	}
+	return shares, v.Commit(p), nil
+}
+
+// Reports whether [s_i]G = sum_j [i^j] C_j
+func (v *VSS) FeldmanVerify(share *Share, commitments []*jubjub.ExtendedPoint) bool {
+	if share.Index == 0 || len(commitments) == 0 {
+		return false
+	}
+	p := v.curve.NewExtendedPoint()
+	v.curve.MulBase(p, share.Value)
+	return p.Eq(v.EvalCommitment(commitments, share.Index))
+}
+
+// Pedersen VSS, commitments are [a_j]G + [b_j]H for coefficients of the sharing and blinding polynomials,
+// commitments don't reveal [secret]G
+func (v *VSS) PedersenSplit(rand io.Reader, secret *jubjub.ScalarFieldElement, threshold, n int) ([]*Share, []*jubjub.ExtendedPoint, error) {
+	shares, p, err := v.Split(rand, secret, threshold, n)
+	if err != nil {
+		return nil, nil, err
+	}
+	b, err := v.RandScalar(rand)
+	if err != nil {
+		return nil, nil, err
+	}
+	blinding, err := v.RandPolynomial(rand, b, threshold-1)
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, s := range shares {
+		s.Blinding = v.Eval(blinding, s.Index)
+	}
+	commitments := v.Commit(p)
+	t := v.curve.NewExtendedPoint()
+	for j := range commitments {
+		v.curve.Mul(t, v.h, blinding[j])
+		v.curve.Add(commitments[j], commitments[j], t)
+	}
+	return shares, commitments, nil
+}
+
+// Reports whether [s_i]G + [b_i]H = sum_j [i^j] C_j
+func (v *VSS) PedersenVerify(share *Share, commitments []*jubjub.ExtendedPoint) bool {
+	if share.Index == 0 || share.Blinding == nil || len(commitments) == 0 {
+		return false
+	}
+	p := v.curve.NewExtendedPoint()
+	v.curve.MultiExp(p, []*jubjub.ExtendedPoint{v.curve.Generator(), v.h}, []*jubjub.ScalarFieldElement{share.Value, share.Blinding})
+	return p.Eq(v.EvalCommitment(commitments, share.Index))
+}
+
+// Proactive refresh, each participant deals a Feldman sharing of zero
+// and every participant adds the received updates to its share.
+// The secret and the threshold are unchanged, old shares become useless with new ones.
+func (v *VSS) RefreshSplit(rand io.Reader, threshold, n int) ([]*Share, []*jubjub.ExtendedPoint, error) {
+	return v.FeldmanSplit(rand, v.scalarField.NewElement(), threshold, n)
+}
+
+// Applies verified refresh updates to share and updated commitments to commitments
+func (v *VSS) Refresh(share *Share, commitments []*jubjub.ExtendedPoint, updates []*Share, updateCommitments [][]*jubjub.ExtendedPoint) (*Share, []*jubjub.ExtendedPoint, error) {
+	if len(updates) != len(updateCommitments) {
+		return nil, nil, fmt.Errorf("updates and commitments must have the same length")
+	}
+	refreshed := &Share{Index: share.Index, Value: v.scalarField.NewElementFromBig(share.Value.Big())}
+	c := make([]*jubjub.ExtendedPoint, len(commitments))
+	for j := range c {
+		c[j] = new(jubjub.ExtendedPoint).Set(commitments[j])
+	}
+	for i, u := range updates {
+		uc := updateCommitments[i]
+		if u.Index != share.Index || len(uc) != len(commitments) {
+			return nil, nil, fmt.Errorf("bad update %d", i)
+		}
+		if !uc[0].Eq(v.curve.NewExtendedPoint()) {
+			return nil, nil, fmt.Errorf("update %d is not a sharing of zero", i)
+		}
+		if !v.FeldmanVerify(u, uc) {
+			return nil, nil, fmt.Errorf("bad update share %d", i)
+		}
+		v.scalarField.Add(refreshed.Value, refreshed.Value, u.Value)
+		for j := range c {
+			v.curve.Add(c[j], c[j], uc[j])
+		}
+	}
+	return refreshed, c, nil
+}
+
+// Resharing to a new set of participants with a new threshold,
+// a qualified set of old participants each deal a Feldman sharing of their share
+func (v *VSS) Reshare(rand io.Reader, share *Share, threshold, n int) ([]*Share, []*jubjub.ExtendedPoint, error) {
+	return v.FeldmanSplit(rand, share.Value, threshold, n)
+}
+
+// Reports whether the resharing commitments of old participant dealer commit to its share
+// under commitments of the old sharing
+func (v *VSS) VerifyReshare(commitments []*jubjub.ExtendedPoint, dealer uint32, reshareCommitments []*jubjub.ExtendedPoint) bool {
+	if dealer == 0 || len(commitments) == 0 || len(reshareCommitments) == 0 {
+		return false
+	}
+	return reshareCommitments[0].Eq(v.EvalCommitment(commitments, dealer))
+}
+
+// Combines sub shares a new participant received from old participants dealers,
+// s'_j = sum_i lambda_i(0) s_ij, the secret is unchanged
+func (v *VSS) CombineReshares(dealers []uint32, subShares []*Share) (*Share, error) {
+	if len(dealers) != len(subShares) || len(subShares) == 0 {
+		return nil, fmt.Errorf("dealers and sub shares must have the same non zero length")
+	}
+	share := &Share{Index: subShares[0].Index, Value: v.scalarField.NewElement()}
+	for i, s := range subShares {
+		if s.Index != share.Index {
+			return nil, fmt.Errorf("sub shares are of different participants")
+		}
+		l, err := v.LagrangeCoefficient(dealers, dealers[i], 0)
+		if err != nil {
+			return nil, err
+		}
+		v.scalarField.Mul(l, l, s.Value)
+		v.scalarField.Add(share.Value, share.Value, l)
+	}
+	return share, nil
+}
+
+// Returns commitments of the new sharing, C'_k = sum_i [lambda_i(0)] C_ik
+func (v *VSS) CombineReshareCommitments(dealers []uint32, reshareCommitments [][]*jubjub.ExtendedPoint) ([]*jubjub.ExtendedPoint, error) {
+	if len(dealers) != len(reshareCommitments) || len(dealers) == 0 {
+		return nil, fmt.Errorf("dealers and commitments must have the same non zero length")
+	}
+	lambdas := make([]*jubjub.ScalarFieldElement, len(dealers))
+	for i, d := range dealers {
+		var err error
+		if lambdas[i], err = v.LagrangeCoefficient(dealers, d, 0); err != nil {
+			return nil, err
+		}
+	}
+	c := make([]*jubjub.ExtendedPoint, len(reshareCommitments[0]))
+	points := make([]*jubjub.ExtendedPoint, len(dealers))
+	for k := range c {
+		for i, rc := range reshareCommitments {
+			if len(rc) != len(c) {
+				return nil, fmt.Errorf("commitments are of different thresholds")
+			}
+			points[i] = rc[k]
+		}
+		c[k] = v.curve.NewExtendedPoint()
+		if err := v.curve.MultiExp(c[k], points, lambdas); err != nil {
+			return nil, err
+		}
+	}
+	return c, nil
+}
@@ -0,0 +1,230 @@
+package vss
+
+import (
+	"crypto/rand"
+	"testing"
+
+	"github.com/kilic/go-jubjub"
+)
+
+func TestShamir(t *testing.T) {
+	v := New(jubjub.NewJubjub())
+	secret, _ := v.RandScalar(rand.Reader)
+	shares, p, err := v.Split(rand.Reader, secret, 3, 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(p) != 3 || p[0].Big().Cmp(secret.Big()) != 0 {
+		t.Fatalf("bad sharing polynomial")
+	}
+	for _, set := range [][]int{{0, 1, 2}, {4, 2, 0}, {0, 1, 2, 3, 4}} {
+		var subset []*Share
+		for _, i := range set {
+			subset = append(subset, shares[i])
+		}
+		r, err := v.Combine(subset)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if r.Big().Cmp(secret.Big()) != 0 {
+			t.Errorf("bad combined secret of shares %v", set)
+		}
+		// interpolation at an index gives its share
+		s, err := v.Interpolate(subset, 2)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if s.Big().Cmp(shares[1].Value.Big()) != 0 {
+			t.Errorf("bad interpolated share of shares %v", set)
+		}
+	}
+	r, _ := v.Combine(shares[:2])
+	if r.Big().Cmp(secret.Big()) == 0 {
+		t.Errorf("expected less than threshold shares to not recover the secret")
+	}
+	if _, err := v.Combine([]*Share{shares[0], shares[0]}); err == nil {
+		t.Errorf("expected error for duplicate shares")
+	}
+	if _, err := v.Combine([]*Share{{Index: 0, Value: secret}}); err == nil {
+		t.Errorf("expected error for zero index")
+	}
+	if _, _, err := v.Split(rand.Reader, secret, 6, 5); err == nil {
+		t.Errorf("expected error for bad threshold")
+	}
+}
+
+func TestFeldman(t *testing.T) {
+	for _, curve := range []*jubjub.Curve{jubjub.NewJubjub(), jubjub.NewBandersnatch()} {
+		v := New(curve)
+		secret, _ := v.RandScalar(rand.Reader)
+		shares, c, err := v.FeldmanSplit(rand.Reader, secret, 3, 5)
+		if err != nil {
+			t.Fatal(err)
+		}
+		pub := curve.NewExtendedPoint()
+		curve.MulBase(pub, secret)
+		if !c[0].Eq(pub) {
+			t.Errorf("bad commitment to the secret")
+		}
+		for _, s := range shares {
+			if !v.FeldmanVerify(s, c) {
+				t.Errorf("bad share %d", s.Index)
+			}
+		}
+		bad := &Share{Index: 1, Value: shares[1].Value}
+		if v.FeldmanVerify(bad, c) {
+			t.Errorf("expected share verification to fail")
+		}
+	}
+}
+
+func TestPedersen(t *testing.T) {
+	curve := jubjub.NewJubjub()
+	v := New(curve)
+	if !curve.IsInSubgroup(v.H()) || v.H().Eq(curve.NewExtendedPoint()) {
+		t.Fatalf("bad second generator")
+	}
+	secret, _ := v.RandScalar(rand.Reader)
+	shares, c, err := v.PedersenSplit(rand.Reader, secret, 2, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, s := range shares {
+		if !v.PedersenVerify(s, c) {
+			t.Errorf("bad share %d", s.Index)
+		}
+	}
+	pub := curve.NewExtendedPoint()
+	curve.MulBase(pub, secret)
+	if c[0].Eq(pub) {
+		t.Errorf("commitment expected to hide the secret")
+	}
+	bad := &Share{Index: 1, Value: shares[0].Value, Blinding: shares[1].Blinding}
+	if v.PedersenVerify(bad, c) {
+		t.Errorf("expected share verification to fail")
+	}
+	if v.PedersenVerify(&Share{Index: 1, Value: shares[0].Value}, c) {
+		t.Errorf("expected share verification to fail without blinding")
+	}
+	r, err := v.Combine(shares[1:3])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Big().Cmp(secret.Big()) != 0 {
+		t.Errorf("bad combined secret")
+	}
+}
+
+func TestRefresh(t *testing.T) {
+	v := New(jubjub.NewJubjub())
+	secret, _ := v.RandScalar(rand.Reader)
+	const threshold, n = 2, 3
+	shares, c, err := v.FeldmanSplit(rand.Reader, secret, threshold, n)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// updates[i][j] is the update dealt by i to j
+	updates := make([][]*Share, n)
+	updateCommitments := make([][]*jubjub.ExtendedPoint, n)
+	for i := range updates {
+		if updates[i], updateCommitments[i], err = v.RefreshSplit(rand.Reader, threshold, n); err != nil {
+			t.Fatal(err)
+		}
+	}
+	refreshed := make([]*Share, n)
+	var commitments []*jubjub.ExtendedPoint
+	for j := range refreshed {
+		var received []*Share
+		for i := range updates {
+			received = append(received, updates[i][j])
+		}
+		if refreshed[j], commitments, err = v.Refresh(shares[j], c, received, updateCommitments); err != nil {
+			t.Fatal(err)
+		}
+		if !v.FeldmanVerify(refreshed[j], commitments) {
+			t.Errorf("bad refreshed share %d", j)
+		}
+		if refreshed[j].Value.Big().Cmp(shares[j].Value.Big()) == 0 {
+			t.Errorf("share %d expected to change", j)
+		}
+	}
+	r, _ := v.Combine(refreshed[1:])
+	if r.Big().Cmp(secret.Big()) != 0 {
+		t.Errorf("bad combined secret after refresh")
+	}
+	// old and new shares don't combine
+	r, _ = v.Combine([]*Share{shares[0], refreshed[1]})
+	if r.Big().Cmp(secret.Big()) == 0 {
+		t.Errorf("expected mixed shares to not recover the secret")
+	}
+	// an update that shares a non zero value is rejected
+	bad, badCommitments, _ := v.FeldmanSplit(rand.Reader, secret, threshold, n)
+	if _, _, err := v.Refresh(shares[0], c, []*Share{bad[0]}, [][]*jubjub.ExtendedPoint{badCommitments}); err == nil {
+		t.Errorf("expected error for non zero update")
+	}
+}
+
+func TestReshare(t *testing.T) {
+	v := New(jubjub.NewJubjub())
+	secret, _ := v.RandScalar(rand.Reader)
+	shares, c, err := v.FeldmanSplit(rand.Reader, secret, 2, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// old participants 1 and 3 reshare to 5 new participants with threshold 3
+	dealers := []uint32{1, 3}
+	subShares := make([][]*Share, len(dealers))
+	reshareCommitments := make([][]*jubjub.ExtendedPoint, len(dealers))
+	for i, d := range dealers {
+		if subShares[i], reshareCommitments[i], err = v.Reshare(rand.Reader, shares[d-1], 3, 5); err != nil {
+			t.Fatal(err)
+		}
+		if !v.VerifyReshare(c, d, reshareCommitments[i]) {
+			t.Errorf("bad reshare of dealer %d", d)
+		}
+	}
+	if v.VerifyReshare(c, 2, reshareCommitments[0]) {
+		t.Errorf("expected reshare verification to fail for another dealer")
+	}
+	newCommitments, err := v.CombineReshareCommitments(dealers, reshareCommitments)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !newCommitments[0].Eq(c[0]) {
+		t.Errorf("bad commitment to the secret after resharing")
+	}
+	newShares := make([]*Share, 5)
+	for j := range newShares {
+		var received []*Share
+		for i := range dealers {
+			if !v.FeldmanVerify(subShares[i][j], reshareCommitments[i]) {
+				t.Errorf("bad sub share %d of dealer %d", j, dealers[i])
+			}
+			received = append(received, subShares[i][j])
+		}
+		if newShares[j], err = v.CombineReshares(dealers, received); err != nil {
+			t.Fatal(err)
+		}
+		if !v.FeldmanVerify(newShares[j], newCommitments) {
+			t.Errorf("bad new share %d", j)
+		}
+	}
+	r, _ := v.Combine(newShares[2:])
+	if r.Big().Cmp(secret.Big()) != 0 {
+		t.Errorf("bad combined secret after resharing")
+	}
+	r, _ = v.Combine(newShares[:2])
+	if r.Big().Cmp(secret.Big()) == 0 {
+		t.Errorf("expected less than the new threshold to not recover the secret")
+	}
+}
+
+func BenchmarkFeldmanVerify(t *testing.B) {
+	v := New(jubjub.NewJubjub())
+	secret, _ := v.RandScalar(rand.Reader)
+	shares, c, _ := v.FeldmanSplit(rand.Reader, secret, 10, 20)
+	t.ResetTimer()
+	for i := 0; i < t.N; i++ {
+		v.FeldmanVerify(shares[i%20], c)
+	}
+}